@@ -0,0 +1,326 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/ankitpokhrel/shopctl/pkg/gql/client"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+// ProductsBulkQuery builds a bulk query to fetch products along with their variants,
+// inventory levels, media and metafields. Nested records of a product are returned as
+// separate lines in the result with the ID of their parent in the `__parentId` field.
+func ProductsBulkQuery(query *string) string {
+	var args string
+	if query != nil && *query != "" {
+		q, _ := json.Marshal(*query)
+		args = fmt.Sprintf("(query: %s)", q)
+	}
+
+	return fmt.Sprintf(`{
+  products%s {
+    edges {
+      node {
+        %s
+        variantsCount {
+          count
+        }
+        mediaCount {
+          count
+        }
+        variants {
+          edges {
+            node {
+              %s
+              inventoryItem {
+                inventoryLevels {
+                  edges {
+                    node {
+                      id
+                      location {
+                        id
+                        name
+                      }
+                      quantities(names: ["available", "on_hand"]) {
+                        name
+                        quantity
+                      }
+                    }
+                  }
+                }
+              }
+            }
+          }
+        }
+        media {
+          edges {
+            node {
+              %s
+            }
+          }
+        }
+        metafields {
+          edges {
+            node {
+              %s
+            }
+          }
+        }
+      }
+    }
+  }
+}`, args, fieldsProduct, fieldsVariant, fieldsMedia, fieldsMetafields)
+}
+
+// ProductSetBulkMutation is the mutation to restore products with a bulk operation.
+// Each line of the staged variables file holds the variables of one product.
+const ProductSetBulkMutation = `mutation productSet($input: ProductSetInput!, $identifier: ProductSetIdentifiers) {
+  productSet(input: $input, identifier: $identifier) {
+    product {
+      id
+      createdAt
+      variants(first: 250) {
+        nodes {
+          id
+          title
+          sku
+          selectedOptions {
+            name
+            value
+          }
+        }
+      }
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}`
+
+// RunBulkQuery submits a bulk query operation.
+func (c GQLClient) RunBulkQuery(ctx context.Context, query string) (*schema.BulkOperation, error) {
+	var out struct {
+		Data struct {
+			BulkOperationRunQuery BulkOperationResponse `json:"bulkOperationRunQuery"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	mutation := `
+	mutation bulkOperationRunQuery($query: String!) {
+		bulkOperationRunQuery(query: $query) {
+			bulkOperation {
+				id
+				status
+				createdAt
+			}
+			userErrors {
+				field
+				message
+			}
+		}
+	}`
+
+	req := client.GQLRequest{
+		Query:     mutation,
+		Variables: client.QueryVars{"query": query},
+	}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("bulkOperationRunQuery: the operation failed with error: %s", out.Errors.Error())
+	}
+	if len(out.Data.BulkOperationRunQuery.UserErrors) > 0 {
+		return nil, fmt.Errorf("bulkOperationRunQuery: the operation failed with user error: %s", out.Data.BulkOperationRunQuery.UserErrors.Error())
+	}
+	return out.Data.BulkOperationRunQuery.BulkOperation, nil
+}
+
+// GetBulkOperation fetches the status of a bulk operation.
+func (c GQLClient) GetBulkOperation(ctx context.Context, id string) (*schema.BulkOperation, error) {
+	var out struct {
+		Data struct {
+			Node *schema.BulkOperation `json:"node"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	query := fmt.Sprintf(`query GetBulkOperation($id: ID!) {
+  node(id: $id) {
+    ... on BulkOperation {
+      %s
+    }
+  }
+}`, fieldsBulkOperation)
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"id": id},
+	}
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": id}, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("%s", out.Errors)
+	}
+	if out.Data.Node == nil {
+		return nil, fmt.Errorf("bulk operation %s not found", id)
+	}
+	return out.Data.Node, nil
+}
+
+// DownloadBulkResult downloads the JSONL result of a bulk operation.
+// The caller is responsible for closing the returned reader.
+func (c GQLClient) DownloadBulkResult(ctx context.Context, url string) (io.ReadCloser, error) {
+	res, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download bulk operation result: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		_ = res.Body.Close()
+		return nil, fmt.Errorf("unable to download bulk operation result: unexpected status code: %d", res.StatusCode)
+	}
+	return res.Body, nil
+}
+
+// RunBulkMutation submits a bulk mutation operation with the variables staged at the given path.
+func (c GQLClient) RunBulkMutation(ctx context.Context, mutation string, stagedUploadPath string) (*schema.BulkOperation, error) {
+	var out struct {
+		Data struct {
+			BulkOperationRunMutation BulkOperationResponse `json:"bulkOperationRunMutation"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	query := `
+	mutation bulkOperationRunMutation($mutation: String!, $stagedUploadPath: String!) {
+		bulkOperationRunMutation(mutation: $mutation, stagedUploadPath: $stagedUploadPath) {
+			bulkOperation {
+				id
+				status
+				createdAt
+			}
+			userErrors {
+				field
+				message
+			}
+		}
+	}`
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"mutation": mutation, "stagedUploadPath": stagedUploadPath},
+	}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("bulkOperationRunMutation: the operation failed with error: %s", out.Errors.Error())
+	}
+	if len(out.Data.BulkOperationRunMutation.UserErrors) > 0 {
+		return nil, fmt.Errorf("bulkOperationRunMutation: the operation failed with user error: %s", out.Data.BulkOperationRunMutation.UserErrors.Error())
+	}
+	return out.Data.BulkOperationRunMutation.BulkOperation, nil
+}
+
+// CreateStagedUpload creates a target to upload a file to.
+func (c GQLClient) CreateStagedUpload(ctx context.Context, input schema.StagedUploadInput) (*StagedUploadTarget, error) {
+	var out struct {
+		Data struct {
+			StagedUploadsCreate StagedUploadsCreateResponse `json:"stagedUploadsCreate"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	query := `
+	mutation stagedUploadsCreate($input: [StagedUploadInput!]!) {
+		stagedUploadsCreate(input: $input) {
+			stagedTargets {
+				url
+				resourceUrl
+				parameters {
+					name
+					value
+				}
+			}
+			userErrors {
+				field
+				message
+			}
+		}
+	}`
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"input": []schema.StagedUploadInput{input}},
+	}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("stagedUploadsCreate: the operation failed with error: %s", out.Errors.Error())
+	}
+	if len(out.Data.StagedUploadsCreate.UserErrors) > 0 {
+		return nil, fmt.Errorf("stagedUploadsCreate: the operation failed with user error: %s", out.Data.StagedUploadsCreate.UserErrors.Error())
+	}
+	if len(out.Data.StagedUploadsCreate.StagedTargets) == 0 {
+		return nil, fmt.Errorf("stagedUploadsCreate: no upload target was created")
+	}
+	return &out.Data.StagedUploadsCreate.StagedTargets[0], nil
+}
+
+// Upload uploads the file of the given size to the staged target with a multipart form POST
+// request. The content is streamed so that large files are not read into the memory.
+func (c GQLClient) Upload(ctx context.Context, target *StagedUploadTarget, filename string, content io.Reader, size int64) error {
+	var (
+		form bytes.Buffer
+		mw   = multipart.NewWriter(&form)
+	)
+
+	// The file needs to be the last field of the form.
+	for _, p := range target.Parameters {
+		if err := mw.WriteField(p.Name, p.Value); err != nil {
+			return err
+		}
+	}
+	if _, err := mw.CreateFormFile("file", filename); err != nil {
+		return err
+	}
+	head := form.Len()
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	// The form is sent with the file in between its fields and the closing boundary
+	// so that the length of the body is known without buffering the file.
+	body := io.MultiReader(
+		bytes.NewReader(form.Bytes()[:head]),
+		io.LimitReader(content, size),
+		bytes.NewReader(form.Bytes()[head:]),
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(form.Len()) + size
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	res, err := (&http.Client{Transport: client.DefaultTransport}).Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to upload %s: %w", filename, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unable to upload %s: unexpected status code: %d", filename, res.StatusCode)
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 type GQLClient struct {
 	*client.Client
 	logger *tlog.Logger
+	server string
 }
 
 // GQLClientFunc is a functional opt for GQLClient.
@@ -54,7 +55,10 @@ func NewGQLClient(ctx *config.StoreContext, opts ...GQLClientFunc) *GQLClient {
 	if c.logger == nil {
 		c.logger = tlog.New(tlog.VerboseLevel(tlog.VL1), false)
 	}
-	c.Client = client.NewClient(server, token, client.WithLogger(c.logger))
+	if c.server == "" {
+		c.server = server
+	}
+	c.Client = client.NewClient(c.server, token, client.WithLogger(c.logger))
 
 	return &c
 }
@@ -65,3 +69,10 @@ func LogRequest(lgr *tlog.Logger) GQLClientFunc {
 		c.logger = lgr
 	}
 }
+
+// WithServer sets a custom GraphQL endpoint for the client.
+func WithServer(server string) GQLClientFunc {
+	return func(c *GQLClient) {
+		c.server = server
+	}
+}
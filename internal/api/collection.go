@@ -0,0 +1,277 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ankitpokhrel/shopctl/pkg/gql/client"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+// CheckCollectionByID fetches a collection by ID without additional details.
+func (c GQLClient) CheckCollectionByID(ctx context.Context, id string) (*schema.Collection, error) {
+	var (
+		query = `query CheckCollectionByID($id: ID!) { collection(id: $id) { id handle } }`
+
+		out *CollectionResponse
+	)
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"id": id},
+	}
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": id}, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("%s", out.Errors)
+	}
+	return &out.Data.Collection, nil
+}
+
+// CheckCollectionByHandle fetches a collection by handle without additional details.
+func (c GQLClient) CheckCollectionByHandle(ctx context.Context, handle string) (*schema.Collection, error) {
+	var out struct {
+		Data struct {
+			Collection schema.Collection `json:"collectionByIdentifier"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	query := `query GetCollectionByHandle($identifier: CollectionIdentifierInput!) {
+  collectionByIdentifier(identifier: $identifier) {
+    id
+    handle
+    ruleSet {
+      appliedDisjunctively
+    }
+  }
+}`
+
+	req := client.GQLRequest{
+		Query: query,
+		Variables: client.QueryVars{
+			"identifier": map[string]string{"handle": handle},
+		},
+	}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("%s", out.Errors)
+	}
+	return &out.Data.Collection, nil
+}
+
+// GetAllCollections fetches collections in a batch and streams the response to a channel.
+func (c GQLClient) GetAllCollections(ctx context.Context, ch chan *CollectionsResponse, limit int, after *string, query *string) error {
+	var out *CollectionsResponse
+
+	collectionsQuery := fmt.Sprintf(`query GetCollections($first: Int!, $after: String, $query: String) {
+  collections(first: $first, after: $after, query: $query) {
+    nodes {
+      %s
+    }
+    pageInfo {
+      hasNextPage
+      endCursor
+    }
+  }
+}`, fieldsCollection)
+
+	req := client.GQLRequest{
+		Query: collectionsQuery,
+		Variables: client.QueryVars{
+			"first": limit,
+			"after": after,
+			"query": query,
+		},
+	}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return err
+	}
+	if len(out.Errors) > 0 {
+		return fmt.Errorf("%s", out.Errors)
+	}
+
+	ch <- out
+
+	if out.Data.Collections.PageInfo.HasNextPage {
+		return c.GetAllCollections(ctx, ch, limit, out.Data.Collections.PageInfo.EndCursor, query)
+	}
+	return nil
+}
+
+// GetCollectionProducts fetches all products of a collection in the collection sort order.
+//
+// Collections can have any number of products, so unlike other child resources
+// products are fetched page by page and merged into a single response.
+func (c GQLClient) GetCollectionProducts(ctx context.Context, collectionID string) (*CollectionProductsData, error) {
+	const limit = 250
+
+	query := `query GetCollectionProducts($id: ID!, $first: Int!, $after: String) {
+  collection(id: $id) {
+    id
+    products(first: $first, after: $after) {
+      nodes {
+        id
+        handle
+      }
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+    }
+  }
+}`
+
+	var (
+		after *string
+		data  = CollectionProductsData{CollectionID: collectionID}
+	)
+	for {
+		var out *CollectionProductsResponse
+
+		req := client.GQLRequest{
+			Query: query,
+			Variables: client.QueryVars{
+				"id":    collectionID,
+				"first": limit,
+				"after": after,
+			},
+		}
+		if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": collectionID}, &out); err != nil {
+			return nil, err
+		}
+		if len(out.Errors) > 0 {
+			return nil, fmt.Errorf("%s", out.Errors)
+		}
+
+		products := out.Data.Collection.Products
+		data.Products.Nodes = append(data.Products.Nodes, products.Nodes...)
+
+		if !products.PageInfo.HasNextPage {
+			break
+		}
+		after = products.PageInfo.EndCursor
+	}
+	return &data, nil
+}
+
+// CreateCollection creates a collection.
+func (c GQLClient) CreateCollection(ctx context.Context, input schema.CollectionInput) (*CollectionSyncResponse, error) {
+	var out struct {
+		Data struct {
+			CollectionCreate CollectionSyncResponse `json:"collectionCreate"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	query := `
+	mutation collectionCreate($input: CollectionInput!) {
+		collectionCreate(input: $input) {
+			collection {
+				id
+			}
+			userErrors {
+				field
+				message
+			}
+		}
+	}`
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"input": input},
+	}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("collectionCreate: the operation failed with error: %s", out.Errors.Error())
+	}
+	if len(out.Data.CollectionCreate.UserErrors) > 0 {
+		return nil, fmt.Errorf("collectionCreate: the operation failed with user error: %s", out.Data.CollectionCreate.UserErrors.Error())
+	}
+	return &out.Data.CollectionCreate, nil
+}
+
+// UpdateCollection updates a collection.
+func (c GQLClient) UpdateCollection(ctx context.Context, input schema.CollectionInput) (*CollectionSyncResponse, error) {
+	var out struct {
+		Data struct {
+			CollectionUpdate CollectionSyncResponse `json:"collectionUpdate"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	query := `
+	mutation collectionUpdate($input: CollectionInput!) {
+		collectionUpdate(input: $input) {
+			collection {
+				id
+			}
+			userErrors {
+				field
+				message
+			}
+		}
+	}`
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"input": input},
+	}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("collectionUpdate: Collection %s: the operation failed with error: %s", *input.ID, out.Errors.Error())
+	}
+	if len(out.Data.CollectionUpdate.UserErrors) > 0 {
+		return nil, fmt.Errorf("collectionUpdate: Collection %s: the operation failed with user error: %s", *input.ID, out.Data.CollectionUpdate.UserErrors.Error())
+	}
+	return &out.Data.CollectionUpdate, nil
+}
+
+// AddProductsToCollection adds products to a custom collection.
+// Shopify accepts at most 250 products per call.
+func (c GQLClient) AddProductsToCollection(ctx context.Context, collectionID string, productIDs []string) (*CollectionSyncResponse, error) {
+	var out struct {
+		Data struct {
+			CollectionAddProducts CollectionSyncResponse `json:"collectionAddProducts"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	query := `
+	mutation collectionAddProducts($id: ID!, $productIds: [ID!]!) {
+		collectionAddProducts(id: $id, productIds: $productIds) {
+			collection {
+				id
+			}
+			userErrors {
+				field
+				message
+			}
+		}
+	}`
+
+	req := client.GQLRequest{
+		Query: query,
+		Variables: client.QueryVars{
+			"id":         collectionID,
+			"productIds": productIDs,
+		},
+	}
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": collectionID}, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("collectionAddProducts: Collection %s: the operation failed with error: %s", collectionID, out.Errors.Error())
+	}
+	if len(out.Data.CollectionAddProducts.UserErrors) > 0 {
+		return nil, fmt.Errorf("collectionAddProducts: Collection %s: the operation failed with user error: %s", collectionID, out.Data.CollectionAddProducts.UserErrors.Error())
+	}
+	return &out.Data.CollectionAddProducts, nil
+}
@@ -9,7 +9,7 @@ import (
 )
 
 // GetCustomerByID fetches customer data by ID.
-func (c GQLClient) GetCustomerByID(id string) (*schema.Customer, error) {
+func (c GQLClient) GetCustomerByID(ctx context.Context, id string) (*schema.Customer, error) {
 	var (
 		query = fmt.Sprintf(`query GetCustomerByID($id: ID!) {
           customer(id: $id) {
@@ -24,7 +24,7 @@ func (c GQLClient) GetCustomerByID(id string) (*schema.Customer, error) {
 		Query:     query,
 		Variables: client.QueryVars{"id": id},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -34,7 +34,7 @@ func (c GQLClient) GetCustomerByID(id string) (*schema.Customer, error) {
 }
 
 // CheckCustomerByEmailOrPhoneOrID fetches a customer by email or phone without additional details.
-func (c GQLClient) CheckCustomerByEmailOrPhoneOrID(email *string, phone *string, id string) (*schema.Customer, error) {
+func (c GQLClient) CheckCustomerByEmailOrPhoneOrID(ctx context.Context, email *string, phone *string, id string) (*schema.Customer, error) {
 	var (
 		query = `query CheckCustomerByEmailOrPhone($query: String!) {
           customers(first: 1, query: $query) {
@@ -67,7 +67,7 @@ func (c GQLClient) CheckCustomerByEmailOrPhoneOrID(email *string, phone *string,
 		Query:     query,
 		Variables: client.QueryVars{"query": exp},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -84,7 +84,7 @@ func (c GQLClient) CheckCustomerByEmailOrPhoneOrID(email *string, phone *string,
 // Shopify limits 200 metafields per customer and the response size seems ok.
 // We'll fetch them all at once for now. We will revisit this if we run
 // into any issue due to the response size.
-func (c GQLClient) GetCustomerMetaFields(customerID string) (*CustomerMetaFieldsResponse, error) {
+func (c GQLClient) GetCustomerMetaFields(ctx context.Context, customerID string) (*CustomerMetaFieldsResponse, error) {
 	var out *CustomerMetaFieldsResponse
 
 	query := fmt.Sprintf(`query GetCustomerMetaFields($id: ID!) {
@@ -104,14 +104,14 @@ func (c GQLClient) GetCustomerMetaFields(customerID string) (*CustomerMetaFields
 		Query:     query,
 		Variables: client.QueryVars{"id": customerID},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
 // GetCustomerMetaFieldsByEmailOrPhoneOrID fetches metafields of a customer by email or phone.
-func (c GQLClient) GetCustomerMetaFieldsByEmailOrPhoneOrID(email *string, phone *string, id string) (*CustomersMetaFieldsResponse, error) {
+func (c GQLClient) GetCustomerMetaFieldsByEmailOrPhoneOrID(ctx context.Context, email *string, phone *string, id string) (*CustomersMetaFieldsResponse, error) {
 	var (
 		query = fmt.Sprintf(`query GetCustomerMetaFieldsByEmailOrPhone($query: String!) {
       customers(first: 1, query: $query) {
@@ -149,7 +149,7 @@ func (c GQLClient) GetCustomerMetaFieldsByEmailOrPhoneOrID(email *string, phone
 		Query:     query,
 		Variables: client.QueryVars{"query": exp},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Data.Customers.Nodes) == 0 {
@@ -159,7 +159,7 @@ func (c GQLClient) GetCustomerMetaFieldsByEmailOrPhoneOrID(email *string, phone
 }
 
 // GetCustomers fetches n number of customers after a cursor.
-func (c GQLClient) GetCustomers(limit int, after *string, query *string) ([]schema.Customer, error) {
+func (c GQLClient) GetCustomers(ctx context.Context, limit int, after *string, query *string) ([]schema.Customer, error) {
 	var out *CustomersResponse
 
 	customersQuery := fmt.Sprintf(`query GetCustomers($first: Int!, $after: String, $query: String, $sortKey: CustomerSortKeys!, $reverse: Boolean!) {
@@ -184,7 +184,7 @@ func (c GQLClient) GetCustomers(limit int, after *string, query *string) ([]sche
 			"reverse": true,
 		},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -194,11 +194,11 @@ func (c GQLClient) GetCustomers(limit int, after *string, query *string) ([]sche
 }
 
 // GetAllCustomers fetches customers in a batch and streams the response to a channel.
-func (c GQLClient) GetAllCustomers(ch chan *CustomersResponse, limit int, after *string) error {
+func (c GQLClient) GetAllCustomers(ctx context.Context, ch chan *CustomersResponse, limit int, after *string, query *string) error {
 	var out *CustomersResponse
 
-	query := fmt.Sprintf(`query GetCustomers($first: Int!, $after: String) {
-  customers(first: $first, after: $after) {
+	customerQuery := fmt.Sprintf(`query GetCustomers($first: Int!, $after: String, $query: String) {
+  customers(first: $first, after: $after, query: $query) {
     nodes {
       %s
     }
@@ -210,13 +210,14 @@ func (c GQLClient) GetAllCustomers(ch chan *CustomersResponse, limit int, after
 }`, fieldsCustomer)
 
 	req := client.GQLRequest{
-		Query: query,
+		Query: customerQuery,
 		Variables: client.QueryVars{
 			"first": limit,
 			"after": after,
+			"query": query,
 		},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return err
 	}
 	if len(out.Errors) > 0 {
@@ -226,13 +227,13 @@ func (c GQLClient) GetAllCustomers(ch chan *CustomersResponse, limit int, after
 	ch <- out
 
 	if out.Data.Customers.PageInfo.HasNextPage {
-		return c.GetAllCustomers(ch, limit, out.Data.Customers.PageInfo.EndCursor)
+		return c.GetAllCustomers(ctx, ch, limit, out.Data.Customers.PageInfo.EndCursor, query)
 	}
 	return nil
 }
 
 // CreateCustomer creates a customer.
-func (c GQLClient) CreateCustomer(input schema.CustomerInput) (*CustomerSyncResponse, error) {
+func (c GQLClient) CreateCustomer(ctx context.Context, input schema.CustomerInput) (*CustomerSyncResponse, error) {
 	var out struct {
 		Data struct {
 			CustomerCreate CustomerSyncResponse `json:"customerCreate"`
@@ -257,7 +258,7 @@ func (c GQLClient) CreateCustomer(input schema.CustomerInput) (*CustomerSyncResp
 		Query:     query,
 		Variables: client.QueryVars{"input": input},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -270,7 +271,7 @@ func (c GQLClient) CreateCustomer(input schema.CustomerInput) (*CustomerSyncResp
 }
 
 // UpdateCustomer updates a customer.
-func (c GQLClient) UpdateCustomer(input schema.CustomerInput) (*CustomerSyncResponse, error) {
+func (c GQLClient) UpdateCustomer(ctx context.Context, input schema.CustomerInput) (*CustomerSyncResponse, error) {
 	var out struct {
 		Data struct {
 			CustomerUpdate CustomerSyncResponse `json:"customerUpdate"`
@@ -295,7 +296,7 @@ func (c GQLClient) UpdateCustomer(input schema.CustomerInput) (*CustomerSyncResp
 		Query:     query,
 		Variables: client.QueryVars{"input": input},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -308,7 +309,7 @@ func (c GQLClient) UpdateCustomer(input schema.CustomerInput) (*CustomerSyncResp
 }
 
 // UpdateCustomerAddress updates a customer address.
-func (c GQLClient) UpdateCustomerAddress(customerID string, addressID string, input schema.MailingAddressInput, isDefault bool) (*CustomerAddressUpdateResponse, error) {
+func (c GQLClient) UpdateCustomerAddress(ctx context.Context, customerID string, addressID string, input schema.MailingAddressInput, isDefault bool) (*CustomerAddressUpdateResponse, error) {
 	var out struct {
 		Data struct {
 			CustomerAddressUpdate CustomerAddressUpdateResponse `json:"customerAddressUpdate"`
@@ -338,7 +339,7 @@ func (c GQLClient) UpdateCustomerAddress(customerID string, addressID string, in
 			"setAsDefault": isDefault,
 		},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -350,7 +351,7 @@ func (c GQLClient) UpdateCustomerAddress(customerID string, addressID string, in
 	return &out.Data.CustomerAddressUpdate, nil
 }
 
-func (c GQLClient) DeleteCustomer(customerID string) (*CustomerDeleteResponse, error) {
+func (c GQLClient) DeleteCustomer(ctx context.Context, customerID string) (*CustomerDeleteResponse, error) {
 	var out struct {
 		Data struct {
 			CustomerDelete CustomerDeleteResponse `json:"customerDelete"`
@@ -378,7 +379,7 @@ func (c GQLClient) DeleteCustomer(customerID string) (*CustomerDeleteResponse, e
 		},
 	}
 
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -169,7 +169,39 @@ preview {
 }
 mediaContentType
 mediaErrors { details }
-mediaWarnings { message }`
+mediaWarnings { message }
+... on Video {
+  originalSource {
+    url
+    mimeType
+  }
+}
+... on Model3d {
+  originalSource {
+    url
+    mimeType
+  }
+}`
+
+	fieldsInventory = `id
+title
+sku
+inventoryItem {
+  id
+  tracked
+  inventoryLevels(first: 50) {
+    nodes {
+      location {
+        id
+        name
+      }
+      quantities(names: ["available", "on_hand"]) {
+        name
+        quantity
+      }
+    }
+  }
+}`
 
 	fieldsMetafields = `id
 namespace
@@ -182,6 +214,39 @@ ownerType
 createdAt
 updatedAt`
 
+	fieldsCollection = `id
+legacyResourceId
+handle
+title
+description
+descriptionHtml
+sortOrder
+templateSuffix
+updatedAt
+seo {
+  title
+  description
+}
+image {
+  id
+  altText
+  url
+  width
+  height
+}
+ruleSet {
+  appliedDisjunctively
+  rules {
+    column
+    relation
+    condition
+  }
+}
+productsCount {
+  count
+  precision
+}`
+
 	fieldsCustomer = `id
 legacyResourceId
 firstName
@@ -293,6 +358,227 @@ displayFulfillmentStatus
 tags
 note`
 
+	fieldsOrderDetails = `id
+legacyResourceId
+name
+email
+phone
+note
+tags
+poNumber
+test
+taxesIncluded
+currencyCode
+presentmentCurrencyCode
+createdAt
+updatedAt
+processedAt
+closedAt
+cancelledAt
+cancelReason
+displayFinancialStatus
+displayFulfillmentStatus
+sourceName
+sourceIdentifier
+discountCodes
+customAttributes {
+  key
+  value
+}
+customer {
+  id
+  firstName
+  lastName
+  email
+  phone
+}
+billingAddress {
+  address1
+  address2
+  city
+  company
+  country
+  countryCodeV2
+  firstName
+  lastName
+  phone
+  province
+  provinceCode
+  zip
+}
+shippingAddress {
+  address1
+  address2
+  city
+  company
+  country
+  countryCodeV2
+  firstName
+  lastName
+  phone
+  province
+  provinceCode
+  zip
+}
+shippingLines(first: 50) {
+  nodes {
+    title
+    code
+    source
+    carrierIdentifier
+    originalPriceSet {
+      shopMoney {
+        amount
+        currencyCode
+      }
+      presentmentMoney {
+        amount
+        currencyCode
+      }
+    }
+  }
+}
+taxLines {
+  title
+  rate
+  priceSet {
+    shopMoney {
+      amount
+      currencyCode
+    }
+    presentmentMoney {
+      amount
+      currencyCode
+    }
+  }
+}
+subtotalPriceSet {
+  shopMoney {
+    amount
+    currencyCode
+  }
+  presentmentMoney {
+    amount
+    currencyCode
+  }
+}
+totalDiscountsSet {
+  shopMoney {
+    amount
+    currencyCode
+  }
+  presentmentMoney {
+    amount
+    currencyCode
+  }
+}
+totalTaxSet {
+  shopMoney {
+    amount
+    currencyCode
+  }
+  presentmentMoney {
+    amount
+    currencyCode
+  }
+}
+totalPriceSet {
+  shopMoney {
+    amount
+    currencyCode
+  }
+  presentmentMoney {
+    amount
+    currencyCode
+  }
+}`
+
+	fieldsOrderLineItem = `id
+name
+title
+variantTitle
+sku
+vendor
+quantity
+taxable
+requiresShipping
+originalUnitPriceSet {
+  shopMoney {
+    amount
+    currencyCode
+  }
+  presentmentMoney {
+    amount
+    currencyCode
+  }
+}
+variant {
+  id
+  title
+  sku
+}
+product {
+  id
+  handle
+}
+taxLines {
+  title
+  rate
+  priceSet {
+    shopMoney {
+      amount
+      currencyCode
+    }
+    presentmentMoney {
+      amount
+      currencyCode
+    }
+  }
+}`
+
+	fieldsOrderTransaction = `id
+kind
+status
+gateway
+test
+authorizationCode
+processedAt
+createdAt
+amountSet {
+  shopMoney {
+    amount
+    currencyCode
+  }
+  presentmentMoney {
+    amount
+    currencyCode
+  }
+}`
+
+	fieldsOrderFulfillment = `id
+name
+status
+createdAt
+updatedAt
+location {
+  id
+  name
+}
+trackingInfo {
+  company
+  number
+  url
+}
+fulfillmentLineItems(first: 250) {
+  nodes {
+    id
+    quantity
+    lineItem {
+      id
+    }
+  }
+}`
+
 	fieldsWebhook = `id
 topic
 format
@@ -305,4 +591,17 @@ endpoint {
 }
 createdAt
 updatedAt`
+
+	fieldsBulkOperation = `id
+status
+errorCode
+createdAt
+completedAt
+objectCount
+rootObjectCount
+fileSize
+url
+partialDataUrl
+query
+type`
 )
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ankitpokhrel/shopctl/pkg/gql/client"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+// GetProductInventory fetches inventory items of the product variants and their levels across locations.
+//
+// Shopify limits 100 variants per product, and we don't expect a store to have more
+// than 50 locations. We'll fetch them all at once and revisit if we run into any issue.
+func (c GQLClient) GetProductInventory(ctx context.Context, productID string) (*ProductInventoryData, error) {
+	var out *ProductInventoryResponse
+
+	query := fmt.Sprintf(`query GetProductInventory($id: ID!) {
+  product(id: $id) {
+    id
+    variants(first: 100) {
+      nodes {
+        %s
+      }
+    }
+  }
+}`, fieldsInventory)
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"id": productID},
+	}
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": productID}, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("%s", out.Errors)
+	}
+	return &out.Data.Product, nil
+}
+
+// GetLocations fetches active locations of the store.
+func (c GQLClient) GetLocations(ctx context.Context) ([]LocationNode, error) {
+	var out struct {
+		Data struct {
+			Locations struct {
+				Nodes []LocationNode `json:"nodes"`
+			} `json:"locations"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	query := `query GetLocations {
+  locations(first: 250) {
+    nodes {
+      id
+      name
+    }
+  }
+}`
+
+	req := client.GQLRequest{Query: query}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("%s", out.Errors)
+	}
+	return out.Data.Locations.Nodes, nil
+}
+
+// ActivateInventory stocks an inventory item at a location.
+func (c GQLClient) ActivateInventory(ctx context.Context, inventoryItemID string, locationID string) (*InventoryActivateResponse, error) {
+	var out struct {
+		Data struct {
+			InventoryActivate InventoryActivateResponse `json:"inventoryActivate"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	query := `
+	mutation inventoryActivate($inventoryItemId: ID!, $locationId: ID!) {
+		inventoryActivate(inventoryItemId: $inventoryItemId, locationId: $locationId) {
+			inventoryLevel {
+				id
+			}
+			userErrors {
+				field
+				message
+			}
+		}
+	}`
+
+	req := client.GQLRequest{
+		Query: query,
+		Variables: client.QueryVars{
+			"inventoryItemId": inventoryItemID,
+			"locationId":      locationID,
+		},
+	}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("inventoryActivate: the operation failed with error: %s", out.Errors.Error())
+	}
+	if len(out.Data.InventoryActivate.UserErrors) > 0 {
+		return nil, fmt.Errorf("inventoryActivate: the operation failed with user error: %s", out.Data.InventoryActivate.UserErrors.Error())
+	}
+	return &out.Data.InventoryActivate, nil
+}
+
+// SetInventoryQuantities sets inventory quantities of the inventory items at locations.
+func (c GQLClient) SetInventoryQuantities(ctx context.Context, input schema.InventorySetQuantitiesInput) (*InventorySetQuantitiesResponse, error) {
+	var out struct {
+		Data struct {
+			InventorySetQuantities InventorySetQuantitiesResponse `json:"inventorySetQuantities"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	query := `
+	mutation inventorySetQuantities($input: InventorySetQuantitiesInput!) {
+		inventorySetQuantities(input: $input) {
+			inventoryAdjustmentGroup {
+				id
+			}
+			userErrors {
+				field
+				message
+			}
+		}
+	}`
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"input": input},
+	}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("inventorySetQuantities: the operation failed with error: %s", out.Errors.Error())
+	}
+	if len(out.Data.InventorySetQuantities.UserErrors) > 0 {
+		return nil, fmt.Errorf("inventorySetQuantities: the operation failed with user error: %s", out.Data.InventorySetQuantities.UserErrors.Error())
+	}
+	return &out.Data.InventorySetQuantities, nil
+}
@@ -9,7 +9,7 @@ import (
 )
 
 // SetMetafields sets a metafield to the resource.
-func (c GQLClient) SetMetafields(metafields []schema.MetafieldsSetInput) (*MetafieldSetResponse, error) {
+func (c GQLClient) SetMetafields(ctx context.Context, metafields []schema.MetafieldsSetInput) (*MetafieldSetResponse, error) {
 	var out struct {
 		Data struct {
 			MetafieldsSet MetafieldSetResponse `json:"metafieldsSet"`
@@ -37,7 +37,7 @@ func (c GQLClient) SetMetafields(metafields []schema.MetafieldsSetInput) (*Metaf
 		},
 	}
 
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -50,7 +50,7 @@ func (c GQLClient) SetMetafields(metafields []schema.MetafieldsSetInput) (*Metaf
 }
 
 // DeleteMetafields deletes metafield attached to a resource.
-func (c GQLClient) DeleteMetafields(metafields []schema.MetafieldIdentifierInput) (*MetafieldDeleteResponse, error) {
+func (c GQLClient) DeleteMetafields(ctx context.Context, metafields []schema.MetafieldIdentifierInput) (*MetafieldDeleteResponse, error) {
 	var out struct {
 		Data struct {
 			MetafieldsDelete MetafieldDeleteResponse `json:"metafieldsDelete"`
@@ -80,7 +80,7 @@ func (c GQLClient) DeleteMetafields(metafields []schema.MetafieldIdentifierInput
 		},
 	}
 
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -91,3 +91,44 @@ func (c GQLClient) DeleteMetafields(metafields []schema.MetafieldIdentifierInput
 	}
 	return &out.Data.MetafieldsDelete, nil
 }
+
+// GetMetafieldDefinitions fetches the metafield definitions of the given owner type.
+//
+// Stores are limited to 256 definitions per owner type, so we fetch them all at once.
+func (c GQLClient) GetMetafieldDefinitions(ctx context.Context, ownerType schema.MetafieldOwnerType) ([]MetafieldDefinitionNode, error) {
+	var out struct {
+		Data struct {
+			MetafieldDefinitions struct {
+				Nodes []MetafieldDefinitionNode `json:"nodes"`
+			} `json:"metafieldDefinitions"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	query := `query GetMetafieldDefinitions($ownerType: MetafieldOwnerType!) {
+  metafieldDefinitions(first: 250, ownerType: $ownerType) {
+    nodes {
+      id
+      namespace
+      key
+      type {
+        name
+      }
+    }
+  }
+}`
+
+	req := client.GQLRequest{
+		Query: query,
+		Variables: client.QueryVars{
+			"ownerType": ownerType,
+		},
+	}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("%s", out.Errors)
+	}
+	return out.Data.MetafieldDefinitions.Nodes, nil
+}
@@ -9,7 +9,7 @@ import (
 )
 
 // GetOrders fetches n number of orders after a cursor.
-func (c GQLClient) GetOrders(limit int, after *string, query *string) ([]schema.Order, error) {
+func (c GQLClient) GetOrders(ctx context.Context, limit int, after *string, query *string) ([]schema.Order, error) {
 	var out *OrdersResponse
 
 	ordersQuery := fmt.Sprintf(`query GetOrders($first: Int!, $after: String, $query: String, $sortKey: OrderSortKeys!, $reverse: Boolean!) {
@@ -34,7 +34,7 @@ func (c GQLClient) GetOrders(limit int, after *string, query *string) ([]schema.
 			"reverse": true,
 		},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -42,3 +42,311 @@ func (c GQLClient) GetOrders(limit int, after *string, query *string) ([]schema.
 	}
 	return out.Data.Orders.Nodes, nil
 }
+
+// GetAllOrders fetches orders in a batch and streams the response to a channel.
+func (c GQLClient) GetAllOrders(ctx context.Context, ch chan *OrdersResponse, limit int, after *string, query *string) error {
+	var out *OrdersResponse
+
+	ordersQuery := fmt.Sprintf(`query GetOrders($first: Int!, $after: String, $query: String) {
+  orders(first: $first, after: $after, query: $query) {
+    nodes {
+      %s
+    }
+    pageInfo {
+      hasNextPage
+      endCursor
+    }
+  }
+}`, fieldsOrderDetails)
+
+	req := client.GQLRequest{
+		Query: ordersQuery,
+		Variables: client.QueryVars{
+			"first": limit,
+			"after": after,
+			"query": query,
+		},
+	}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return err
+	}
+	if len(out.Errors) > 0 {
+		return fmt.Errorf("%s", out.Errors)
+	}
+
+	ch <- out
+
+	if out.Data.Orders.PageInfo.HasNextPage {
+		return c.GetAllOrders(ctx, ch, limit, out.Data.Orders.PageInfo.EndCursor, query)
+	}
+	return nil
+}
+
+// CheckOrderByName fetches an order by its name without additional details.
+func (c GQLClient) CheckOrderByName(ctx context.Context, name string) (*schema.Order, error) {
+	var out *OrdersResponse
+
+	query := `query CheckOrderByName($query: String!) {
+  orders(first: 1, query: $query) {
+    nodes {
+      id
+      name
+    }
+  }
+}`
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"query": fmt.Sprintf("name:%q", name)},
+	}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("checkOrderByName: the operation failed with error: %s", out.Errors.Error())
+	}
+	if len(out.Data.Orders.Nodes) == 0 {
+		return nil, fmt.Errorf("order not found")
+	}
+	return &out.Data.Orders.Nodes[0], nil
+}
+
+// GetOrderLineItems fetches line items of an order.
+//
+// Orders rarely have more than a few line items, so we'll fetch up to 250
+// of them at once. We will revisit this if we run into any issue.
+func (c GQLClient) GetOrderLineItems(ctx context.Context, orderID string) (*OrderLineItemsResponse, error) {
+	var out *OrderLineItemsResponse
+
+	query := fmt.Sprintf(`query GetOrderLineItems($id: ID!) {
+  order(id: $id) {
+    id
+    lineItems(first: 250) {
+      nodes {
+        %s
+      }
+    }
+  }
+}`, fieldsOrderLineItem)
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"id": orderID},
+	}
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": orderID}, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("%s", out.Errors)
+	}
+	return out, nil
+}
+
+// GetOrderTransactions fetches transactions of an order.
+func (c GQLClient) GetOrderTransactions(ctx context.Context, orderID string) (*OrderTransactionsResponse, error) {
+	var out *OrderTransactionsResponse
+
+	query := fmt.Sprintf(`query GetOrderTransactions($id: ID!) {
+  order(id: $id) {
+    id
+    transactions(first: 100) {
+      %s
+    }
+  }
+}`, fieldsOrderTransaction)
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"id": orderID},
+	}
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": orderID}, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("%s", out.Errors)
+	}
+	return out, nil
+}
+
+// GetOrderFulfillments fetches fulfillments of an order.
+func (c GQLClient) GetOrderFulfillments(ctx context.Context, orderID string) (*OrderFulfillmentsResponse, error) {
+	var out *OrderFulfillmentsResponse
+
+	query := fmt.Sprintf(`query GetOrderFulfillments($id: ID!) {
+  order(id: $id) {
+    id
+    fulfillments(first: 100) {
+      %s
+    }
+  }
+}`, fieldsOrderFulfillment)
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"id": orderID},
+	}
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": orderID}, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("%s", out.Errors)
+	}
+	return out, nil
+}
+
+// GetOrderMetaFields fetches metafields of an order by its ID.
+func (c GQLClient) GetOrderMetaFields(ctx context.Context, orderID string) (*OrderMetaFieldsResponse, error) {
+	var out *OrderMetaFieldsResponse
+
+	query := fmt.Sprintf(`query GetOrderMetaFields($id: ID!) {
+  order(id: $id) {
+    id
+    metafields(first: 200) {
+      nodes {
+        %s
+      }
+    }
+  }
+}`, fieldsMetafields)
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"id": orderID},
+	}
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": orderID}, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("%s", out.Errors)
+	}
+	return out, nil
+}
+
+// GetFulfillmentOrders fetches fulfillment orders of an order.
+func (c GQLClient) GetFulfillmentOrders(ctx context.Context, orderID string) (*FulfillmentOrdersData, error) {
+	var out struct {
+		Data struct {
+			Order FulfillmentOrdersData `json:"order"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	query := `query GetFulfillmentOrders($id: ID!) {
+  order(id: $id) {
+    id
+    fulfillmentOrders(first: 50) {
+      nodes {
+        id
+        status
+        lineItems(first: 250) {
+          nodes {
+            id
+            remainingQuantity
+            lineItem {
+              id
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"id": orderID},
+	}
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": orderID}, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("%s", out.Errors)
+	}
+	return &out.Data.Order, nil
+}
+
+// CreateOrder creates an order.
+func (c GQLClient) CreateOrder(ctx context.Context, input schema.OrderCreateOrderInput, options *schema.OrderCreateOptionsInput) (*OrderCreateResponse, error) {
+	var out struct {
+		Data struct {
+			OrderCreate OrderCreateResponse `json:"orderCreate"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	query := `
+	mutation orderCreate($order: OrderCreateOrderInput!, $options: OrderCreateOptionsInput) {
+		orderCreate(order: $order, options: $options) {
+			order {
+				id
+				name
+				lineItems(first: 250) {
+					nodes {
+						id
+					}
+				}
+			}
+			userErrors {
+				field
+				message
+			}
+		}
+	}`
+
+	req := client.GQLRequest{
+		Query: query,
+		Variables: client.QueryVars{
+			"order":   input,
+			"options": options,
+		},
+	}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("orderCreate: the operation failed with error: %s", out.Errors.Error())
+	}
+	if len(out.Data.OrderCreate.UserErrors) > 0 {
+		return nil, fmt.Errorf("orderCreate: the operation failed with user error: %s", out.Data.OrderCreate.UserErrors.Error())
+	}
+	return &out.Data.OrderCreate, nil
+}
+
+// CreateFulfillment creates a fulfillment for the fulfillment orders.
+func (c GQLClient) CreateFulfillment(ctx context.Context, input schema.FulfillmentInput) (*FulfillmentCreateResponse, error) {
+	var out struct {
+		Data struct {
+			FulfillmentCreate FulfillmentCreateResponse `json:"fulfillmentCreate"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	query := `
+	mutation fulfillmentCreate($fulfillment: FulfillmentInput!) {
+		fulfillmentCreate(fulfillment: $fulfillment) {
+			fulfillment {
+				id
+				status
+			}
+			userErrors {
+				field
+				message
+			}
+		}
+	}`
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"fulfillment": input},
+	}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("fulfillmentCreate: the operation failed with error: %s", out.Errors.Error())
+	}
+	if len(out.Data.FulfillmentCreate.UserErrors) > 0 {
+		return nil, fmt.Errorf("fulfillmentCreate: the operation failed with user error: %s", out.Data.FulfillmentCreate.UserErrors.Error())
+	}
+	return &out.Data.FulfillmentCreate, nil
+}
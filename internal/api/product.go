@@ -3,6 +3,8 @@ package api
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"strings"
 
 	"github.com/ankitpokhrel/shopctl/pkg/gql/client"
@@ -10,7 +12,7 @@ import (
 )
 
 // CheckProductByID fetches a product by ID without additional details.
-func (c GQLClient) CheckProductByID(id string) (*schema.Product, error) {
+func (c GQLClient) CheckProductByID(ctx context.Context, id string) (*schema.Product, error) {
 	var (
 		query = `query CheckProductByID($id: ID!) { product(id: $id) { id } }`
 
@@ -22,14 +24,14 @@ func (c GQLClient) CheckProductByID(id string) (*schema.Product, error) {
 		Query:     query,
 		Variables: client.QueryVars{"id": id},
 	}
-	if err = c.Execute(context.Background(), req, client.Header{"X-ShopCTL-Resource-ID": id}, &out); err != nil {
+	if err = c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": id}, &out); err != nil {
 		return nil, err
 	}
 	return &out.Data.Product, err
 }
 
 // CheckProductByHandle fetches a product by Handle without additional details.
-func (c GQLClient) CheckProductByHandle(handle string) (*schema.Product, error) {
+func (c GQLClient) CheckProductByHandle(ctx context.Context, handle string) (*schema.Product, error) {
 	var out struct {
 		Data struct {
 			Product schema.Product `json:"productByIdentifier"`
@@ -50,14 +52,14 @@ func (c GQLClient) CheckProductByHandle(handle string) (*schema.Product, error)
 			"identifier": map[string]string{"handle": handle},
 		},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	return &out.Data.Product, nil
 }
 
 // GetProductByID fetches a product by ID.
-func (c GQLClient) GetProductByID(id string) (*schema.Product, error) {
+func (c GQLClient) GetProductByID(ctx context.Context, id string) (*schema.Product, error) {
 	var out *ProductResponse
 
 	query := fmt.Sprintf(`query GetProductByID($id: ID!) {
@@ -80,7 +82,7 @@ func (c GQLClient) GetProductByID(id string) (*schema.Product, error) {
 		Query:     query,
 		Variables: client.QueryVars{"id": id},
 	}
-	if err := c.Execute(context.Background(), req, client.Header{"X-ShopCTL-Resource-ID": id}, &out); err != nil {
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": id}, &out); err != nil {
 		return nil, err
 	}
 	if out.Data.Product.ID == "" {
@@ -92,7 +94,33 @@ func (c GQLClient) GetProductByID(id string) (*schema.Product, error) {
 	return &out.Data.Product, nil
 }
 
-func (c GQLClient) GetProductByHandle(handle string) (*schema.Product, error) {
+// GetProductFieldsByID fetches a product by ID without its variants and media.
+func (c GQLClient) GetProductFieldsByID(ctx context.Context, id string) (*schema.Product, error) {
+	var out *ProductResponse
+
+	query := fmt.Sprintf(`query GetProductFieldsByID($id: ID!) {
+  product(id: $id) {
+    %s
+  }
+}`, fieldsProduct)
+
+	req := client.GQLRequest{
+		Query:     query,
+		Variables: client.QueryVars{"id": id},
+	}
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": id}, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("%s", out.Errors)
+	}
+	if out.Data.Product.ID == "" {
+		return nil, fmt.Errorf("product not found")
+	}
+	return &out.Data.Product, nil
+}
+
+func (c GQLClient) GetProductByHandle(ctx context.Context, handle string) (*schema.Product, error) {
 	var out struct {
 		Data struct {
 			Product schema.Product `json:"productByIdentifier"`
@@ -124,7 +152,7 @@ func (c GQLClient) GetProductByHandle(handle string) (*schema.Product, error) {
 		},
 	}
 
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if out.Data.Product.ID == "" {
@@ -137,7 +165,7 @@ func (c GQLClient) GetProductByHandle(handle string) (*schema.Product, error) {
 }
 
 // GetProducts fetches n number of products after a cursor.
-func (c GQLClient) GetProducts(limit int, after *string, query *string) (*ProductsResponse, error) {
+func (c GQLClient) GetProducts(ctx context.Context, limit int, after *string, query *string) (*ProductsResponse, error) {
 	var out *ProductsResponse
 
 	productQuery := fmt.Sprintf(`query GetProducts($first: Int!, $after: String, $query: String, $sortKey: ProductSortKeys!, $reverse: Boolean!) {
@@ -170,7 +198,7 @@ products(first: $first, after: $after, query: $query, sortKey: $sortKey, reverse
 			"reverse": true,
 		},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -180,7 +208,7 @@ products(first: $first, after: $after, query: $query, sortKey: $sortKey, reverse
 }
 
 // GetAllProducts fetches products in a batch and streams the response to a channel.
-func (c GQLClient) GetAllProducts(ch chan *ProductsResponse, limit int, after *string, query *string) error {
+func (c GQLClient) GetAllProducts(ctx context.Context, ch chan *ProductsResponse, limit int, after *string, query *string) error {
 	var out *ProductsResponse
 
 	productQuery := fmt.Sprintf(`query GetProducts($first: Int!, $after: String, $query: String) {
@@ -211,7 +239,7 @@ func (c GQLClient) GetAllProducts(ch chan *ProductsResponse, limit int, after *s
 			"query": query,
 		},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return err
 	}
 	if len(out.Errors) > 0 {
@@ -221,13 +249,13 @@ func (c GQLClient) GetAllProducts(ch chan *ProductsResponse, limit int, after *s
 	ch <- out
 
 	if out.Data.Products.PageInfo.HasNextPage {
-		return c.GetAllProducts(ch, limit, out.Data.Products.PageInfo.EndCursor, query)
+		return c.GetAllProducts(ctx, ch, limit, out.Data.Products.PageInfo.EndCursor, query)
 	}
 	return nil
 }
 
 // GetProductOptions fetches product options.
-func (c GQLClient) GetProductOptions(productID string) (*ProductOptionsResponse, error) {
+func (c GQLClient) GetProductOptions(ctx context.Context, productID string) (*ProductOptionsResponse, error) {
 	var out *ProductOptionsResponse
 
 	query := `query GetProductOptions($id: ID!) {
@@ -255,7 +283,7 @@ func (c GQLClient) GetProductOptions(productID string) (*ProductOptionsResponse,
 		Query:     query,
 		Variables: client.QueryVars{"id": productID},
 	}
-	if err := c.Execute(context.Background(), req, client.Header{"X-ShopCTL-Resource-ID": productID}, &out); err != nil {
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": productID}, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -268,7 +296,7 @@ func (c GQLClient) GetProductOptions(productID string) (*ProductOptionsResponse,
 //
 // Shopify limits 100 variants per product so we should be good to fetch them all at once.
 // We will revisit this if we run into any issues even with the limit.
-func (c GQLClient) GetProductVariants(productID string) (*ProductVariantData, error) {
+func (c GQLClient) GetProductVariants(ctx context.Context, productID string) (*ProductVariantData, error) {
 	var out *ProductVariantsResponse
 
 	query := fmt.Sprintf(`query GetProductVariants($id: ID!) {
@@ -286,7 +314,7 @@ func (c GQLClient) GetProductVariants(productID string) (*ProductVariantData, er
 		Query:     query,
 		Variables: client.QueryVars{"id": productID},
 	}
-	if err := c.Execute(context.Background(), req, client.Header{"X-ShopCTL-Resource-ID": productID}, &out); err != nil {
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": productID}, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -296,16 +324,16 @@ func (c GQLClient) GetProductVariants(productID string) (*ProductVariantData, er
 }
 
 // CheckProductVariantByID returns product variant without fetching all fields.
-func (c GQLClient) CheckProductVariantByID(variantID string) (*schema.ProductVariant, error) {
-	return c.getProductVariantByID(variantID, "id\ntitle\n")
+func (c GQLClient) CheckProductVariantByID(ctx context.Context, variantID string) (*schema.ProductVariant, error) {
+	return c.getProductVariantByID(ctx, variantID, "id\ntitle\n")
 }
 
 // GetProductVariantByID returns product variant by its id.
-func (c GQLClient) GetProductVariantByID(variantID string) (*schema.ProductVariant, error) {
-	return c.getProductVariantByID(variantID, fieldsVariant)
+func (c GQLClient) GetProductVariantByID(ctx context.Context, variantID string) (*schema.ProductVariant, error) {
+	return c.getProductVariantByID(ctx, variantID, fieldsVariant)
 }
 
-func (c GQLClient) getProductVariantByID(variantID string, fields string) (*schema.ProductVariant, error) {
+func (c GQLClient) getProductVariantByID(ctx context.Context, variantID string, fields string) (*schema.ProductVariant, error) {
 	var out struct {
 		Data struct {
 			Node *schema.ProductVariant `json:"node"`
@@ -325,7 +353,7 @@ func (c GQLClient) getProductVariantByID(variantID string, fields string) (*sche
 		Query:     query,
 		Variables: client.QueryVars{"id": variantID},
 	}
-	if err := c.Execute(context.Background(), req, client.Header{"X-ShopCTL-Resource-ID": variantID}, &out); err != nil {
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": variantID}, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -341,7 +369,7 @@ func (c GQLClient) getProductVariantByID(variantID string, fields string) (*sche
 //
 // Shopify limits 100 variants per product so we should be good to fetch them all at once.
 // We will revisit this if we run into any issues even with the limit.
-func (c GQLClient) GetProductVariantByTitle(productID string, title string, fetchAll bool) (*schema.ProductVariant, error) {
+func (c GQLClient) GetProductVariantByTitle(ctx context.Context, productID string, title string, fetchAll bool) (*schema.ProductVariant, error) {
 	var out *ProductVariantsResponse
 
 	query := `query GetProductVariants($id: ID!) {
@@ -360,7 +388,7 @@ func (c GQLClient) GetProductVariantByTitle(productID string, title string, fetc
 		Query:     query,
 		Variables: client.QueryVars{"id": productID},
 	}
-	if err := c.Execute(context.Background(), req, client.Header{"X-ShopCTL-Resource-ID": productID}, &out); err != nil {
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": productID}, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -369,7 +397,7 @@ func (c GQLClient) GetProductVariantByTitle(productID string, title string, fetc
 	for _, v := range out.Data.Product.Variants.Nodes {
 		if strings.EqualFold(v.Title, title) {
 			if fetchAll {
-				return c.GetProductVariantByID(v.ID)
+				return c.GetProductVariantByID(ctx, v.ID)
 			}
 			return &v, nil
 		}
@@ -382,7 +410,7 @@ func (c GQLClient) GetProductVariantByTitle(productID string, title string, fetc
 // Shopify limits 200 metafields per product and the response size seems ok.
 // We'll fetch them all at once for now. We will revisit this if we run
 // into any issue due to the response size.
-func (c GQLClient) GetProductMetaFields(productID string) (*ProductMetaFieldsResponse, error) {
+func (c GQLClient) GetProductMetaFields(ctx context.Context, productID string) (*ProductMetaFieldsResponse, error) {
 	var out *ProductMetaFieldsResponse
 
 	query := fmt.Sprintf(`query GetProductMetaFields($id: ID!) {
@@ -400,7 +428,7 @@ func (c GQLClient) GetProductMetaFields(productID string) (*ProductMetaFieldsRes
 		Query:     query,
 		Variables: client.QueryVars{"id": productID},
 	}
-	if err := c.Execute(context.Background(), req, client.Header{"X-ShopCTL-Resource-ID": productID}, &out); err != nil {
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": productID}, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -414,7 +442,7 @@ func (c GQLClient) GetProductMetaFields(productID string) (*ProductMetaFieldsRes
 // Shopify limits 250 medias per product and the response size seems ok.
 // We'll fetch them all at once for now. We will revisit this if we run
 // into any issue due to the response size.
-func (c GQLClient) GetProductMedias(productID string) (*ProductMediasResponse, error) {
+func (c GQLClient) GetProductMedias(ctx context.Context, productID string) (*ProductMediasResponse, error) {
 	var out *ProductMediasResponse
 
 	query := fmt.Sprintf(`query GetProductMedias($id: ID!) {
@@ -432,7 +460,7 @@ func (c GQLClient) GetProductMedias(productID string) (*ProductMediasResponse, e
 		Query:     query,
 		Variables: client.QueryVars{"id": productID},
 	}
-	if err := c.Execute(context.Background(), req, client.Header{"X-ShopCTL-Resource-ID": productID}, &out); err != nil {
+	if err := c.Execute(ctx, req, client.Header{"X-ShopCTL-Resource-ID": productID}, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -441,8 +469,32 @@ func (c GQLClient) GetProductMedias(productID string) (*ProductMediasResponse, e
 	return out, nil
 }
 
+// DownloadMediaFile downloads the original file of a product media.
+// The caller is responsible for closing the returned reader.
+func (c GQLClient) DownloadMediaFile(ctx context.Context, url string) (io.ReadCloser, error) {
+	res, err := c.Get(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download media file: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		_ = res.Body.Close()
+		return nil, fmt.Errorf("unable to download media file: unexpected status code: %d", res.StatusCode)
+	}
+	return res.Body, nil
+}
+
+// IsMediaReachable checks if the file of a product media can still be downloaded from the given url.
+func (c GQLClient) IsMediaReachable(ctx context.Context, url string) bool {
+	res, err := c.Head(ctx, url)
+	if err != nil {
+		return false
+	}
+	_ = res.Body.Close()
+	return res.StatusCode == http.StatusOK
+}
+
 // CreateProduct creates a product.
-func (c GQLClient) CreateProduct(input schema.ProductInput) (*ProductCreateResponse, error) {
+func (c GQLClient) CreateProduct(ctx context.Context, input schema.ProductInput) (*ProductCreateResponse, error) {
 	var out struct {
 		Data struct {
 			ProductCreate ProductCreateResponse `json:"productCreate"`
@@ -469,7 +521,7 @@ func (c GQLClient) CreateProduct(input schema.ProductInput) (*ProductCreateRespo
 		Query:     query,
 		Variables: client.QueryVars{"input": input},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -482,7 +534,7 @@ func (c GQLClient) CreateProduct(input schema.ProductInput) (*ProductCreateRespo
 }
 
 // UpdateProduct updates a product.
-func (c GQLClient) UpdateProduct(input schema.ProductInput, media []schema.CreateMediaInput) (*ProductCreateResponse, error) {
+func (c GQLClient) UpdateProduct(ctx context.Context, input schema.ProductInput, media []schema.CreateMediaInput) (*ProductCreateResponse, error) {
 	var out struct {
 		Data struct {
 			ProductUpdate ProductCreateResponse `json:"productUpdate"`
@@ -513,7 +565,7 @@ func (c GQLClient) UpdateProduct(input schema.ProductInput, media []schema.Creat
 		},
 	}
 
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -526,7 +578,7 @@ func (c GQLClient) UpdateProduct(input schema.ProductInput, media []schema.Creat
 }
 
 // DeleteProduct deletes a product.
-func (c GQLClient) DeleteProduct(productID string) (*ProductDeleteResponse, error) {
+func (c GQLClient) DeleteProduct(ctx context.Context, productID string) (*ProductDeleteResponse, error) {
 	var out struct {
 		Data struct {
 			ProductDelete ProductDeleteResponse `json:"productDelete"`
@@ -552,7 +604,7 @@ func (c GQLClient) DeleteProduct(productID string) (*ProductDeleteResponse, erro
 		},
 	}
 
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -568,6 +620,7 @@ func (c GQLClient) DeleteProduct(productID string) (*ProductDeleteResponse, erro
 //
 //nolint:dupl
 func (c GQLClient) CreateProductOptions(
+	ctx context.Context,
 	productID string,
 	options []schema.OptionCreateInput,
 	strategy schema.ProductOptionCreateVariantStrategy,
@@ -607,7 +660,7 @@ func (c GQLClient) CreateProductOptions(
 		},
 	}
 
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -621,6 +674,7 @@ func (c GQLClient) CreateProductOptions(
 
 // UpdateProductOptions updates product options.
 func (c GQLClient) UpdateProductOptions(
+	ctx context.Context,
 	productID string,
 	option *schema.OptionUpdateInput,
 	optionsToAdd []schema.OptionValueCreateInput,
@@ -665,7 +719,7 @@ func (c GQLClient) UpdateProductOptions(
 		},
 	}
 
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -678,7 +732,7 @@ func (c GQLClient) UpdateProductOptions(
 }
 
 // DeleteProductOptions removes one or more product options.
-func (c GQLClient) DeleteProductOptions(productID string, options []string) (*ProductOptionSyncResponse, error) {
+func (c GQLClient) DeleteProductOptions(ctx context.Context, productID string, options []string) (*ProductOptionSyncResponse, error) {
 	var out struct {
 		Data struct {
 			ProductOptionDelete ProductOptionSyncResponse `json:"productOptionsDelete"`
@@ -714,7 +768,7 @@ func (c GQLClient) DeleteProductOptions(productID string, options []string) (*Pr
 		},
 	}
 
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -730,6 +784,7 @@ func (c GQLClient) DeleteProductOptions(productID string, options []string) (*Pr
 //
 //nolint:dupl
 func (c GQLClient) CreateProductVariants(
+	ctx context.Context,
 	productID string,
 	variants []schema.ProductVariantsBulkInput,
 	strategy schema.ProductVariantsBulkCreateStrategy,
@@ -747,6 +802,10 @@ func (c GQLClient) CreateProductVariants(
         product {
           id
         }
+        productVariants {
+          id
+          title
+        }
         userErrors {
           field
           message
@@ -764,7 +823,7 @@ func (c GQLClient) CreateProductVariants(
 		},
 	}
 
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -777,7 +836,7 @@ func (c GQLClient) CreateProductVariants(
 }
 
 // UpdateProductVariants creates one or more product variants.
-func (c GQLClient) UpdateProductVariants(productID string, variants []schema.ProductVariantsBulkInput, partialUpdate bool) (*ProductVariantsSyncResponse, error) {
+func (c GQLClient) UpdateProductVariants(ctx context.Context, productID string, variants []schema.ProductVariantsBulkInput, partialUpdate bool) (*ProductVariantsSyncResponse, error) {
 	var out struct {
 		Data struct {
 			ProductVariantsBulkUpdate ProductVariantsSyncResponse `json:"productVariantsBulkUpdate"`
@@ -808,7 +867,7 @@ func (c GQLClient) UpdateProductVariants(productID string, variants []schema.Pro
 		},
 	}
 
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -821,7 +880,7 @@ func (c GQLClient) UpdateProductVariants(productID string, variants []schema.Pro
 }
 
 // DeleteProductVariants deletes one or more product variants.
-func (c GQLClient) DeleteProductVariants(productID string, variants []string) (*ProductVariantsSyncResponse, error) {
+func (c GQLClient) DeleteProductVariants(ctx context.Context, productID string, variants []string) (*ProductVariantsSyncResponse, error) {
 	var out struct {
 		Data struct {
 			ProductVariantBulkDelete ProductVariantsSyncResponse `json:"productVariantsBulkDelete"`
@@ -855,7 +914,7 @@ func (c GQLClient) DeleteProductVariants(productID string, variants []string) (*
 		},
 	}
 
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -868,7 +927,7 @@ func (c GQLClient) DeleteProductVariants(productID string, variants []string) (*
 }
 
 // DetachProductMedia detaches one or more product media.
-func (c GQLClient) DetachProductMedia(input []schema.FileUpdateInput) (*FileUpdateResponse, error) {
+func (c GQLClient) DetachProductMedia(ctx context.Context, input []schema.FileUpdateInput) (*FileUpdateResponse, error) {
 	var out struct {
 		Data struct {
 			FileUpdate FileUpdateResponse `json:"fileUpdate"`
@@ -894,7 +953,7 @@ func (c GQLClient) DetachProductMedia(input []schema.FileUpdateInput) (*FileUpda
 		Variables: client.QueryVars{"input": input},
 	}
 
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -905,3 +964,43 @@ func (c GQLClient) DetachProductMedia(input []schema.FileUpdateInput) (*FileUpda
 	}
 	return &out.Data.FileUpdate, nil
 }
+
+// GetTaxonomyCategories searches the product taxonomy for the categories matching the given term.
+func (c GQLClient) GetTaxonomyCategories(ctx context.Context, search string) ([]TaxonomyCategoryNode, error) {
+	var out struct {
+		Data struct {
+			Taxonomy struct {
+				Categories struct {
+					Nodes []TaxonomyCategoryNode `json:"nodes"`
+				} `json:"categories"`
+			} `json:"taxonomy"`
+		} `json:"data"`
+		Errors Errors `json:"errors"`
+	}
+
+	query := `query GetTaxonomyCategories($search: String!) {
+  taxonomy {
+    categories(first: 10, search: $search) {
+      nodes {
+        id
+        name
+        fullName
+      }
+    }
+  }
+}`
+
+	req := client.GQLRequest{
+		Query: query,
+		Variables: client.QueryVars{
+			"search": search,
+		},
+	}
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Errors) > 0 {
+		return nil, fmt.Errorf("%s", out.Errors)
+	}
+	return out.Data.Taxonomy.Categories.Nodes, nil
+}
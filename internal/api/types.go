@@ -83,6 +83,13 @@ type ProductMediaNode struct {
 	MediaContentType schema.MediaContentType  `json:"mediaContentType"`
 	MediaErrors      []any                    `json:"mediaErrors,omitempty"`
 	MediaWarnings    []any                    `json:"mediaWarnings,omitempty"`
+	// OriginalSource is the original file of a video or a 3D model.
+	OriginalSource *MediaSource `json:"originalSource,omitempty"`
+}
+
+type MediaSource struct {
+	URL      string `json:"url"`
+	MimeType string `json:"mimeType"`
 }
 
 type ProductMediaData struct {
@@ -92,6 +99,78 @@ type ProductMediaData struct {
 	} `json:"media"`
 }
 
+type InventoryQuantity struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+}
+
+type LocationNode struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type MetafieldDefinitionNode struct {
+	ID        string `json:"id"`
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Type      struct {
+		Name string `json:"name"`
+	} `json:"type"`
+}
+
+type TaxonomyCategoryNode struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	FullName string `json:"fullName"`
+}
+
+type InventoryLevelNode struct {
+	Location   LocationNode        `json:"location"`
+	Quantities []InventoryQuantity `json:"quantities"`
+}
+
+type ProductInventoryNode struct {
+	ID            string  `json:"id"`
+	Title         string  `json:"title"`
+	Sku           *string `json:"sku,omitempty"`
+	InventoryItem struct {
+		ID              string `json:"id"`
+		Tracked         bool   `json:"tracked"`
+		InventoryLevels struct {
+			Nodes []InventoryLevelNode `json:"nodes"`
+		} `json:"inventoryLevels"`
+	} `json:"inventoryItem"`
+}
+
+type ProductInventoryData struct {
+	ProductID string `json:"id"`
+	Variants  struct {
+		Nodes []ProductInventoryNode `json:"nodes"`
+	} `json:"variants"`
+}
+
+type ProductInventoryResponse struct {
+	Data struct {
+		Product ProductInventoryData `json:"product"`
+	} `json:"data"`
+	Errors     Errors     `json:"errors"`
+	Extensions Extensions `json:"extensions"`
+}
+
+type InventoryActivateResponse struct {
+	InventoryLevel struct {
+		ID string `json:"id"`
+	} `json:"inventoryLevel"`
+	UserErrors UserErrors `json:"userErrors"`
+}
+
+type InventorySetQuantitiesResponse struct {
+	InventoryAdjustmentGroup *struct {
+		ID string `json:"id"`
+	} `json:"inventoryAdjustmentGroup"`
+	UserErrors UserErrors `json:"userErrors"`
+}
+
 type FileUpdateResponse struct {
 	Files []struct {
 		ID string `json:"id"`
@@ -135,6 +214,54 @@ type MetafieldDeleteResponse struct {
 	UserErrors UserErrors                   `json:"userErrors"`
 }
 
+type CollectionResponse struct {
+	Data struct {
+		Collection schema.Collection `json:"collection"`
+	} `json:"data"`
+	Errors Errors `json:"errors"`
+}
+
+type CollectionsResponse struct {
+	Data struct {
+		Collections CollectionData `json:"collections"`
+	} `json:"data"`
+	Errors     Errors     `json:"errors"`
+	Extensions Extensions `json:"extensions"`
+}
+
+type CollectionData struct {
+	Nodes    []schema.Collection `json:"nodes"`
+	PageInfo schema.PageInfo     `json:"pageInfo"`
+}
+
+type CollectionProductNode struct {
+	ID     string `json:"id"`
+	Handle string `json:"handle"`
+}
+
+type CollectionProductsData struct {
+	CollectionID string `json:"id"`
+	Products     struct {
+		Nodes    []CollectionProductNode `json:"nodes"`
+		PageInfo schema.PageInfo         `json:"pageInfo"`
+	} `json:"products"`
+}
+
+type CollectionProductsResponse struct {
+	Data struct {
+		Collection CollectionProductsData `json:"collection"`
+	} `json:"data"`
+	Errors     Errors     `json:"errors"`
+	Extensions Extensions `json:"extensions"`
+}
+
+type CollectionSyncResponse struct {
+	Collection struct {
+		ID string `json:"id"`
+	} `json:"collection"`
+	UserErrors UserErrors `json:"userErrors"`
+}
+
 type CustomerCreateResponse struct {
 	Customer   schema.Customer `json:"customer"`
 	UserErrors UserErrors      `json:"userErrors"`
@@ -221,6 +348,177 @@ type OrderData struct {
 	PageInfo schema.PageInfo `json:"pageInfo"`
 }
 
+type OrderTaxLine struct {
+	Title    string          `json:"title"`
+	Rate     *float64        `json:"rate,omitempty"`
+	PriceSet schema.MoneyBag `json:"priceSet"`
+}
+
+type OrderShippingLine struct {
+	Title             string          `json:"title"`
+	Code              *string         `json:"code,omitempty"`
+	Source            *string         `json:"source,omitempty"`
+	CarrierIdentifier *string         `json:"carrierIdentifier,omitempty"`
+	OriginalPriceSet  schema.MoneyBag `json:"originalPriceSet"`
+}
+
+type OrderLineItemNode struct {
+	ID                   string          `json:"id"`
+	Name                 string          `json:"name"`
+	Title                string          `json:"title"`
+	VariantTitle         *string         `json:"variantTitle,omitempty"`
+	Sku                  *string         `json:"sku,omitempty"`
+	Vendor               *string         `json:"vendor,omitempty"`
+	Quantity             int             `json:"quantity"`
+	Taxable              bool            `json:"taxable"`
+	RequiresShipping     bool            `json:"requiresShipping"`
+	OriginalUnitPriceSet schema.MoneyBag `json:"originalUnitPriceSet"`
+	Variant              *struct {
+		ID    string  `json:"id"`
+		Title string  `json:"title"`
+		Sku   *string `json:"sku,omitempty"`
+	} `json:"variant,omitempty"`
+	Product *struct {
+		ID     string `json:"id"`
+		Handle string `json:"handle"`
+	} `json:"product,omitempty"`
+	TaxLines []OrderTaxLine `json:"taxLines"`
+}
+
+type OrderLineItemsData struct {
+	OrderID   string `json:"id"`
+	LineItems struct {
+		Nodes []OrderLineItemNode `json:"nodes"`
+	} `json:"lineItems"`
+}
+
+type OrderLineItemsResponse struct {
+	Data struct {
+		Order OrderLineItemsData `json:"order"`
+	} `json:"data"`
+	Errors     Errors     `json:"errors"`
+	Extensions Extensions `json:"extensions"`
+}
+
+type OrderTransactionNode struct {
+	ID                string          `json:"id"`
+	Kind              string          `json:"kind"`
+	Status            string          `json:"status"`
+	Gateway           *string         `json:"gateway,omitempty"`
+	Test              bool            `json:"test"`
+	AuthorizationCode *string         `json:"authorizationCode,omitempty"`
+	ProcessedAt       *string         `json:"processedAt,omitempty"`
+	CreatedAt         string          `json:"createdAt"`
+	AmountSet         schema.MoneyBag `json:"amountSet"`
+}
+
+type OrderTransactionsData struct {
+	OrderID      string                 `json:"id"`
+	Transactions []OrderTransactionNode `json:"transactions"`
+}
+
+type OrderTransactionsResponse struct {
+	Data struct {
+		Order OrderTransactionsData `json:"order"`
+	} `json:"data"`
+	Errors     Errors     `json:"errors"`
+	Extensions Extensions `json:"extensions"`
+}
+
+type OrderFulfillmentNode struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+	Location  *struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"location,omitempty"`
+	TrackingInfo []struct {
+		Company *string `json:"company,omitempty"`
+		Number  *string `json:"number,omitempty"`
+		URL     *string `json:"url,omitempty"`
+	} `json:"trackingInfo"`
+	FulfillmentLineItems struct {
+		Nodes []struct {
+			ID       string `json:"id"`
+			Quantity int    `json:"quantity"`
+			LineItem struct {
+				ID string `json:"id"`
+			} `json:"lineItem"`
+		} `json:"nodes"`
+	} `json:"fulfillmentLineItems"`
+}
+
+type OrderFulfillmentsData struct {
+	OrderID      string                 `json:"id"`
+	Fulfillments []OrderFulfillmentNode `json:"fulfillments"`
+}
+
+type OrderFulfillmentsResponse struct {
+	Data struct {
+		Order OrderFulfillmentsData `json:"order"`
+	} `json:"data"`
+	Errors     Errors     `json:"errors"`
+	Extensions Extensions `json:"extensions"`
+}
+
+type OrderMetafieldsData struct {
+	OrderID    string `json:"id"`
+	Metafields struct {
+		Nodes []schema.Metafield `json:"nodes"`
+	} `json:"metafields"`
+}
+
+type OrderMetaFieldsResponse struct {
+	Data struct {
+		Order OrderMetafieldsData `json:"order"`
+	} `json:"data"`
+	Errors     Errors     `json:"errors"`
+	Extensions Extensions `json:"extensions"`
+}
+
+type OrderCreateResponse struct {
+	Order struct {
+		ID        string `json:"id"`
+		Name      string `json:"name"`
+		LineItems struct {
+			Nodes []struct {
+				ID string `json:"id"`
+			} `json:"nodes"`
+		} `json:"lineItems"`
+	} `json:"order"`
+	UserErrors UserErrors `json:"userErrors"`
+}
+
+type FulfillmentOrdersData struct {
+	OrderID           string `json:"id"`
+	FulfillmentOrders struct {
+		Nodes []struct {
+			ID        string `json:"id"`
+			Status    string `json:"status"`
+			LineItems struct {
+				Nodes []struct {
+					ID                string `json:"id"`
+					RemainingQuantity int    `json:"remainingQuantity"`
+					LineItem          struct {
+						ID string `json:"id"`
+					} `json:"lineItem"`
+				} `json:"nodes"`
+			} `json:"lineItems"`
+		} `json:"nodes"`
+	} `json:"fulfillmentOrders"`
+}
+
+type FulfillmentCreateResponse struct {
+	Fulfillment struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	} `json:"fulfillment"`
+	UserErrors UserErrors `json:"userErrors"`
+}
+
 type WebhookData struct {
 	Nodes    []schema.WebhookSubscription `json:"nodes"`
 	PageInfo schema.PageInfo              `json:"pageInfo"`
@@ -235,3 +533,69 @@ type WebhookDeleteResponse struct {
 	DeletedWebhookSubscriptionID string     `json:"deletedWebhookSubscriptionId"`
 	UserErrors                   UserErrors `json:"userErrors"`
 }
+
+type BulkOperationResponse struct {
+	BulkOperation *schema.BulkOperation `json:"bulkOperation"`
+	UserErrors    UserErrors            `json:"userErrors"`
+}
+
+type StagedUploadParameter struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type StagedUploadTarget struct {
+	URL         string                  `json:"url"`
+	ResourceURL *string                 `json:"resourceUrl"`
+	Parameters  []StagedUploadParameter `json:"parameters"`
+}
+
+// Path returns the path of the staged upload to refer to the uploaded file with.
+func (t StagedUploadTarget) Path() string {
+	for _, p := range t.Parameters {
+		if p.Name == "key" {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+type StagedUploadsCreateResponse struct {
+	StagedTargets []StagedUploadTarget `json:"stagedTargets"`
+	UserErrors    UserErrors           `json:"userErrors"`
+}
+
+// ProductSetVariables are the variables of a productSet mutation.
+type ProductSetVariables struct {
+	Input      schema.ProductSetInput        `json:"input"`
+	Identifier *schema.ProductSetIdentifiers `json:"identifier,omitempty"`
+}
+
+// SelectedOption is an option value of a variant.
+type SelectedOption struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ProductSetBulkResult is a line in the result of a productSet bulk mutation.
+type ProductSetBulkResult struct {
+	Data struct {
+		ProductSet struct {
+			Product *struct {
+				ID        string `json:"id"`
+				CreatedAt string `json:"createdAt"`
+				Variants  struct {
+					Nodes []struct {
+						ID              string           `json:"id"`
+						Title           string           `json:"title"`
+						Sku             *string          `json:"sku"`
+						SelectedOptions []SelectedOption `json:"selectedOptions"`
+					} `json:"nodes"`
+				} `json:"variants"`
+			} `json:"product"`
+			UserErrors UserErrors `json:"userErrors"`
+		} `json:"productSet"`
+	} `json:"data"`
+	Errors     Errors `json:"errors"`
+	LineNumber int    `json:"__lineNumber"`
+}
@@ -13,7 +13,7 @@ import (
 var ErrAddrTaken = fmt.Errorf("address for this topic has already been taken")
 
 // GetWebhooks fetches n number of webhooks after a cursor.
-func (c GQLClient) GetWebhooks(limit int, after *string, topics []schema.WebhookSubscriptionTopic, query *string) ([]schema.WebhookSubscription, error) {
+func (c GQLClient) GetWebhooks(ctx context.Context, limit int, after *string, topics []schema.WebhookSubscriptionTopic, query *string) ([]schema.WebhookSubscription, error) {
 	var out struct {
 		Data struct {
 			WebhookSubscriptions WebhookData `json:"webhookSubscriptions"`
@@ -45,7 +45,7 @@ func (c GQLClient) GetWebhooks(limit int, after *string, topics []schema.Webhook
 			"reverse": true,
 		},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -55,7 +55,7 @@ func (c GQLClient) GetWebhooks(limit int, after *string, topics []schema.Webhook
 }
 
 // SubscribeWebhook subscribes to a webhook.
-func (c GQLClient) SubscribeWebhook(topic string, endpoint string) (*schema.WebhookSubscription, error) {
+func (c GQLClient) SubscribeWebhook(ctx context.Context, topic string, endpoint string) (*schema.WebhookSubscription, error) {
 	var out struct {
 		Data struct {
 			WebhookSubscriptionCreate WebhookSyncResponse `json:"webhookSubscriptionCreate"`
@@ -93,7 +93,7 @@ func (c GQLClient) SubscribeWebhook(topic string, endpoint string) (*schema.Webh
 			},
 		},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -110,7 +110,7 @@ func (c GQLClient) SubscribeWebhook(topic string, endpoint string) (*schema.Webh
 }
 
 // GetWebhookByID fetches webhook by its ID.
-func (c GQLClient) GetWebhookByID(id string) (*schema.WebhookSubscription, error) {
+func (c GQLClient) GetWebhookByID(ctx context.Context, id string) (*schema.WebhookSubscription, error) {
 	var out struct {
 		Data struct {
 			WebhookSubscription schema.WebhookSubscription `json:"webhookSubscription"`
@@ -128,7 +128,7 @@ func (c GQLClient) GetWebhookByID(id string) (*schema.WebhookSubscription, error
 		Query:     query,
 		Variables: client.QueryVars{"id": id},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
@@ -138,7 +138,7 @@ func (c GQLClient) GetWebhookByID(id string) (*schema.WebhookSubscription, error
 }
 
 // DeleteWebhook deletes a Webhook by ID.
-func (c GQLClient) DeleteWebhook(id string) (*WebhookDeleteResponse, error) {
+func (c GQLClient) DeleteWebhook(ctx context.Context, id string) (*WebhookDeleteResponse, error) {
 	var out struct {
 		Data struct {
 			WebhookSubscriptionDelete WebhookDeleteResponse `json:"webhookSubscriptionDelete"`
@@ -163,7 +163,7 @@ func (c GQLClient) DeleteWebhook(id string) (*WebhookDeleteResponse, error) {
 			"id": id,
 		},
 	}
-	if err := c.Execute(context.Background(), req, nil, &out); err != nil {
+	if err := c.Execute(ctx, req, nil, &out); err != nil {
 		return nil, err
 	}
 	if len(out.Errors) > 0 {
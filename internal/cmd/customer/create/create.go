@@ -240,7 +240,7 @@ func run(cmd *cobra.Command, args []string, ctx *config.StoreContext, client *ap
 		TaxExemptions:         exemptions,
 	}
 
-	res, err := client.CreateCustomer(input)
+	res, err := client.CreateCustomer(cmd.Context(), input)
 	if err != nil {
 		return err
 	}
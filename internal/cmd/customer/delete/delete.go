@@ -84,20 +84,20 @@ func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 	case flag.id != "":
 		customerID = flag.id
 	case flag.email != "":
-		customer, err := client.CheckCustomerByEmailOrPhoneOrID(&flag.email, nil, "")
+		customer, err := client.CheckCustomerByEmailOrPhoneOrID(cmd.Context(), &flag.email, nil, "")
 		if err != nil {
 			return err
 		}
 		customerID = customer.ID
 	case flag.phone != "":
-		customer, err := client.CheckCustomerByEmailOrPhoneOrID(nil, &flag.phone, "")
+		customer, err := client.CheckCustomerByEmailOrPhoneOrID(cmd.Context(), nil, &flag.phone, "")
 		if err != nil {
 			return err
 		}
 		customerID = customer.ID
 	}
 
-	_, err := client.DeleteCustomer(customerID)
+	_, err := client.DeleteCustomer(cmd.Context(), customerID)
 	if err != nil {
 		return err
 	}
@@ -259,7 +259,7 @@ func run(cmd *cobra.Command, args []string, ctx *config.StoreContext, client *ap
 		return nil
 	}
 
-	customers, err := client.GetCustomers(int(flag.limit), nil, query)
+	customers, err := client.GetCustomers(cmd.Context(), int(flag.limit), nil, query)
 	if err != nil {
 		return err
 	}
@@ -209,7 +209,7 @@ func run(cmd *cobra.Command, args []string, ctx *config.StoreContext, client *ap
 	flag := &flag{}
 	flag.parse(cmd, args)
 
-	customer, err := client.GetCustomerByID(flag.id)
+	customer, err := client.GetCustomerByID(cmd.Context(), flag.id)
 	if err != nil {
 		return fmt.Errorf("customer not found")
 	}
@@ -220,13 +220,13 @@ func run(cmd *cobra.Command, args []string, ctx *config.StoreContext, client *ap
 	}
 	input := getCustomerInput(*flag, customer)
 
-	res, err := client.UpdateCustomer(*input)
+	res, err := client.UpdateCustomer(cmd.Context(), *input)
 	if err != nil {
 		return err
 	}
 	if flag.address != nil && defaultAddressID != "" {
 		addressInput := getMailingAddressInput(flag.address)
-		_, err := client.UpdateCustomerAddress(flag.id, defaultAddressID, *addressInput, true)
+		_, err := client.UpdateCustomerAddress(cmd.Context(), flag.id, defaultAddressID, *addressInput, true)
 		if err != nil {
 			return fmt.Errorf("customer updated but default address update falied: %w", err)
 		}
@@ -0,0 +1,267 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/cmdutil"
+	"github.com/ankitpokhrel/shopctl/internal/config"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	textdiff "github.com/ankitpokhrel/shopctl/pkg/diff"
+	"github.com/ankitpokhrel/shopctl/pkg/tui/table"
+)
+
+const (
+	helpText = `Diff compares two exports or an export and the store.
+
+Products are matched by their handle, variants by their title, media by their ID
+and customers by their email or phone. Fields that change on every update or
+differ between stores, like IDs and timestamps, are not compared.
+
+If only one export is given, it is compared against the current state of the store.`
+
+	examples = `# Compare two exports
+$ shopctl diff /path/to/monday.tar.gz /path/to/friday.tar.gz
+
+# Compare an export against the store of the current context
+$ shopctl diff /path/to/export/dir
+
+# Compare products only and print the changes as JSON
+$ shopctl diff /path/to/monday /path/to/friday -r product --format json
+
+# Print the changes in the unified diff format
+$ shopctl diff /path/to/monday /path/to/friday --format diff`
+
+	formatTable = "table"
+	formatJSON  = "json"
+	formatDiff  = "diff"
+
+	diffContextLines = 3
+	maxValueLen      = 60
+)
+
+type flag struct {
+	from      string
+	to        string
+	resources []engine.ResourceType
+	format    string
+}
+
+func (f *flag) parse(cmd *cobra.Command, args []string) {
+	resources, err := cmd.Flags().GetStringArray("resource")
+	cmdutil.ExitOnErr(err)
+
+	format, err := cmd.Flags().GetString("format")
+	cmdutil.ExitOnErr(err)
+
+	if !slices.Contains([]string{formatTable, formatJSON, formatDiff}, format) {
+		cmdutil.ExitOnErr(cmdutil.HelpErrorf("Error: format must be one of table, json or diff", examples))
+	}
+
+	f.resources = make([]engine.ResourceType, 0, len(resources))
+	for _, r := range resources {
+		rt := engine.ResourceType(r)
+		if rt != engine.Product && rt != engine.Customer {
+			cmdutil.ExitOnErr(cmdutil.HelpErrorf(fmt.Sprintf("Error: unsupported resource %q, only product and customer can be compared", r), examples))
+		}
+		f.resources = append(f.resources, rt)
+	}
+
+	f.from = args[0]
+	if len(args) > 1 {
+		f.to = args[1]
+	}
+	f.format = format
+}
+
+// NewCmdDiff creates a new diff command.
+func NewCmdDiff() *cobra.Command {
+	cmd := cobra.Command{
+		Use:         "diff FROM [TO]",
+		Short:       "Compare two exports or an export and the store",
+		Long:        helpText,
+		Example:     examples,
+		Annotations: map[string]string{"cmd:main": "true"},
+		Args:        cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdutil.ExitOnErr(run(cmd, args))
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayP("resource", "r", []string{string(engine.Product), string(engine.Customer)}, "Resource types to compare (product, customer)")
+	cmd.Flags().String("format", formatTable, "Output format (table, json or diff)")
+
+	cmd.Flags().SortFlags = false
+
+	return &cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	flag := &flag{}
+	flag.parse(cmd, args)
+
+	from, err := readExport(flag.from, flag.resources)
+	if err != nil {
+		return err
+	}
+
+	var (
+		to     *registry.Snapshot
+		toName = flag.to
+	)
+	if flag.to != "" {
+		to, err = readExport(flag.to, flag.resources)
+	} else {
+		toName, to, err = fetchStore(cmd, flag.resources)
+	}
+	if err != nil {
+		return err
+	}
+
+	diffs, err := registry.CompareSnapshots(from, to)
+	if err != nil {
+		return err
+	}
+
+	switch flag.format {
+	case formatJSON:
+		return printJSON(flag.from, toName, diffs)
+	case formatDiff:
+		return printUnified(diffs)
+	}
+	return printTable(diffs)
+}
+
+func readExport(path string, rts []engine.ResourceType) (*registry.Snapshot, error) {
+	reg, err := registry.NewRegistry(path)
+	if err != nil {
+		return nil, err
+	}
+	return reg.Snapshot(rts...)
+}
+
+func fetchStore(cmd *cobra.Command, rts []engine.ResourceType) (string, *registry.Snapshot, error) {
+	cfg, err := config.NewShopConfig()
+	if err != nil {
+		return "", nil, err
+	}
+
+	ctx, err := cmdutil.GetContext(cmd, cfg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	snap, err := registry.StoreSnapshot(cmd.Context(), api.NewGQLClient(ctx), rts...)
+	if err != nil {
+		return "", nil, err
+	}
+	return ctx.Store, snap, nil
+}
+
+func printJSON(from, to string, diffs []registry.ResourceDiff) error {
+	summary := map[string]int{
+		registry.StatusAdded:    0,
+		registry.StatusRemoved:  0,
+		registry.StatusModified: 0,
+	}
+	for _, d := range diffs {
+		summary[d.Status]++
+	}
+
+	out, err := json.MarshalIndent(map[string]any{
+		"from":    from,
+		"to":      to,
+		"summary": summary,
+		"changes": diffs,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+func printUnified(diffs []registry.ResourceDiff) error {
+	for _, d := range diffs {
+		old, err := indent(d.Old)
+		if err != nil {
+			return err
+		}
+		curr, err := indent(d.New)
+		if err != nil {
+			return err
+		}
+
+		name := fmt.Sprintf("%s/%s", d.Resource, d.Key)
+		fmt.Print(textdiff.Unified("a/"+name, "b/"+name, old, curr, diffContextLines))
+	}
+	return nil
+}
+
+func printTable(diffs []registry.ResourceDiff) error {
+	if len(diffs) == 0 {
+		cmdutil.Success("No differences found")
+		return nil
+	}
+
+	cols := []table.Column{
+		{Title: "Resource", Width: 18},
+		{Title: "Key", Width: 40},
+		{Title: "Status", Width: 10},
+		{Title: "Field", Width: 25},
+		{Title: "Old", Width: maxValueLen},
+		{Title: "New", Width: maxValueLen},
+	}
+
+	rows := make([]table.Row, 0, len(diffs))
+	for _, d := range diffs {
+		if d.Status != registry.StatusModified {
+			rows = append(rows, table.Row{string(d.Resource), d.Key, d.Status, "", "", ""})
+			continue
+		}
+		for _, c := range d.Changes {
+			rows = append(rows, table.Row{string(d.Resource), d.Key, d.Status, c.Field, value(c.Old), value(c.New)})
+		}
+	}
+
+	tbl := table.NewStaticTable(
+		cols, rows,
+		table.WithNoHeaders(false),
+		table.WithTableColumns([]string{"resource", "key", "status", "field", "old", "new"}),
+	)
+	if err := tbl.Render(); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "\nResources with differences: %d\n", len(diffs))
+	return nil
+}
+
+func indent(v any) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
+
+func value(v any) string {
+	if v == nil {
+		return "null"
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return strings.TrimSpace(cmdutil.ShortenAndPad(string(out), maxValueLen))
+}
@@ -0,0 +1,124 @@
+package convert
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ankitpokhrel/shopctl/internal/cmdutil"
+	conv "github.com/ankitpokhrel/shopctl/internal/convert"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+)
+
+const (
+	helpText = `Convert writes the products and customers of an export to another format offline.
+
+The sqlite format writes products, options, variants, media, metafields, customers,
+addresses and customer metafields into normalized tables keyed by their numeric ID
+so that the export can be analyzed with SQL.
+
+The csv format writes products.csv and customers.csv in the layout of the CSV files of
+the Shopify admin so that the catalog can be edited in a spreadsheet and imported back
+from the admin. Product rows are grouped by the handle, with the product details on the
+first row and the remaining variants and images on the following rows.`
+
+	examples = `# Convert an export to a SQLite database next to it, i.e. /path/to/export.db
+$ shopctl export convert /path/to/export.tar.gz --to sqlite
+
+# Convert an extracted export folder to the given file
+$ shopctl export convert /path/to/export/dir --to sqlite -o /path/to/shop.db
+
+# Find variants that are sold for less than they cost
+$ sqlite3 /path/to/shop.db "SELECT p.handle, v.sku, v.price, v.cost FROM variants v JOIN products p ON p.id = v.product_id WHERE v.price < v.cost"
+
+# Write products.csv and customers.csv compatible with the Shopify admin importer to the given directory
+$ shopctl export convert /path/to/export.tar.gz --to csv -o /path/to/catalog`
+
+	formatSQLite = "sqlite"
+	formatCSV    = "csv"
+)
+
+type flag struct {
+	path   string
+	to     string
+	output string
+}
+
+func (f *flag) parse(cmd *cobra.Command, args []string) {
+	to, err := cmd.Flags().GetString("to")
+	cmdutil.ExitOnErr(err)
+
+	output, err := cmd.Flags().GetString("output")
+	cmdutil.ExitOnErr(err)
+
+	if !slices.Contains([]string{formatSQLite, formatCSV}, to) {
+		cmdutil.ExitOnErr(cmdutil.HelpErrorf("Error: format must be one of sqlite or csv", examples))
+	}
+
+	f.path = args[0]
+	f.to = to
+	f.output = output
+	if f.output == "" {
+		base := strings.TrimSuffix(filepath.Clean(f.path), ".tar.gz")
+		if to == formatCSV {
+			f.output = base + "-csv"
+		} else {
+			f.output = base + ".db"
+		}
+	}
+}
+
+// NewCmdConvert creates a new export convert command.
+func NewCmdConvert() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "convert PATH",
+		Short:   "Convert an export to another format offline",
+		Long:    helpText,
+		Example: examples,
+		Args:    cobra.ExactArgs(1),
+		// Conversion is done offline, so we don't need a store context.
+		PersistentPreRunE: func(*cobra.Command, []string) error { return nil },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdutil.ExitOnErr(run(cmd, args))
+			return nil
+		},
+	}
+
+	cmd.Flags().String("to", formatSQLite, "Format to convert the export to (sqlite or csv)")
+	cmd.Flags().StringP("output", "o", "", "File to write the database to, or directory to write the csv files to (default next to the export)")
+
+	cmd.Flags().SortFlags = false
+
+	return &cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	flag := &flag{}
+	flag.parse(cmd, args)
+
+	reg, err := registry.NewRegistry(flag.path)
+	if err != nil {
+		return err
+	}
+
+	snap, err := reg.Snapshot(engine.Product, engine.Customer)
+	if err != nil {
+		return err
+	}
+
+	if flag.to == formatCSV {
+		err = conv.ToCSV(snap, flag.output)
+	} else {
+		err = conv.ToSQLite(snap, flag.output)
+	}
+	if err != nil {
+		return fmt.Errorf("error converting export: %w", err)
+	}
+
+	cmdutil.Success("Converted %d products and %d customers to %s", len(snap.Products), len(snap.Customers), flag.output)
+	return nil
+}
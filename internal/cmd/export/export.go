@@ -11,12 +11,17 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/cmd/export/convert"
+	"github.com/ankitpokhrel/shopctl/internal/cmd/export/verify"
 	"github.com/ankitpokhrel/shopctl/internal/cmdutil"
 	"github.com/ankitpokhrel/shopctl/internal/config"
 	"github.com/ankitpokhrel/shopctl/internal/engine"
 	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/internal/runner/backup/collection"
 	"github.com/ankitpokhrel/shopctl/internal/runner/backup/customer"
+	"github.com/ankitpokhrel/shopctl/internal/runner/backup/order"
 	"github.com/ankitpokhrel/shopctl/internal/runner/backup/product"
+	"github.com/ankitpokhrel/shopctl/pkg/gql/client"
 	"github.com/ankitpokhrel/shopctl/pkg/tlog"
 )
 
@@ -28,25 +33,59 @@ const (
 $ shopctl export -c store2 -r product -r customer -o /path/to/dir
 
 # Export premium on-sale products and customers created starting 2025
-$ shopctl export -r product="tag:on-sale AND tag:premium" -r customer=created_at:>=2025-01-01 -o /path/to/dir
+$ shopctl export -r product="tag:on-sale AND tag:premium" -r customer=customer_date:>=2025-01-01 -o /path/to/dir
+
+# Export products along with the custom and smart collections they belong to
+$ shopctl export -r product -r collection -o /path/to/dir
+
+# Export orders processed in 2024 along with their line items, transactions and fulfillments
+$ shopctl export -r order="processed_at:>=2024-01-01 AND processed_at:<2025-01-01" -o /path/to/dir
 
 # You can use 'list' command to prepare filters
 $ shopctl export -c mycontext -r product="$(shopctl product list --tags on-sale --type Bags --print-query)" -o /path/to/dir
 
+# Incremental export only fetches records updated since the last incremental export of the store.
+# Resources whose filter has changed since then are exported in full. Deleted records are not
+# tracked, so importing the export restores the records deleted since its base export as well.
+$ shopctl export -r product -r customer -o /path/to/dir --incremental
+
+# Resume an interrupted export using the export ID printed when the export started
+$ shopctl export -o /path/to/dir --resume 4f2a8c1d9e
+
 # Dry run executes the export without creating final files. This will still create files in temporary location.
 # Use this option if you want to verify your export without the risk of saving data to the unintented location.
 $ shopctl export -r product="tag:on-sale" --dry-run
+
+# Verify that an export is restorable
+$ shopctl export verify /path/to/export.tar.gz
+
+# Export products with a bulk operation. Shopify prepares the products along with their variants, media,
+# metafields and inventory in one file instead of thousands of requests. Recommended for large stores.
+$ shopctl export -r product -o /path/to/dir --bulk
+
+# Download the images, videos and 3D models of the products along with the export. Import uploads
+# the files from the export if the original media URLs are no longer reachable, e.g. if the product was deleted.
+$ shopctl export -r product -o /path/to/dir --with-media-files
+
+# Process more resources concurrently on stores with a higher API limit. Concurrency is
+# reduced automatically when the available query cost budget of the store is running low.
+$ shopctl export -r product -o /path/to/dir --workers 10
 `
 )
 
 var verbosity int
 
 type flag struct {
-	outDir    string
-	name      string
-	resources []config.BackupResource
-	dryRun    bool
-	quiet     bool
+	outDir      string
+	name        string
+	resources   []config.BackupResource
+	incremental bool
+	resume      string
+	workers     int
+	bulk        bool
+	mediaFiles  bool
+	dryRun      bool
+	quiet       bool
 }
 
 func (f *flag) parse(cmd *cobra.Command) {
@@ -59,10 +98,29 @@ func (f *flag) parse(cmd *cobra.Command) {
 	resources, err := cmd.Flags().GetStringArray("resource")
 	cmdutil.ExitOnErr(err)
 
-	if len(resources) == 0 || dir == "" {
+	resume, err := cmd.Flags().GetString("resume")
+	cmdutil.ExitOnErr(err)
+
+	if (len(resources) == 0 && resume == "") || dir == "" {
 		cmdutil.ExitOnErr(cmdutil.HelpErrorf("Error: resources to export and output directory is required", examples))
 	}
 
+	incremental, err := cmd.Flags().GetBool("incremental")
+	cmdutil.ExitOnErr(err)
+
+	workers, err := cmd.Flags().GetInt("workers")
+	cmdutil.ExitOnErr(err)
+
+	if workers < 1 {
+		cmdutil.ExitOnErr(cmdutil.HelpErrorf("Error: number of workers must be at least 1", examples))
+	}
+
+	bulk, err := cmd.Flags().GetBool("bulk")
+	cmdutil.ExitOnErr(err)
+
+	mediaFiles, err := cmd.Flags().GetBool("with-media-files")
+	cmdutil.ExitOnErr(err)
+
 	dryRun, err := cmd.Flags().GetBool("dry-run")
 	cmdutil.ExitOnErr(err)
 
@@ -72,6 +130,19 @@ func (f *flag) parse(cmd *cobra.Command) {
 	f.outDir = dir
 	f.name = name
 	f.resources = cmdutil.ParseBackupResource(resources)
+	for _, r := range f.resources {
+		if engine.ResourceType(r.Resource) != engine.Customer {
+			continue
+		}
+		if err := customer.ValidateQuery(r.Query); err != nil {
+			cmdutil.ExitOnErr(cmdutil.HelpErrorf(fmt.Sprintf("Error: %s", err), examples))
+		}
+	}
+	f.incremental = incremental
+	f.resume = resume
+	f.workers = workers
+	f.bulk = bulk
+	f.mediaFiles = mediaFiles
 	f.dryRun = dryRun
 	f.quiet = quiet
 }
@@ -101,12 +172,22 @@ func NewCmdExport() *cobra.Command {
 	cmd.Flags().StringP("output-dir", "o", "", "Root output directory to save files to")
 	cmd.Flags().StringP("name", "n", "", "Name of the generated export folder (default autogenerated)")
 	cmd.Flags().StringArrayP("resource", "r", []string{}, "Resources to export (accepts filters)")
+	cmd.Flags().Bool("incremental", false, "Only export records updated since the last incremental export")
+	cmd.Flags().String("resume", "", "Resume an interrupted export with the given export ID")
+	cmd.Flags().Int("workers", engine.DefaultWorkers, "Number of resources to process concurrently per resource type")
+	cmd.Flags().Bool("bulk", false, "Export products with a bulk operation instead of paginated queries")
+	cmd.Flags().Bool("with-media-files", false, "Download the files of the product media along with the export")
 	cmd.Flags().Bool("dry-run", false, "Print logs without creating an actual backup file")
 	cmd.Flags().Bool("quiet", false, "Do not print anything to stdout")
 	cmd.Flags().CountVarP(&verbosity, "verbose", "v", "Set the verbosity level (e.g., -v, -vv, -vvv)")
 
 	cmd.Flags().SortFlags = false
 
+	cmd.AddCommand(
+		verify.NewCmdVerify(),
+		convert.NewCmdConvert(),
+	)
+
 	return &cmd
 }
 
@@ -145,53 +226,140 @@ func run(cmd *cobra.Command, client *api.GQLClient, ctx *config.StoreContext, lo
 	flag := &flag{}
 	flag.parse(cmd)
 
-	bkpEng := engine.NewBackup(
-		ctx.Store,
-		engine.WithBackupDir(flag.name),
+	var (
+		state *config.ExportState
+		err   error
+
+		opts = []engine.Option{
+			engine.WithBackupDir(flag.name),
+			engine.WithAlias(ctx.Alias),
+			engine.WithFilters(getFilters(flag.resources)),
+		}
 	)
-	eng := engine.New(bkpEng)
+
+	if flag.incremental {
+		state, err = config.NewExportState(ctx.Store)
+		if err != nil {
+			return err
+		}
+		if base := state.LastExport(); base != nil {
+			opts = append(opts, engine.WithDeltaOf(
+				engine.BackupRef{ID: base.ID, Dir: base.Dir},
+				getWatermarks(state, flag.resources, logger),
+			))
+		}
+	}
+
+	var bkpEng *engine.Backup
+	if flag.resume != "" {
+		bkpEng, err = engine.ResumeBackup(flag.resume, opts...)
+		if err != nil {
+			return fmt.Errorf("unable to resume export %s: %w", flag.resume, err)
+		}
+		if bkpEng.Store() != ctx.Store {
+			return fmt.Errorf("export %s was started for the store %q", flag.resume, bkpEng.Store())
+		}
+		// Resources to export are restored from the checkpoint.
+		flag.resources = getResources(bkpEng.Filters())
+	} else {
+		bkpEng = engine.NewBackup(ctx.Store, opts...)
+	}
+	eng := engine.New(bkpEng, engine.WithWorkers(flag.workers), engine.WithThrottler(client))
 
 	var (
-		wg      sync.WaitGroup
-		rnr     runner.Runner
-		start   time.Time
-		counter int
+		wg          sync.WaitGroup
+		rnr         runner.Runner
+		start       time.Time
+		counter     int
+		interrupted bool
 
 		runners = make([]runner.Runner, 0, len(flag.resources))
+		prdOpts = make([]product.Option, 0)
 	)
+	if flag.mediaFiles {
+		prdOpts = append(prdOpts, product.WithMediaFiles())
+	}
 
 	defer func() {
+		// An interrupted export is not archived; resources exported so far
+		// are kept in the temp location and are not fetched again on resume.
+		if interrupted {
+			if err := bkpEng.SaveCheckpoint(); err != nil {
+				logger.Errorf("Error: unable to save export checkpoint: %s", err.Error())
+			}
+			logger.Warnf("Export %q was interrupted, use '--resume %s' to continue", bkpEng.ID(), bkpEng.ID())
+
+			if !flag.quiet && counter > 0 {
+				summarize(flag, bkpEng, runners, client.CostStats(), interrupted)
+			}
+			return
+		}
+
+		archived := true
 		if !flag.dryRun && counter > 0 {
+			if err := bkpEng.WriteManifest(); err != nil {
+				logger.Errorf("Error: unable to write manifest: %s", err.Error())
+			}
 			err := archive(bkpEng.Root(), flag.outDir, bkpEng.Dir())
 			if err != nil {
+				archived = false
 				logger.Errorf("Error: unable to archive: %s", err.Error())
+			} else if state != nil {
+				if err := saveState(state, bkpEng, flag.resources); err != nil {
+					logger.Errorf("Error: unable to save export state: %s", err.Error())
+				}
+			}
+		}
+		// Keep the checkpoint so that archiving can be retried with --resume.
+		if archived {
+			if err := bkpEng.RemoveCheckpoint(); err != nil {
+				logger.Warnf("Unable to remove export checkpoint: %s", err.Error())
 			}
 		}
 		logger.Infof("Export complete in %s", time.Since(start))
 
 		if !flag.quiet && counter > 0 {
-			summarize(flag, bkpEng, runners)
+			summarize(flag, bkpEng, runners, client.CostStats(), interrupted)
 		}
 	}()
 
 	for _, resource := range flag.resources {
 		switch engine.ResourceType(resource.Resource) {
 		case engine.Product:
-			rnr = product.NewRunner(eng, client, resource.Query, logger)
+			if flag.bulk {
+				rnr = product.NewBulkRunner(eng, client, resource.Query, logger, product.WithOptions(prdOpts...))
+			} else {
+				rnr = product.NewRunner(eng, client, resource.Query, logger, prdOpts...)
+			}
+		case engine.Collection:
+			rnr = collection.NewRunner(eng, client, resource.Query, logger)
 		case engine.Customer:
-			rnr = customer.NewRunner(eng, client, logger)
+			rnr = customer.NewRunner(eng, client, resource.Query, logger)
+		case engine.Order:
+			rnr = order.NewRunner(eng, client, resource.Query, logger)
 		default:
 			logger.Warnf("Skipping '%s': invalid resource", resource)
 			continue
 		}
+		if flag.bulk && engine.ResourceType(resource.Resource) != engine.Product {
+			logger.V(tlog.VL1).Warnf("Bulk export is only supported for products, %s will be exported with paginated queries", resource.Resource)
+		}
 		runners = append(runners, rnr)
 	}
 
 	if flag.dryRun {
 		logger.Warn("This is a dry run. API calls will be made but final backup files won't be created.")
 	}
+	if flag.resume != "" {
+		logger.Infof("Resuming export %q", bkpEng.ID())
+	} else {
+		logger.Infof("Starting export %q, use '--resume %s' to resume if it gets interrupted", bkpEng.ID(), bkpEng.ID())
+	}
 	logger.V(tlog.VL1).Infof("Using context %q", ctx.Alias)
 	logger.V(tlog.VL1).Infof("Using store %q", ctx.Store)
+	if base := bkpEng.Base(); base != nil {
+		logger.V(tlog.VL1).Infof("Exporting changes since backup %q", base.ID)
+	}
 
 	start = time.Now()
 	for _, rnr := range runners {
@@ -200,17 +368,25 @@ func run(cmd *cobra.Command, client *api.GQLClient, ctx *config.StoreContext, lo
 		go func(r runner.Runner) {
 			defer wg.Done()
 
-			if err := r.Run(); err != nil {
+			if err := r.Run(cmd.Context()); err != nil {
 				logger.Errorf("%s runner exited with err: %s", r.Kind(), err.Error())
 			}
 		}(rnr)
 	}
 	wg.Wait()
 
+	interrupted = cmd.Context().Err() != nil
+
 	for _, rnr := range runners {
 		stats := rnr.Stats()
 		counter += stats[rnr.Kind()].Count
 	}
+	// Records written before the interruption are not fetched again.
+	if flag.resume != "" {
+		for _, n := range bkpEng.Manifest().Counts {
+			counter += n
+		}
+	}
 
 	if flag.quiet {
 		return nil
@@ -229,13 +405,72 @@ func archive(from string, to string, name string) error {
 	return cmdutil.Archive(from, to, name)
 }
 
-func summarize(f *flag, bkpEng *engine.Backup, runners []runner.Runner) {
+func getFilters(resources []config.BackupResource) []engine.ResourceFilter {
+	filters := make([]engine.ResourceFilter, 0, len(resources))
+	for _, r := range resources {
+		filters = append(filters, engine.ResourceFilter{Resource: r.Resource, Query: r.Query})
+	}
+	return filters
+}
+
+func getResources(filters []engine.ResourceFilter) []config.BackupResource {
+	resources := make([]config.BackupResource, 0, len(filters))
+	for _, f := range filters {
+		resources = append(resources, config.BackupResource{Resource: f.Resource, Query: f.Query})
+	}
+	return resources
+}
+
+// getWatermarks returns the watermarks of the resources to export. Watermarks recorded with a
+// different filter are not used, and the resource is exported in full.
+func getWatermarks(state *config.ExportState, resources []config.BackupResource, logger *tlog.Logger) map[engine.ResourceType]engine.Watermark {
+	marks := make(map[engine.ResourceType]engine.Watermark)
+	for _, r := range resources {
+		wm, ok := state.Watermark(r.Resource)
+		if !ok {
+			continue
+		}
+		if !wm.Matches(r.Query) {
+			logger.Warnf("Filter of %s has changed since the last incremental export, all matching records will be exported", r.Resource)
+			continue
+		}
+		updatedAt, err := time.Parse(time.RFC3339, wm.UpdatedAt)
+		if err != nil {
+			continue
+		}
+		marks[engine.ResourceType(r.Resource)] = engine.Watermark{UpdatedAt: updatedAt, BackupID: wm.BackupID}
+	}
+	return marks
+}
+
+func saveState(state *config.ExportState, bkpEng *engine.Backup, resources []config.BackupResource) error {
+	queries := make(map[engine.ResourceType]string, len(resources))
+	for _, r := range resources {
+		queries[engine.ResourceType(r.Resource)] = r.Query
+	}
+
+	state.SetLastExport(config.BackupRef{ID: bkpEng.ID(), Dir: bkpEng.Dir()})
+	for rt, wm := range bkpEng.Watermarks() {
+		state.SetWatermark(string(rt), queries[rt], config.Watermark{
+			UpdatedAt: wm.UpdatedAt.UTC().Format(time.RFC3339),
+			BackupID:  wm.BackupID,
+		})
+	}
+	return state.Save()
+}
+
+func summarize(f *flag, bkpEng *engine.Backup, runners []runner.Runner, cost client.CostStats, interrupted bool) {
+	title, file := "EXPORT SUMMARY", bkpEng.Dir()+".tar.gz"
+	if interrupted {
+		title, file = "EXPORT SUMMARY (INTERRUPTED)", "-"
+	}
+
 	fmt.Println()
-	cmdutil.SummaryTitle("EXPORT SUMMARY", cmdutil.RepeatedEquals)
+	cmdutil.SummaryTitle(title, cmdutil.RepeatedEquals)
 	fmt.Printf(`Store: %s
 Resources: %s
 Path: %s
-File: %s.tar.gz
+File: %s
 `,
 		bkpEng.Store(),
 		func() string {
@@ -245,8 +480,11 @@ File: %s.tar.gz
 			}
 			return strings.Join(resources, ",")
 		}(),
-		f.outDir, bkpEng.Dir(),
+		f.outDir, file,
 	)
+	if base := bkpEng.Base(); base != nil {
+		fmt.Printf("Delta of: %s\n", base.ID)
+	}
 	for _, rnr := range runners {
 		fmt.Println()
 		stats := rnr.Stats()
@@ -260,4 +498,7 @@ File: %s.tar.gz
 			fmt.Println()
 		}
 	}
+
+	cmdutil.SummaryTitle("API USAGE", cmdutil.RepeatedDashes)
+	fmt.Println(cost.String())
 }
@@ -0,0 +1,65 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ankitpokhrel/shopctl/internal/cmdutil"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+)
+
+const (
+	helpText = `Verify validates an export offline.
+
+The command checks that every resource file in the export can be decoded
+the same way the import would, reports child files that have no parent and
+verifies checksums if the export has a manifest. The report is printed as
+JSON and the command exits with a non-zero status if the export is invalid.`
+
+	examples = `$ shopctl export verify /path/to/export.tar.gz
+
+# Verify an extracted export folder
+$ shopctl export verify /path/to/export/dir`
+)
+
+// NewCmdVerify creates a new export verify command.
+func NewCmdVerify() *cobra.Command {
+	return &cobra.Command{
+		Use:     "verify PATH",
+		Short:   "Verify an export offline",
+		Long:    helpText,
+		Example: examples,
+		Args:    cobra.ExactArgs(1),
+		// Verification is done offline, so we don't need a store context.
+		PersistentPreRunE: func(*cobra.Command, []string) error { return nil },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdutil.ExitOnErr(run(args[0]))
+			return nil
+		},
+	}
+}
+
+func run(path string) error {
+	reg, err := registry.NewRegistry(path)
+	if err != nil {
+		return err
+	}
+
+	report, err := reg.Verify()
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+
+	if !report.OK {
+		return fmt.Errorf("export %q failed verification", path)
+	}
+	return nil
+}
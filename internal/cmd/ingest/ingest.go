@@ -2,22 +2,27 @@ package ingest
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/ankitpokhrel/shopctl"
 	"github.com/ankitpokhrel/shopctl/internal/api"
 	"github.com/ankitpokhrel/shopctl/internal/cmdutil"
 	"github.com/ankitpokhrel/shopctl/internal/config"
 	"github.com/ankitpokhrel/shopctl/internal/engine"
 	"github.com/ankitpokhrel/shopctl/internal/registry"
 	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/internal/runner/restore/collection"
 	"github.com/ankitpokhrel/shopctl/internal/runner/restore/customer"
+	"github.com/ankitpokhrel/shopctl/internal/runner/restore/order"
 	"github.com/ankitpokhrel/shopctl/internal/runner/restore/product"
+	"github.com/ankitpokhrel/shopctl/pkg/gql/client"
 	"github.com/ankitpokhrel/shopctl/pkg/tlog"
 )
 
@@ -29,21 +34,83 @@ const (
 $ shopctl import -r product="id:id1,id2,id3 AND status:DRAFT" --from /path/to/import/dir
 
 # Restore specific products and verified customers from the latest backup
-$ shopctl import -r product="tags:premium,on-sale" -r customer="verifiedemail:true" --from /path/to/import/dir
+$ shopctl import -r product="tag:premium,on-sale" -r customer="verified_email:true" --from /path/to/import/dir
+
+# Filters use the Shopify search syntax and are evaluated against the exported data. Restore
+# products created since 2025 that are not archived and are either premium or on sale.
+$ shopctl import -r product="created_at:>=2025-01-01 -status:archived (tag:premium OR tag:on-sale)" --from /path/to/import/dir
 
 # Restore products and customers directly from the given backup path
 $ shopctl import -r product -r customer --from /path/to/import/dir
 
+# Importing from an incremental export restores the base export and all of its deltas. Records
+# deleted in the store after the base export are restored as well, as deletions are not exported.
+$ shopctl import -r product --from /path/to/dir/incremental_export.tar.gz
+
+# Import from an export that was created with a different API version or without a manifest
+$ shopctl import -r product --from /path/to/import/dir --force
+
+# Inventory quantities are restored to the locations with the same name. Skip them if
+# the stock is managed elsewhere, e.g. by an inventory management app.
+$ shopctl import -r product --from /path/to/import/dir --skip-inventory
+
+# Restore collections. Custom collections get their products back, matched by the product handle.
+$ shopctl import -r product -r collection="type:custom" --from /path/to/import/dir
+
+# Restore orders. Orders are linked to the restored customers and variants, and
+# the orders that already exist in the store, matched by their name, are left untouched.
+$ shopctl import -r product -r customer -r order --from /path/to/import/dir
+
+# Resume an interrupted or partially failed import. Resources restored
+# successfully in the earlier run are skipped and the rest are retried.
+$ shopctl import -r product -r customer --from /path/to/import/dir --resume
+
+# Resolve products and customers that can't be found by their handle or email using
+# the ID map written by an earlier import, e.g. when re-importing to a migrated store
+$ shopctl import -r product -r customer --from /path/to/import/dir --id-map /path/to/id_map.csv
+
+# Review the changes before importing. The plan lists the resources to create, update,
+# delete or leave unchanged along with the field changes. Applying the plan refuses to
+# import if the store has changed since the plan was made, and resources that change
+# while the plan is applied are reported as failed instead of being imported.
+$ shopctl import -r product -r customer --from /path/to/import/dir --plan plan.json
+$ shopctl import --apply plan.json
+
+# Migrate an export of one store to another. References to store-specific resources like
+# categories, locations, metafield definitions and media are resolved by their natural keys
+# in the target store, and the ones that can't be resolved are reported instead of failing.
+$ shopctl import -r product -r customer --from /path/to/storeA/export --target-store storeB.myshopify.com
+
 # Dry run executes the restoration process and print logs without making an actual API call
 $ shopctl import -r product --from /path/to/import/dir --dry-run -vvv
+
+# Restore products along with their options, variants, metafields and media with a bulk operation.
+# Recommended for large restores. Inventory quantities are not restored in bulk.
+$ shopctl import -r product --from /path/to/import/dir --bulk
+
+# Process more resources concurrently on stores with a higher API limit. Concurrency is
+# reduced automatically when the available query cost budget of the store is running low.
+$ shopctl import -r product --from /path/to/import/dir --workers 10
 `
 )
 
+const journalFile = "journal.jsonl"
+
 var verbosity int
 
 type flag struct {
 	from      string
+	rawRes    []string
 	resources []config.BackupResource
+	plan      string
+	apply     string
+	target    string
+	force     bool
+	resume    bool
+	idMap     string
+	skipInv   bool
+	workers   int
+	bulk      bool
 	dryRun    bool
 	quiet     bool
 }
@@ -55,12 +122,61 @@ func (f *flag) parse(cmd *cobra.Command) {
 	resources, err := cmd.Flags().GetStringArray("resource")
 	cmdutil.ExitOnErr(err)
 
+	force, err := cmd.Flags().GetBool("force")
+	cmdutil.ExitOnErr(err)
+
+	resume, err := cmd.Flags().GetBool("resume")
+	cmdutil.ExitOnErr(err)
+
+	idMap, err := cmd.Flags().GetString("id-map")
+	cmdutil.ExitOnErr(err)
+
+	skipInv, err := cmd.Flags().GetBool("skip-inventory")
+	cmdutil.ExitOnErr(err)
+
+	workers, err := cmd.Flags().GetInt("workers")
+	cmdutil.ExitOnErr(err)
+
+	if workers < 1 {
+		cmdutil.ExitOnErr(cmdutil.HelpErrorf("Error: number of workers must be at least 1", examples))
+	}
+
+	bulk, err := cmd.Flags().GetBool("bulk")
+	cmdutil.ExitOnErr(err)
+
+	if bulk && resume {
+		cmdutil.ExitOnErr(cmdutil.HelpErrorf("Error: --resume can't be used with --bulk", examples))
+	}
+
+	plan, err := cmd.Flags().GetString("plan")
+	cmdutil.ExitOnErr(err)
+
+	apply, err := cmd.Flags().GetString("apply")
+	cmdutil.ExitOnErr(err)
+
+	target, err := cmd.Flags().GetString("target-store")
+	cmdutil.ExitOnErr(err)
+
+	if bulk && target != "" {
+		cmdutil.ExitOnErr(cmdutil.HelpErrorf("Error: --target-store can't be used with --bulk", examples))
+	}
+
 	dryRun, err := cmd.Flags().GetBool("dry-run")
 	cmdutil.ExitOnErr(err)
 
 	quiet, err := cmd.Flags().GetBool("quiet")
 	cmdutil.ExitOnErr(err)
 
+	if apply != "" {
+		if from != "" || len(resources) > 0 {
+			cmdutil.ExitOnErr(cmdutil.HelpErrorf("Error: the path and resources to import are read from the plan on --apply", examples))
+		}
+		p, err := runner.LoadPlan(apply)
+		cmdutil.ExitOnErr(err)
+
+		from, resources = p.From, p.Resources
+	}
+
 	if len(resources) == 0 || from == "" {
 		cmdutil.ExitOnErr(
 			cmdutil.HelpErrorf("Error: path to the dir to import from and resources to import is required.", examples),
@@ -68,9 +184,42 @@ func (f *flag) parse(cmd *cobra.Command) {
 	}
 
 	f.from = from
+	f.rawRes = resources
 	f.resources = cmdutil.ParseBackupResource(resources)
+	f.plan = plan
+	f.apply = apply
+	f.target = target
+	f.force = force
+	f.resume = resume
+	f.idMap = idMap
+	f.skipInv = skipInv
+	f.workers = workers
+	f.bulk = bulk
 	f.dryRun = dryRun
 	f.quiet = quiet
+
+	if plan != "" || apply != "" {
+		f.validatePlan()
+	}
+}
+
+// validatePlan validates the flags used along with --plan or --apply.
+func (f *flag) validatePlan() {
+	if f.plan != "" && f.apply != "" {
+		cmdutil.ExitOnErr(cmdutil.HelpErrorf("Error: --plan can't be used with --apply", examples))
+	}
+	if f.bulk {
+		cmdutil.ExitOnErr(cmdutil.HelpErrorf("Error: --plan and --apply can't be used with --bulk", examples))
+	}
+	if f.apply != "" && f.dryRun {
+		cmdutil.ExitOnErr(cmdutil.HelpErrorf("Error: --apply can't be used with --dry-run, use --plan to review the changes", examples))
+	}
+	for _, r := range f.resources {
+		rt := engine.ResourceType(r.Resource)
+		if rt != engine.Product && rt != engine.Customer {
+			cmdutil.ExitOnErr(cmdutil.HelpErrorf(fmt.Sprintf("Error: unsupported resource %q, only product and customer can be planned", r.Resource), examples))
+		}
+	}
 }
 
 // NewCmdImport creates a new import command.
@@ -97,6 +246,15 @@ func NewCmdImport() *cobra.Command {
 	}
 	cmd.Flags().StringP("from", "f", "", "Path of the import folder to restore from")
 	cmd.Flags().StringArrayP("resource", "r", []string{}, "Resource types to restore")
+	cmd.Flags().Bool("force", false, "Import even if the export manifest is missing or doesn't match")
+	cmd.Flags().Bool("resume", false, "Skip resources restored successfully by the previous import of the same export")
+	cmd.Flags().String("id-map", "", "ID map (.csv or .json) from an earlier import to resolve resources with")
+	cmd.Flags().Bool("skip-inventory", false, "Do not restore inventory quantities of the products")
+	cmd.Flags().Int("workers", engine.DefaultWorkers, "Number of resources to process concurrently per resource type")
+	cmd.Flags().Bool("bulk", false, "Restore products with a bulk operation instead of a mutation per product")
+	cmd.Flags().String("plan", "", "Write the changes the import would make to the given file without importing")
+	cmd.Flags().String("apply", "", "Import the changes of a plan, refusing if the store has changed since")
+	cmd.Flags().String("target-store", "", "Store or context to migrate the export of another store to")
 	cmd.Flags().Bool("dry-run", false, "Print logs without creating an actual backup file")
 	cmd.Flags().Bool("quiet", false, "Do not print anything to stdout")
 	cmd.Flags().CountVarP(&verbosity, "verbose", "v", "Set the verbosity level (e.g., -v, -vv, -vvv)")
@@ -112,7 +270,7 @@ func preRun(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	ctx, err := cmdutil.GetContext(cmd, cfg)
+	ctx, err := getContext(cmd, cfg)
 	if err != nil {
 		return err
 	}
@@ -140,18 +298,32 @@ func run(cmd *cobra.Command, client *api.GQLClient, ctx *config.StoreContext, lo
 	flag := &flag{}
 	flag.parse(cmd)
 
-	var (
-		wg      sync.WaitGroup
-		rnr     runner.Runner
-		counter int
-
-		runners = make([]runner.Runner, 0, len(flag.resources))
-	)
+	chain, err := registry.ResolveChain(flag.from)
+	if err != nil {
+		return err
+	}
+	for _, path := range chain {
+		if err := verify(path, flag.force, logger); err != nil {
+			return err
+		}
+	}
 
-	eng := engine.New(engine.NewRestore(ctx.Store))
+	if m, err := registry.ReadManifest(flag.from); err == nil && m.Store != "" && m.Store != ctx.Store && flag.target == "" {
+		logger.Warnf("Export was made from store %s; use --target-store to resolve the references to its resources in %s", m.Store, ctx.Store)
+	}
 
 	dirPath := flag.from
-	if strings.HasSuffix(flag.from, ".tar.gz") {
+	if len(chain) > 1 {
+		logger.V(tlog.VL1).Infof("Merging %d incremental backups to temp location", len(chain))
+
+		tmpPath, err := registry.MergeChain(chain, cmdutil.GetBackupIDFromName(filepath.Base(flag.from)))
+		if err != nil {
+			return err
+		}
+		dirPath = tmpPath
+
+		logger.V(tlog.VL2).Infof("Backup chain %q was merged to %q", strings.Join(chain, ","), tmpPath)
+	} else if strings.HasSuffix(flag.from, ".tar.gz") {
 		logger.V(tlog.VL1).Info("Extracting backup folder to temp location")
 
 		tmpPath, err := registry.ExtractZipToTemp(flag.from, cmdutil.GetBackupIDFromName(filepath.Base(flag.from)))
@@ -163,28 +335,158 @@ func run(cmd *cobra.Command, client *api.GQLClient, ctx *config.StoreContext, lo
 		logger.V(tlog.VL2).Infof("Export folder %q was extracted to %q", flag.from, tmpPath)
 	}
 
-	// TODO:
-	// We need to maintain the order in which products, customers and orders are restored.
-	// The order should always be Product -> Customer -> Order.
+	// Inventory quantities are not part of the plan.
+	if (flag.plan != "" || flag.apply != "") && !flag.skipInv {
+		logger.Warn("Inventory quantities are not restored with --plan or --apply.")
+		flag.skipInv = true
+	}
+
+	switch {
+	case flag.plan != "":
+		// The path is resolved so that the plan can be applied from any working directory.
+		from, err := filepath.Abs(flag.from)
+		if err != nil {
+			return err
+		}
+		plan := runner.NewPlan(ctx.Store, from, flag.rawRes)
+		if err := restore(cmd, client, ctx, logger, flag, dirPath, plan); err != nil {
+			return err
+		}
+		if cmd.Context().Err() != nil {
+			return fmt.Errorf("planning was interrupted, plan was not written")
+		}
+		if err := plan.Save(flag.plan); err != nil {
+			return err
+		}
+		if !flag.quiet {
+			summarizePlan(plan, flag.plan)
+		}
+		return nil
+	case flag.apply != "":
+		saved, err := checkDrift(cmd, client, ctx, logger, flag, dirPath)
+		if err != nil {
+			return err
+		}
+		// The store may still change while the plan is applied, so each resource
+		// is checked against the plan again right before it is mutated.
+		return restore(cmd, client, ctx, logger, flag, dirPath, runner.NewApplyPlan(saved))
+	}
+
+	return restore(cmd, client, ctx, logger, flag, dirPath, nil)
+}
+
+// checkDrift plans the import again and compares it with the plan to apply.
+// It returns the plan to apply if the store hasn't changed since it was made.
+func checkDrift(cmd *cobra.Command, client *api.GQLClient, ctx *config.StoreContext, logger *tlog.Logger, f *flag, dirPath string) (*runner.Plan, error) {
+	const maxDrift = 10
+
+	saved, err := runner.LoadPlan(f.apply)
+	if err != nil {
+		return nil, err
+	}
+	if saved.Store != ctx.Store {
+		return nil, fmt.Errorf("plan %q was made for store %s, not %s", f.apply, saved.Store, ctx.Store)
+	}
+
+	logger.Infof("Checking the store for changes since the plan %q was made", f.apply)
+
+	current := runner.NewPlan(ctx.Store, f.from, f.rawRes)
+	if err := restore(cmd, client, ctx, logger, f, dirPath, current); err != nil {
+		return nil, err
+	}
+	if cmd.Context().Err() != nil {
+		return nil, fmt.Errorf("drift check was interrupted, plan was not applied")
+	}
+
+	drift := saved.Drift(current)
+	if len(drift) == 0 {
+		return saved, nil
+	}
+	if len(drift) > maxDrift {
+		drift = append(drift[:maxDrift], fmt.Sprintf("... and %d more", len(drift)-maxDrift))
+	}
+	return nil, fmt.Errorf(
+		"store has changed since the plan was made, create a new plan:\n  %s",
+		strings.Join(drift, "\n  "),
+	)
+}
+
+// restore restores the resources of the export at dirPath. The restore is
+// a dry run that records the changes to the plan if a plan is given, unless
+// the plan is applied, see `runner.NewApplyPlan`.
+func restore(cmd *cobra.Command, client *api.GQLClient, ctx *config.StoreContext, logger *tlog.Logger, flag *flag, dirPath string, plan *runner.Plan) error {
+	var (
+		rnr     runner.Runner
+		counter int
+
+		runners  = make([]runner.Runner, 0, len(flag.resources))
+		planning = plan != nil && !plan.IsApply()
+		dryRun   = flag.dryRun || planning
+	)
+
+	var (
+		opts   []engine.RestoreOption
+		idMap  *runner.IDMap
+		impDir = config.ImportDir(ctx.Store, getImportName(flag.from))
+	)
+	switch {
+	case !dryRun:
+		journal, err := openJournal(impDir, flag, logger)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = journal.Close() }()
+		opts = append(opts, engine.WithJournal(journal))
+
+		if idMap, err = getIDMap(impDir, flag); err != nil {
+			return err
+		}
+	case planning:
+		// Resources are resolved the same way as on apply, but the ID map is not saved.
+		var err error
+		if idMap, err = getIDMap(impDir, flag); err != nil {
+			return err
+		}
+	case flag.resume:
+		logger.Warn("Restore journal is not used in dry run, all resources will be processed.")
+	}
+
+	var migration *runner.Migration
+	if flag.target != "" {
+		migration = runner.NewMigration(client, idMap)
+	}
+
+	eng := engine.New(
+		engine.NewRestore(ctx.Store, opts...),
+		engine.WithWorkers(flag.workers),
+		engine.WithThrottler(client),
+	)
 
 	toRestore := make([]string, 0, len(flag.resources))
 	for _, resource := range flag.resources {
-		var filters runner.RestoreFilter
-		if resource.Query != "" {
-			conditions, separators, err := cmdutil.ParseRestoreFilters(resource.Query)
-			if err != nil {
-				return err
-			}
-			filters.Filters = conditions
-			filters.Separators = separators
+		filters, err := runner.NewRestoreFilter(resource.Query, filterFields(engine.ResourceType(resource.Resource)))
+		if err != nil {
+			return fmt.Errorf("invalid filter for %s: %w", resource.Resource, err)
 		}
 
 		toRestore = append(toRestore, resource.Resource)
 		switch engine.ResourceType(resource.Resource) {
 		case engine.Product:
-			rnr = product.NewRunner(dirPath, eng, client, logger, &filters, flag.dryRun)
+			if flag.bulk {
+				rnr = product.NewBulkRunner(dirPath, client, logger, filters, idMap, dryRun)
+				break
+			}
+			opts := []product.Option{product.WithPlan(plan), product.WithMigration(migration)}
+			if flag.skipInv {
+				opts = append(opts, product.SkipInventory())
+			}
+			rnr = product.NewRunner(dirPath, eng, client, logger, filters, idMap, dryRun, opts...)
+		case engine.Collection:
+			rnr = collection.NewRunner(dirPath, eng, client, logger, filters, idMap, dryRun)
 		case engine.Customer:
-			rnr = customer.NewRunner(dirPath, eng, client, logger, &filters, flag.dryRun)
+			rnr = customer.NewRunner(dirPath, eng, client, logger, filters, idMap, dryRun, customer.WithPlan(plan), customer.WithMigration(migration))
+		case engine.Order:
+			rnr = order.NewRunner(dirPath, eng, client, logger, filters, idMap, dryRun)
 		default:
 			logger.V(tlog.VL1).Warnf("Skipping '%s': Invalid resource", resource)
 			continue
@@ -192,49 +494,224 @@ func run(cmd *cobra.Command, client *api.GQLClient, ctx *config.StoreContext, lo
 		runners = append(runners, rnr)
 	}
 
-	if flag.dryRun {
+	switch {
+	case planning:
+		logger.Info("Planning the restore. CRUD mutations won't be executed.")
+	case dryRun:
 		logger.Warn("This is a dry run. CRUD mutations won't be executed.")
 	}
+	if migration != nil {
+		logger.Infof("Migrating export to store %s, store-specific references are resolved by their natural keys", ctx.Store)
+	}
 	logger.Infof("Starting restore for store: %s", ctx.Store)
 	logger.Infof("Resources to restore: %s", strings.Join(toRestore, ","))
 
-	start := time.Now()
+	// Runners are scheduled based on the prerequisites of their resource type so that products are
+	// restored before collections and customers, and orders are restored after products and customers.
+	sch := engine.NewScheduler()
 	for _, rnr := range runners {
-		wg.Add(1)
-
-		go func(r runner.Runner) {
-			defer wg.Done()
+		if err := sch.Add(rnr.Kind(), rnr.Run); err != nil {
+			return err
+		}
+	}
 
-			if err := r.Run(); err != nil {
-				logger.Errorf("%s runner exited with err: %s", r.Kind(), err.Error())
-			}
-		}(rnr)
+	start := time.Now()
+	if err := sch.Run(cmd.Context()); err != nil {
+		logger.Errorf("Restore runner exited with err: %s", err.Error())
 	}
 
-	wg.Wait()
-	logger.Infof("Restore complete in %s", time.Since(start))
+	interrupted := cmd.Context().Err() != nil
+	switch {
+	case interrupted && !dryRun && flag.bulk:
+		// Bulk restores can't be resumed. A bulk operation that was already submitted keeps running
+		// in the store, and products are set by their handle, so running the import again is safe.
+		logger.Warnf(
+			"Restore was interrupted after %s, a submitted bulk operation keeps running in the store; "+
+				"run the import again with --bulk once it completes to restore the remaining products", time.Since(start),
+		)
+	case interrupted && !dryRun:
+		logger.Warnf("Restore was interrupted after %s, run the import again with --resume to continue", time.Since(start))
+	case interrupted:
+		logger.Warnf("Restore was interrupted after %s", time.Since(start))
+	default:
+		logger.Infof("Restore complete in %s", time.Since(start))
+	}
 
 	for _, rnr := range runners {
 		stats := rnr.Stats()
 		counter += stats[rnr.Kind()].Count
 	}
+	if n := len(migration.Unresolved()); n > 0 {
+		logger.Warnf("%d references couldn't be resolved in the target store", n)
+	}
+	if planning {
+		return nil
+	}
+
+	if idMap != nil {
+		if err := idMap.Save(impDir); err != nil {
+			logger.Errorf("Error: unable to save id map: %s", err.Error())
+		} else {
+			logger.V(tlog.VL1).Infof("ID map was written to %q", impDir)
+		}
+	}
+	// Unresolved references are reported on dry run as well to review the migration.
+	if migration != nil {
+		if err := migration.Save(impDir); err != nil {
+			logger.Errorf("Error: unable to save unresolved references: %s", err.Error())
+		}
+	}
 
 	if !flag.quiet && counter > 0 {
-		summarize(ctx.Store, flag.from, runners)
+		summarize(ctx.Store, flag.from, idMap, migration, impDir, runners, client.CostStats(), interrupted)
 	} else if counter == 0 {
 		logger.Info("No matching records found for the given criteria")
 	}
 	return nil
 }
 
-func summarize(store string, bkpPath string, runners []runner.Runner) {
+// getContext returns the context of the store to import to. The target store
+// of a migration is looked up by its context alias or the store name.
+func getContext(cmd *cobra.Command, cfg *config.ShopConfig) (*config.StoreContext, error) {
+	target, err := cmd.Flags().GetString("target-store")
+	if err != nil {
+		return nil, err
+	}
+	if target == "" {
+		return cmdutil.GetContext(cmd, cfg)
+	}
+	if cmd.Flags().Changed("context") {
+		return nil, fmt.Errorf("--target-store can't be used with --context")
+	}
+
+	for _, c := range cfg.Contexts() {
+		if c.Alias == target || c.Store == target {
+			return &c, nil
+		}
+	}
+	return nil, fmt.Errorf("no context exists for the target store: %q", target)
+}
+
+// verify verifies the export against its manifest. Any issue
+// is logged as a warning instead if the import is forced.
+func verify(path string, force bool, logger *tlog.Logger) error {
+	var problem error
+
+	m, err := registry.VerifyChecksums(path)
+	switch {
+	case errors.Is(err, registry.ErrNoManifest):
+		problem = fmt.Errorf("export %q doesn't have a manifest", path)
+	case err != nil:
+		problem = fmt.Errorf("export %q failed verification: %w", path, err)
+	case m.APIVersion != shopctl.ShopifyApiVersion:
+		problem = fmt.Errorf(
+			"export %q was created with API version %s but the current API version is %s",
+			path, m.APIVersion, shopctl.ShopifyApiVersion,
+		)
+	}
+
+	if problem == nil {
+		logger.V(tlog.VL2).Infof("Export %q was verified against its manifest", path)
+		return nil
+	}
+	if force {
+		logger.Warnf("%s; continuing as the import is forced", problem.Error())
+		return nil
+	}
+	return fmt.Errorf("%w; use --force to import anyway", problem)
+}
+
+// openJournal opens the restore journal in the import dir.
+func openJournal(dir string, f *flag, logger *tlog.Logger) (*engine.Journal, error) {
+	path := filepath.Join(dir, journalFile)
+
+	journal, err := engine.OpenJournal(path, f.from, f.resume)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open restore journal: %w", err)
+	}
+
+	if f.resume {
+		logger.Infof("Resuming restore using journal %q", path)
+	} else {
+		logger.V(tlog.VL1).Infof("Recording restore journal to %q", path)
+	}
+	return journal, nil
+}
+
+// getIDMap builds the ID map to record the restored resources to. IDs are
+// resolved from the given ID map file. A resumed import continues the ID map
+// of the previous run so that the resources skipped on resume are kept.
+func getIDMap(dir string, f *flag) (*runner.IDMap, error) {
+	var base *runner.IDMap
+	if f.idMap != "" {
+		m, err := runner.LoadIDMap(f.idMap)
+		if err != nil {
+			return nil, err
+		}
+		base = m
+	}
+
+	idMap := runner.NewIDMap(runner.WithBase(base))
+	if f.resume {
+		prev, err := runner.LoadIDMap(filepath.Join(dir, runner.IDMapFileJSON))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		idMap.Merge(prev)
+	}
+	return idMap, nil
+}
+
+// filterFields returns the fields the resources of the given type can be filtered by.
+func filterFields(rt engine.ResourceType) []string {
+	switch rt {
+	case engine.Product:
+		return product.FilterFields()
+	case engine.Collection:
+		return collection.FilterFields()
+	case engine.Customer:
+		return customer.FilterFields()
+	case engine.Order:
+		return order.FilterFields()
+	}
+	return nil
+}
+
+// getImportName returns the name to identify imports of the export with.
+// The export is identified by its ID if it can be determined.
+func getImportName(path string) string {
+	if m, err := registry.ReadManifest(path); err == nil && m.ID != "" {
+		return m.ID
+	}
+	name := filepath.Base(path)
+	if id := cmdutil.GetBackupIDFromName(name); id != "" {
+		return id
+	}
+	return strings.TrimSuffix(name, ".tar.gz")
+}
+
+func summarize(
+	store string,
+	bkpPath string,
+	idMap *runner.IDMap,
+	migration *runner.Migration,
+	impDir string,
+	runners []runner.Runner,
+	cost client.CostStats,
+	interrupted bool,
+) {
 	resources := make([]string, 0, len(runners))
 	for _, rnr := range runners {
 		resources = append(resources, string(rnr.Kind()))
 	}
 
+	title := "RESTORE SUMMARY"
+	if interrupted {
+		title = "RESTORE SUMMARY (INTERRUPTED)"
+	}
+
 	fmt.Println()
-	cmdutil.SummaryTitle("RESTORE SUMMARY", cmdutil.RepeatedEquals)
+	cmdutil.SummaryTitle(title, cmdutil.RepeatedEquals)
 	fmt.Printf(`Store: %s
 Path used: %s
 Resources: %s
@@ -242,17 +719,17 @@ Resources: %s
 		store, bkpPath,
 		strings.Join(resources, ","),
 	)
+	if idMap != nil {
+		fmt.Printf("ID map: %s\n", filepath.Join(impDir, runner.IDMapFileCSV))
+	}
+	if migration != nil {
+		fmt.Printf("Unresolved references: %d (%s)\n", len(migration.Unresolved()), filepath.Join(impDir, runner.UnresolvedFileCSV))
+	}
 
 	for _, rnr := range runners {
 		fmt.Println()
 		stats := rnr.Stats()
 		for _, rt := range engine.GetAllResourceTypes() {
-			// ProductOption count is not accurate as
-			// we are handling them separately now.
-			// TODO: Better summary implementation.
-			if rt == engine.ProductOption {
-				continue
-			}
 			st, ok := stats[rt]
 			if !ok {
 				continue
@@ -266,4 +743,46 @@ Resources: %s
 			fmt.Println()
 		}
 	}
+
+	cmdutil.SummaryTitle("API USAGE", cmdutil.RepeatedDashes)
+	fmt.Println(cost.String())
+}
+
+func summarizePlan(plan *runner.Plan, path string) {
+	counts := make(map[engine.ResourceType]map[string]int)
+	for _, e := range plan.Entries {
+		if _, ok := counts[e.Resource]; !ok {
+			counts[e.Resource] = make(map[string]int)
+		}
+		counts[e.Resource][e.Action]++
+	}
+
+	fmt.Println()
+	cmdutil.SummaryTitle("PLAN SUMMARY", cmdutil.RepeatedEquals)
+	fmt.Printf(`Store: %s
+Path used: %s
+Resources: %s
+Plan: %s
+`,
+		plan.Store, plan.From,
+		strings.Join(plan.Resources, ","),
+		path,
+	)
+
+	fmt.Println()
+	for _, rt := range engine.GetAllResourceTypes() {
+		c, ok := counts[rt]
+		if !ok {
+			continue
+		}
+		fmt.Printf(
+			"%-20s create: %d, update: %d, delete: %d, unchanged: %d\n",
+			rt, c[runner.PlanCreate], c[runner.PlanUpdate], c[runner.PlanDelete], c[runner.PlanUnchanged],
+		)
+	}
+	fmt.Println()
+	fmt.Printf(
+		"Total: create: %d, update: %d, delete: %d, unchanged: %d\n",
+		plan.Summary[runner.PlanCreate], plan.Summary[runner.PlanUpdate], plan.Summary[runner.PlanDelete], plan.Summary[runner.PlanUnchanged],
+	)
 }
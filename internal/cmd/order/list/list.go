@@ -243,7 +243,7 @@ func run(cmd *cobra.Command, args []string, ctx *config.StoreContext, client *ap
 		return nil
 	}
 
-	orders, err := client.GetOrders(int(flag.limit), nil, query)
+	orders, err := client.GetOrders(cmd.Context(), int(flag.limit), nil, query)
 	if err != nil {
 		return err
 	}
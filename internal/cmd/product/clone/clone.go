@@ -1,6 +1,7 @@
 package clone
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -121,7 +122,7 @@ func run(cmd *cobra.Command, args []string, ctx *config.StoreContext, client *ap
 	flag := &flag{}
 	flag.parse(cmd, args)
 
-	product, err := client.GetProductByID(flag.id)
+	product, err := client.GetProductByID(cmd.Context(), flag.id)
 	if err != nil {
 		return fmt.Errorf("product not found")
 	}
@@ -221,19 +222,19 @@ func run(cmd *cobra.Command, args []string, ctx *config.StoreContext, client *ap
 		client = api.NewGQLClient(ctx)
 	}
 
-	res, err := client.CreateProduct(input)
+	res, err := client.CreateProduct(cmd.Context(), input)
 	if err != nil {
 		return err
 	}
 
 	if flag.variants {
-		_, err := cloneProductVariants(res.Product.ID, product.Variants.Nodes, client)
+		_, err := cloneProductVariants(cmd.Context(), res.Product.ID, product.Variants.Nodes, client)
 		if err != nil {
 			cmdutil.Fail("There were some errors when cloning product variants")
 		}
 	}
 	if flag.media {
-		_, err := cloneProductMedia(res.Product.ID, tags, product.Media.Nodes, client)
+		_, err := cloneProductMedia(cmd.Context(), res.Product.ID, tags, product.Media.Nodes, client)
 		if err != nil {
 			cmdutil.Fail("There were some errors when cloning product media")
 		}
@@ -253,7 +254,7 @@ func run(cmd *cobra.Command, args []string, ctx *config.StoreContext, client *ap
 	return nil
 }
 
-func cloneProductVariants(productID string, toAdd []schema.ProductVariant, client *api.GQLClient) (*api.ProductVariantsSyncResponse, error) {
+func cloneProductVariants(ctx context.Context, productID string, toAdd []schema.ProductVariant, client *api.GQLClient) (*api.ProductVariantsSyncResponse, error) {
 	if len(toAdd) == 0 {
 		return nil, nil
 	}
@@ -298,10 +299,10 @@ func cloneProductVariants(productID string, toAdd []schema.ProductVariant, clien
 		variantsInput = append(variantsInput, input)
 	}
 
-	return client.CreateProductVariants(productID, variantsInput, schema.ProductVariantsBulkCreateStrategyRemoveStandaloneVariant)
+	return client.CreateProductVariants(ctx, productID, variantsInput, schema.ProductVariantsBulkCreateStrategyRemoveStandaloneVariant)
 }
 
-func cloneProductMedia(productID string, tags []any, toAdd []any, client *api.GQLClient) (*api.ProductCreateResponse, error) {
+func cloneProductMedia(ctx context.Context, productID string, tags []any, toAdd []any, client *api.GQLClient) (*api.ProductCreateResponse, error) {
 	if len(toAdd) == 0 {
 		return nil, nil
 	}
@@ -329,7 +330,7 @@ func cloneProductMedia(productID string, tags []any, toAdd []any, client *api.GQ
 			MediaContentType: schema.MediaContentType(mct),
 		})
 	}
-	return client.UpdateProduct(input, createMediaInput)
+	return client.UpdateProduct(ctx, input, createMediaInput)
 }
 
 func getOptions(options []any) []any {
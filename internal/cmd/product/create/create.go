@@ -148,7 +148,7 @@ func run(cmd *cobra.Command, args []string, ctx *config.StoreContext, client *ap
 		GiftCard:        &flag.isGiftCard,
 	}
 
-	res, err := client.CreateProduct(input)
+	res, err := client.CreateProduct(cmd.Context(), input)
 	if err != nil {
 		return err
 	}
@@ -36,10 +36,10 @@ func NewCmdDelete() *cobra.Command {
 	}
 }
 
-func run(_ *cobra.Command, args []string, client *api.GQLClient) error {
+func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 	productID := shopctl.ShopifyProductID(args[0])
 
-	_, err := client.DeleteProduct(productID)
+	_, err := client.DeleteProduct(cmd.Context(), productID)
 	if err != nil {
 		return err
 	}
@@ -249,7 +249,7 @@ func run(cmd *cobra.Command, args []string, ctx *config.StoreContext, client *ap
 		return nil
 	}
 
-	products, err := client.GetProducts(int(flag.limit), nil, query)
+	products, err := client.GetProducts(cmd.Context(), int(flag.limit), nil, query)
 	if err != nil {
 		return err
 	}
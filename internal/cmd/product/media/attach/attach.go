@@ -99,7 +99,7 @@ func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 		MediaContentType: flag.typ,
 	}
 
-	res, err := client.UpdateProduct(input, []schema.CreateMediaInput{createMediaInput})
+	res, err := client.UpdateProduct(cmd.Context(), input, []schema.CreateMediaInput{createMediaInput})
 	if err != nil {
 		return err
 	}
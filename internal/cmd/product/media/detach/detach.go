@@ -95,7 +95,7 @@ func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 		})
 	}
 
-	_, err := client.DetachProductMedia(input)
+	_, err := client.DetachProductMedia(cmd.Context(), input)
 	if err != nil {
 		return err
 	}
@@ -111,7 +111,7 @@ func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 		})
 	}
 
-	res, err := client.CreateProductOptions(flag.id, []schema.OptionCreateInput{opt}, flag.create)
+	res, err := client.CreateProductOptions(cmd.Context(), flag.id, []schema.OptionCreateInput{opt}, flag.create)
 	if err != nil {
 		return err
 	}
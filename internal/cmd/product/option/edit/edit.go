@@ -108,7 +108,7 @@ func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 		os.Exit(0)
 	}
 
-	productOptions, err := client.GetProductOptions(flag.id)
+	productOptions, err := client.GetProductOptions(cmd.Context(), flag.id)
 	if err != nil {
 		return err
 	}
@@ -172,6 +172,7 @@ func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 	}
 
 	res, err := client.UpdateProductOptions(
+		cmd.Context(),
 		flag.id, &opt,
 		optionValuesToCreate,
 		optionValuesToUpdate,
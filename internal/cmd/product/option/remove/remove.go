@@ -61,7 +61,7 @@ func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 	flag := &flag{}
 	flag.parse(cmd, args)
 
-	productOptions, err := client.GetProductOptions(flag.id)
+	productOptions, err := client.GetProductOptions(cmd.Context(), flag.id)
 	if err != nil {
 		return err
 	}
@@ -85,7 +85,7 @@ func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 		os.Exit(0)
 	}
 
-	res, err := client.DeleteProductOptions(flag.id, optionsToDelete)
+	res, err := client.DeleteProductOptions(cmd.Context(), flag.id, optionsToDelete)
 	if err != nil {
 		return err
 	}
@@ -95,7 +95,7 @@ func run(cmd *cobra.Command, args []string, ctx *config.StoreContext, client *ap
 		}
 		product, err = reg.GetProductByID(shopctl.ExtractNumericID(flag.id))
 	} else {
-		product, err = client.GetProductByID(flag.id)
+		product, err = client.GetProductByID(cmd.Context(), flag.id)
 	}
 	if err != nil {
 		return err
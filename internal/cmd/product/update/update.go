@@ -151,13 +151,13 @@ func run(cmd *cobra.Command, args []string, ctx *config.StoreContext, client *ap
 		os.Exit(0)
 	}
 
-	product, err := client.GetProductByID(flag.id)
+	product, err := client.GetProductByID(cmd.Context(), flag.id)
 	if err != nil {
 		return fmt.Errorf("product not found")
 	}
 	input := getInput(*flag, product)
 
-	res, err := client.UpdateProduct(*input, nil)
+	res, err := client.UpdateProduct(cmd.Context(), *input, nil)
 	if err != nil {
 		return err
 	}
@@ -224,7 +224,7 @@ func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 		Taxable:      &flag.isTaxable,
 		TaxCode:      &flag.taxcode,
 	}
-	res, err := client.CreateProductVariants(flag.id, []schema.ProductVariantsBulkInput{input}, flag.strategy)
+	res, err := client.CreateProductVariants(cmd.Context(), flag.id, []schema.ProductVariantsBulkInput{input}, flag.strategy)
 	if err != nil {
 		return err
 	}
@@ -223,9 +223,9 @@ func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 	)
 
 	if flag.variantID != "" {
-		variant, err = client.GetProductVariantByID(flag.variantID)
+		variant, err = client.GetProductVariantByID(cmd.Context(), flag.variantID)
 	} else {
-		variant, err = client.GetProductVariantByTitle(flag.id, flag.title, true)
+		variant, err = client.GetProductVariantByTitle(cmd.Context(), flag.id, flag.title, true)
 	}
 	if err != nil {
 		return err
@@ -234,6 +234,7 @@ func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 
 	input := getInput(*flag, variant)
 	res, err := client.UpdateProductVariants(
+		cmd.Context(),
 		flag.id, []schema.ProductVariantsBulkInput{input},
 		false, /* We don't allow partial update */
 	)
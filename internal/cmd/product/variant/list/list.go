@@ -168,7 +168,7 @@ func run(cmd *cobra.Command, args []string, ctx *config.StoreContext, client *ap
 	flag := &flag{}
 	flag.parse(cmd, args)
 
-	product, err := client.GetProductVariants(flag.productID)
+	product, err := client.GetProductVariants(cmd.Context(), flag.productID)
 	if err != nil {
 		return err
 	}
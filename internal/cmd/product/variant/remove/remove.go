@@ -37,7 +37,7 @@ func NewCmdRemove() *cobra.Command {
 	}
 }
 
-func run(_ *cobra.Command, args []string, client *api.GQLClient) error {
+func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 	productID := shopctl.ShopifyProductID(args[0])
 	variantIDs := args[1:]
 
@@ -52,14 +52,14 @@ func run(_ *cobra.Command, args []string, client *api.GQLClient) error {
 			}
 			title := strings.Join(parts, " / ")
 
-			variant, err := client.GetProductVariantByTitle(productID, title, false)
+			variant, err := client.GetProductVariantByTitle(cmd.Context(), productID, title, false)
 			if err != nil {
 				variantsSkipped = append(variantsSkipped, id)
 			} else {
 				variantsToDelete = append(variantsToDelete, variant.ID)
 			}
 		} else {
-			variant, err := client.CheckProductVariantByID(vid)
+			variant, err := client.CheckProductVariantByID(cmd.Context(), vid)
 			if err != nil {
 				variantsSkipped = append(variantsSkipped, vid)
 			} else {
@@ -76,7 +76,7 @@ func run(_ *cobra.Command, args []string, client *api.GQLClient) error {
 		return nil
 	}
 
-	res, err := client.DeleteProductVariants(productID, variantsToDelete)
+	res, err := client.DeleteProductVariants(cmd.Context(), productID, variantsToDelete)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,220 @@
+//nolint:mnd
+package query
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/ankitpokhrel/shopctl"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+// column is a field of a resource that can be both filtered by and printed.
+type column[T any] struct {
+	name  string
+	title string
+	width int
+	date  bool
+	value func(*T) []string
+}
+
+// variant is a product variant along with the product it belongs to.
+type variant struct {
+	product *schema.Product
+	variant *schema.ProductVariant
+}
+
+var productColumns = []column[registry.SnapshotProduct]{
+	{name: "id", title: "ID", width: 15, value: func(p *registry.SnapshotProduct) []string {
+		return []string{shopctl.ExtractNumericID(p.Product.ID)}
+	}},
+	{name: "handle", title: "Handle", width: 30, value: func(p *registry.SnapshotProduct) []string {
+		return []string{p.Product.Handle}
+	}},
+	{name: "title", title: "Title", width: 50, value: func(p *registry.SnapshotProduct) []string {
+		return []string{p.Product.Title}
+	}},
+	{name: "status", title: "Status", width: 10, value: func(p *registry.SnapshotProduct) []string {
+		return []string{string(p.Product.Status)}
+	}},
+	{name: "vendor", title: "Vendor", width: 20, value: func(p *registry.SnapshotProduct) []string {
+		return []string{p.Product.Vendor}
+	}},
+	{name: "product_type", title: "Product Type", width: 20, value: func(p *registry.SnapshotProduct) []string {
+		return []string{p.Product.ProductType}
+	}},
+	{name: "category", title: "Category", width: 25, value: func(p *registry.SnapshotProduct) []string {
+		if p.Product.Category == nil {
+			return nil
+		}
+		return []string{p.Product.Category.Name}
+	}},
+	{name: "tag", title: "Tags", width: 25, value: func(p *registry.SnapshotProduct) []string {
+		return runner.StringValues(p.Product.Tags)
+	}},
+	{name: "gift_card", title: "Gift Card", width: 10, value: func(p *registry.SnapshotProduct) []string {
+		return []string{strconv.FormatBool(p.Product.IsGiftCard)}
+	}},
+	{name: "variants", title: "Variants", width: 8, value: func(p *registry.SnapshotProduct) []string {
+		return []string{strconv.Itoa(len(p.Variants))}
+	}},
+	{name: "media", title: "Media", width: 8, value: func(p *registry.SnapshotProduct) []string {
+		return []string{strconv.Itoa(len(p.Media))}
+	}},
+	{name: "inventory_total", title: "Inventory Total", width: 15, value: func(p *registry.SnapshotProduct) []string {
+		return []string{strconv.Itoa(p.Product.TotalInventory)}
+	}},
+	{name: "min_price", title: "Min Price", width: 10, value: func(p *registry.SnapshotProduct) []string {
+		return []string{formatMoney(p.Product.PriceRangeV2.MinVariantPrice.Amount)}
+	}},
+	{name: "max_price", title: "Max Price", width: 10, value: func(p *registry.SnapshotProduct) []string {
+		return []string{formatMoney(p.Product.PriceRangeV2.MaxVariantPrice.Amount)}
+	}},
+	{name: "created_at", title: "Created", width: 20, date: true, value: func(p *registry.SnapshotProduct) []string {
+		return []string{p.Product.CreatedAt}
+	}},
+	{name: "updated_at", title: "Updated", width: 20, date: true, value: func(p *registry.SnapshotProduct) []string {
+		return []string{p.Product.UpdatedAt}
+	}},
+	{name: "published_at", title: "Published", width: 20, date: true, value: func(p *registry.SnapshotProduct) []string {
+		return runner.OptionalValue(p.Product.PublishedAt)
+	}},
+}
+
+var variantColumns = []column[variant]{
+	{name: "product_id", title: "Product ID", width: 15, value: func(v *variant) []string {
+		return []string{shopctl.ExtractNumericID(v.product.ID)}
+	}},
+	{name: "handle", title: "Handle", width: 30, value: func(v *variant) []string {
+		return []string{v.product.Handle}
+	}},
+	{name: "product", title: "Product", width: 40, value: func(v *variant) []string {
+		return []string{v.product.Title}
+	}},
+	{name: "status", title: "Status", width: 10, value: func(v *variant) []string {
+		return []string{string(v.product.Status)}
+	}},
+	{name: "id", title: "ID", width: 15, value: func(v *variant) []string {
+		return []string{shopctl.ExtractNumericID(v.variant.ID)}
+	}},
+	{name: "title", title: "Title", width: 30, value: func(v *variant) []string {
+		return []string{v.variant.Title}
+	}},
+	{name: "sku", title: "SKU", width: 20, value: func(v *variant) []string {
+		return runner.OptionalValue(v.variant.Sku)
+	}},
+	{name: "barcode", title: "Barcode", width: 20, value: func(v *variant) []string {
+		return runner.OptionalValue(v.variant.Barcode)
+	}},
+	{name: "price", title: "Price", width: 10, value: func(v *variant) []string {
+		return []string{v.variant.Price}
+	}},
+	{name: "compare_at_price", title: "Compare At Price", width: 10, value: func(v *variant) []string {
+		return runner.OptionalValue(v.variant.CompareAtPrice)
+	}},
+	{name: "cost", title: "Cost", width: 10, value: func(v *variant) []string {
+		if cost, ok := unitCost(v.variant); ok {
+			return []string{formatMoney(cost)}
+		}
+		return nil
+	}},
+	{name: "margin", title: "Margin", width: 10, value: func(v *variant) []string {
+		cost, ok := unitCost(v.variant)
+		if !ok {
+			return nil
+		}
+		price, err := strconv.ParseFloat(v.variant.Price, 64)
+		if err != nil {
+			return nil
+		}
+		return []string{formatMoney(price - cost)}
+	}},
+	{name: "inventory_quantity", title: "Inventory Quantity", width: 10, value: func(v *variant) []string {
+		if v.variant.InventoryQuantity == nil {
+			return nil
+		}
+		return []string{strconv.Itoa(*v.variant.InventoryQuantity)}
+	}},
+	{name: "taxable", title: "Taxable", width: 8, value: func(v *variant) []string {
+		return []string{strconv.FormatBool(v.variant.Taxable)}
+	}},
+	{name: "created_at", title: "Created", width: 20, date: true, value: func(v *variant) []string {
+		return []string{v.variant.CreatedAt}
+	}},
+	{name: "updated_at", title: "Updated", width: 20, date: true, value: func(v *variant) []string {
+		return []string{v.variant.UpdatedAt}
+	}},
+}
+
+var customerColumns = []column[schema.Customer]{
+	{name: "id", title: "ID", width: 15, value: func(c *schema.Customer) []string {
+		return []string{shopctl.ExtractNumericID(c.ID)}
+	}},
+	{name: "first_name", title: "First Name", width: 20, value: func(c *schema.Customer) []string {
+		return runner.OptionalValue(c.FirstName)
+	}},
+	{name: "last_name", title: "Last Name", width: 20, value: func(c *schema.Customer) []string {
+		return runner.OptionalValue(c.LastName)
+	}},
+	{name: "email", title: "Email", width: 30, value: func(c *schema.Customer) []string {
+		return runner.OptionalValue(c.Email)
+	}},
+	{name: "phone", title: "Phone", width: 15, value: func(c *schema.Customer) []string {
+		return runner.OptionalValue(c.Phone)
+	}},
+	{name: "state", title: "State", width: 10, value: func(c *schema.Customer) []string {
+		return []string{string(c.State)}
+	}},
+	{name: "tag", title: "Tags", width: 25, value: func(c *schema.Customer) []string {
+		return runner.StringValues(c.Tags)
+	}},
+	{name: "country", title: "Country", width: 15, value: func(c *schema.Customer) []string {
+		if c.DefaultAddress == nil {
+			return nil
+		}
+		return runner.OptionalValue(c.DefaultAddress.Country)
+	}},
+	{name: "verified_email", title: "Verified Email", width: 8, value: func(c *schema.Customer) []string {
+		return []string{strconv.FormatBool(c.VerifiedEmail)}
+	}},
+	{name: "accepts_marketing", title: "Accepts Marketing", width: 8, value: func(c *schema.Customer) []string {
+		subscribed := c.EmailMarketingConsent != nil && c.EmailMarketingConsent.MarketingState == schema.CustomerEmailMarketingStateSubscribed
+		return []string{strconv.FormatBool(subscribed)}
+	}},
+	{name: "orders_count", title: "Orders Count", width: 8, value: func(c *schema.Customer) []string {
+		return []string{c.NumberOfOrders}
+	}},
+	{name: "total_spent", title: "Total Spent", width: 10, value: func(c *schema.Customer) []string {
+		return []string{formatMoney(c.AmountSpent.Amount)}
+	}},
+	{name: "created_at", title: "Created", width: 20, date: true, value: func(c *schema.Customer) []string {
+		return []string{c.CreatedAt}
+	}},
+	{name: "updated_at", title: "Updated", width: 20, date: true, value: func(c *schema.Customer) []string {
+		return []string{c.UpdatedAt}
+	}},
+}
+
+// defaultValues are the values free text in the expression is matched against.
+func defaultValues[T any](cols []column[T], res *T, names ...string) []string {
+	var out []string
+	for _, c := range cols {
+		if slices.Contains(names, c.name) {
+			out = append(out, c.value(res)...)
+		}
+	}
+	return out
+}
+
+func unitCost(v *schema.ProductVariant) (float64, bool) {
+	if v.InventoryItem == nil || v.InventoryItem.UnitCost == nil {
+		return 0, false
+	}
+	return v.InventoryItem.UnitCost.Amount, true
+}
+
+func formatMoney(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -0,0 +1,353 @@
+package query
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.design/x/clipboard"
+
+	"github.com/ankitpokhrel/shopctl/internal/cmdutil"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/pkg/fmtout"
+	"github.com/ankitpokhrel/shopctl/pkg/search"
+	"github.com/ankitpokhrel/shopctl/pkg/tui/table"
+)
+
+const (
+	helpText = `Query filters and prints products, variants or customers of an export.
+
+The expression uses the Shopify search syntax and is evaluated locally, so
+the export can be explored without access to the store. Fields that can be
+used in the expression are the same as the columns that can be printed.
+See https://shopify.dev/docs/api/usage/search-syntax`
+
+	examples = `# List all products in an export
+$ shopctl query /path/to/export.tar.gz
+
+# List active products tagged 'on-sale' created this year
+$ shopctl query /path/to/export/dir "status:active tag:on-sale created_at:>=2025-01-01"
+
+# Find variants that are sold for less than they cost
+$ shopctl query /path/to/export.tar.gz "margin:<0" -r variant --columns handle,sku,price,cost,margin
+
+# List customers that spent more than 100 in a plain table view
+$ shopctl query /path/to/export.tar.gz "total_spent:>100" -r customer --plain
+
+# Print products without media as a csv
+$ shopctl query /path/to/export.tar.gz "media:0" --csv --columns id,handle,title`
+
+	resourceProduct  = "product"
+	resourceVariant  = "variant"
+	resourceCustomer = "customer"
+)
+
+type flag struct {
+	path       string
+	expression string
+	resource   string
+	columns    []string
+	limit      int
+	plain      bool
+	csv        bool
+	noHeaders  bool
+}
+
+func (f *flag) parse(cmd *cobra.Command, args []string) {
+	resource, err := cmd.Flags().GetString("resource")
+	cmdutil.ExitOnErr(err)
+
+	columns, err := cmd.Flags().GetString("columns")
+	cmdutil.ExitOnErr(err)
+
+	limit, err := cmd.Flags().GetInt("limit")
+	cmdutil.ExitOnErr(err)
+
+	plain, err := cmd.Flags().GetBool("plain")
+	cmdutil.ExitOnErr(err)
+
+	csv, err := cmd.Flags().GetBool("csv")
+	cmdutil.ExitOnErr(err)
+
+	noHeaders, err := cmd.Flags().GetBool("no-headers")
+	cmdutil.ExitOnErr(err)
+
+	var valid []string
+	switch resource {
+	case resourceProduct:
+		valid = columnNames(productColumns)
+	case resourceVariant:
+		valid = columnNames(variantColumns)
+	case resourceCustomer:
+		valid = columnNames(customerColumns)
+	default:
+		cmdutil.ExitOnErr(cmdutil.HelpErrorf("Error: resource must be one of product, variant or customer", examples))
+	}
+
+	f.path = args[0]
+	if len(args) > 1 {
+		f.expression = args[1]
+	}
+	f.resource = resource
+	f.columns = func() []string {
+		if columns != "" {
+			return strings.Split(columns, ",")
+		}
+		return []string{}
+	}()
+	for _, c := range f.columns {
+		if !slices.Contains(valid, c) {
+			cmdutil.Fail("Error: column names should be one of: %s", strings.Join(valid, ", "))
+			os.Exit(1)
+		}
+	}
+	f.limit = limit
+	f.plain = plain
+	f.csv = csv
+	f.noHeaders = noHeaders
+}
+
+// NewCmdQuery constructs a new query command.
+func NewCmdQuery() *cobra.Command {
+	cmd := cobra.Command{
+		Use:     "query BACKUP [EXPRESSION]",
+		Short:   "Query filters and prints resources of an export",
+		Long:    helpText,
+		Example: examples,
+		Args:    cobra.RangeArgs(1, 2),
+		Annotations: map[string]string{
+			"cmd:main": "true",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdutil.ExitOnErr(run(cmd, args))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("resource", "r", resourceProduct, "Resource to query (product, variant or customer)")
+	cmd.Flags().String("columns", "", "Comma separated list of columns to print")
+	cmd.Flags().Int("limit", 0, "Number of entries to print, all if 0")
+	cmd.Flags().Bool("plain", false, "Show output in properly formatted plain text")
+	cmd.Flags().Bool("csv", false, "Print output in csv")
+	cmd.Flags().Bool("no-headers", false, "Don't print table headers (works only with --plain and --csv)")
+
+	cmd.Flags().SortFlags = false
+
+	return &cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	flag := &flag{}
+	flag.parse(cmd, args)
+
+	var filter *search.Filter
+	if flag.expression != "" {
+		f, err := search.ParseFilter(flag.expression)
+		if err != nil {
+			return fmt.Errorf("invalid expression: %w", err)
+		}
+		filter = f
+	}
+
+	reg, err := registry.NewRegistry(flag.path)
+	if err != nil {
+		return err
+	}
+
+	rt := engine.Product
+	if flag.resource == resourceCustomer {
+		rt = engine.Customer
+	}
+	snap, err := reg.Snapshot(rt)
+	if err != nil {
+		return err
+	}
+
+	cols, rows, err := query(snap, flag, filter)
+	if err != nil {
+		return err
+	}
+
+	// Only the projected columns are printed, in the given order.
+	keys := make([]string, 0, len(cols))
+	for _, c := range cols {
+		keys = append(keys, keyme(c.Title))
+	}
+
+	if len(rows) == 0 {
+		cmdutil.Warn("No %ss found for the given expression", flag.resource)
+		return nil
+	}
+
+	if flag.plain {
+		tbl := table.NewStaticTable(
+			cols, rows,
+			table.WithNoHeaders(flag.noHeaders),
+			table.WithTableColumns(keys),
+		)
+		return tbl.Render()
+	}
+	if flag.csv {
+		csvfmt := fmtout.NewCSV(
+			table.ColsToString(cols),
+			table.RowsToString(rows),
+			fmtout.WithNoHeaders(flag.noHeaders),
+			fmtout.WithColumns(keys),
+		)
+		return csvfmt.Format(os.Stdout)
+	}
+
+	helpTexts := []string{
+		"↑ k/j ↓: Navigate top & down",
+		"← h/l →: Navigate left & right",
+		"m: Toggle distraction free mode",
+		"c: Copy the value of the first column",
+		"q/CTRL+c/ESC: Quit",
+	}
+	footerTexts := []string{
+		fmt.Sprintf("Showing %d %ss from %q", len(rows), flag.resource, flag.path),
+	}
+	if flag.expression != "" {
+		footerTexts = append(footerTexts, fmt.Sprintf("Expression: %s", flag.expression))
+	}
+
+	tbl := table.NewInteractiveTable(
+		cols, rows,
+		table.WithHelpTexts(helpTexts),
+		table.WithFooterTexts(footerTexts),
+		table.WithCopyFunc(func(val string, _ string) error {
+			if err := clipboard.Init(); err == nil {
+				_ = clipboard.Write(clipboard.FmtText, []byte(val))
+			}
+			return nil
+		}),
+	)
+	return tbl.Render()
+}
+
+// query filters the resources of the snapshot and returns the selected columns of
+// the matching resources. Default columns are used if no columns are selected.
+func query(snap *registry.Snapshot, flag *flag, filter *search.Filter) ([]table.Column, []table.Row, error) {
+	var (
+		cols []table.Column
+		rows []table.Row
+		err  error
+	)
+	switch flag.resource {
+	case resourceProduct:
+		if len(flag.columns) == 0 {
+			flag.columns = []string{"id", "title", "status", "product_type", "variants", "updated_at"}
+		}
+		cols, rows, err = evaluate(sortByID(snap.Products), productColumns, []string{"title", "handle", "vendor", "product_type"}, filter, flag.columns, flag.limit)
+	case resourceVariant:
+		var variants []*variant
+		for _, p := range sortByID(snap.Products) {
+			for i := range p.Variants {
+				variants = append(variants, &variant{product: &p.Product, variant: &p.Variants[i]})
+			}
+		}
+		if len(flag.columns) == 0 {
+			flag.columns = []string{"product_id", "id", "product", "title", "sku", "price", "cost", "margin"}
+		}
+		cols, rows, err = evaluate(variants, variantColumns, []string{"product", "title", "sku", "barcode"}, filter, flag.columns, flag.limit)
+	case resourceCustomer:
+		if len(flag.columns) == 0 {
+			flag.columns = []string{"id", "first_name", "last_name", "email", "total_spent", "created_at"}
+		}
+		cols, rows, err = evaluate(sortByID(snap.Customers), customerColumns, []string{"first_name", "last_name", "email", "phone"}, filter, flag.columns, flag.limit)
+	}
+	return cols, rows, err
+
+}
+
+// evaluate filters the resources by the expression and projects the selected columns.
+// Free text in the expression is matched against the values of the given search columns.
+func evaluate[T any](records []*T, cols []column[T], searchCols []string, filter *search.Filter, columns []string, limit int) ([]table.Column, []table.Row, error) {
+	byName := make(map[string]column[T], len(cols))
+	for _, c := range cols {
+		byName[c.name] = c
+	}
+
+	if filter != nil {
+		if err := filter.Validate(append(columnNames(cols), search.DefaultField)); err != nil {
+			return nil, nil, fmt.Errorf("invalid expression: %w", err)
+		}
+	}
+
+	selected := make([]column[T], 0, len(columns))
+	for _, name := range columns {
+		selected = append(selected, byName[name])
+	}
+
+	tcols := make([]table.Column, 0, len(selected))
+	for _, c := range selected {
+		tcols = append(tcols, table.Column{Title: c.title, Width: c.width})
+	}
+
+	rows := make([]table.Row, 0)
+	for _, rec := range records {
+		if limit > 0 && len(rows) >= limit {
+			break
+		}
+		matched := filter.Match(func(field string) []string {
+			if field == search.DefaultField {
+				return defaultValues(cols, rec, searchCols...)
+			}
+			if c, ok := byName[field]; ok {
+				return c.value(rec)
+			}
+			return nil
+		})
+		if !matched {
+			continue
+		}
+
+		row := make(table.Row, 0, len(selected))
+		for _, c := range selected {
+			vals := c.value(rec)
+			if c.date {
+				for i, v := range vals {
+					vals[i] = cmdutil.FormatDateTime(v, "")
+				}
+			}
+			row = append(row, strings.Join(vals, ","))
+		}
+		rows = append(rows, row)
+	}
+
+	return tcols, rows, nil
+}
+
+func columnNames[T any](cols []column[T]) []string {
+	names := make([]string, 0, len(cols))
+	for _, c := range cols {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// sortByID returns the resources of the snapshot ordered by their numeric ID.
+func sortByID[T any](resources map[string]*T) []*T {
+	ids := make([]string, 0, len(resources))
+	for id := range resources {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(a), len(b)), strings.Compare(a, b))
+	})
+
+	out := make([]*T, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, resources[id])
+	}
+	return out
+}
+
+func keyme(s string) string {
+	s = strings.ToLower(s)
+	return strings.ReplaceAll(s, " ", "_")
+}
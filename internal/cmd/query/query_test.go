@@ -0,0 +1,178 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/pkg/search"
+	"github.com/ankitpokhrel/shopctl/pkg/tui/table"
+)
+
+func TestQuery(t *testing.T) {
+	reg, err := registry.NewRegistry("../../registry/testdata/bkp")
+	assert.NoError(t, err)
+
+	snap, err := reg.Snapshot(engine.Product, engine.Customer)
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name       string
+		resource   string
+		expression string
+		columns    []string
+		limit      int
+		titles     []string
+		rows       []table.Row
+		err        string
+	}{
+		{
+			name:     "product default columns",
+			resource: resourceProduct,
+			titles:   []string{"ID", "Title", "Status", "Product Type", "Variants", "Updated"},
+			rows:     []table.Row{{"8737843216608", "Test Product", "ACTIVE", "Shirts", "2", "2025-01-12 09:10:00"}},
+		},
+		{
+			name:       "product filtered by fields",
+			resource:   resourceProduct,
+			expression: "status:active tag:premium created_at:<2025-01-01",
+			columns:    []string{"handle", "id", "tag"},
+			titles:     []string{"Handle", "ID", "Tags"},
+			rows:       []table.Row{{"test-product", "8737843216608", "premium,on-sale"}},
+		},
+		{
+			name:       "product filtered by free text",
+			resource:   resourceProduct,
+			expression: "shirts",
+			columns:    []string{"handle"},
+			titles:     []string{"Handle"},
+			rows:       []table.Row{{"test-product"}},
+		},
+		{
+			name:       "product not matching",
+			resource:   resourceProduct,
+			expression: "tag:premium -status:active",
+			columns:    []string{"handle"},
+			titles:     []string{"Handle"},
+			rows:       []table.Row{},
+		},
+		{
+			name:       "variant sold below cost",
+			resource:   resourceVariant,
+			expression: "margin:<0",
+			columns:    []string{"sku", "price", "cost", "margin"},
+			titles:     []string{"SKU", "Price", "Cost", "Margin"},
+			rows:       []table.Row{{"TP-M", "19.99", "21.00", "-1.01"}},
+		},
+		{
+			name:     "variant with limit",
+			resource: resourceVariant,
+			columns:  []string{"product_id", "sku"},
+			limit:    1,
+			titles:   []string{"Product ID", "SKU"},
+			rows:     []table.Row{{"8737843216608", "TP-S"}},
+		},
+		{
+			name:       "customer filtered by fields",
+			resource:   resourceCustomer,
+			expression: "total_spent:>100 country:Germany tag:vip",
+			columns:    []string{"email", "total_spent", "orders_count"},
+			titles:     []string{"Email", "Total Spent", "Orders Count"},
+			rows:       []table.Row{{"jon@example.com", "120.50", "3"}},
+		},
+		{
+			name:       "unsupported field",
+			resource:   resourceCustomer,
+			expression: "margin:<0",
+			err:        `invalid expression: unsupported field "margin"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var filter *search.Filter
+			if tc.expression != "" {
+				f, err := search.ParseFilter(tc.expression)
+				assert.NoError(t, err)
+				filter = f
+			}
+
+			cols, rows, err := query(snap, &flag{resource: tc.resource, columns: tc.columns, limit: tc.limit}, filter)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+
+			titles := make([]string, 0, len(cols))
+			for _, c := range cols {
+				titles = append(titles, c.Title)
+			}
+			assert.Equal(t, tc.titles, titles)
+			assert.Equal(t, tc.rows, rows)
+		})
+	}
+}
+
+func TestEvaluateColumns(t *testing.T) {
+	reg, err := registry.NewRegistry("../../registry/testdata/bkp")
+	assert.NoError(t, err)
+
+	snap, err := reg.Snapshot(engine.Product)
+	assert.NoError(t, err)
+
+	products := sortByID(snap.Products)
+
+	cases := []struct {
+		name    string
+		columns []string
+		titles  []string
+		row     table.Row
+	}{
+		{
+			name:    "selected order is kept",
+			columns: []string{"title", "id"},
+			titles:  []string{"Title", "ID"},
+			row:     table.Row{"Test Product", "8737843216608"},
+		},
+		{
+			name:    "multiple values are joined",
+			columns: []string{"tag"},
+			titles:  []string{"Tags"},
+			row:     table.Row{"premium,on-sale"},
+		},
+		{
+			name:    "counts",
+			columns: []string{"variants", "media", "inventory_total"},
+			titles:  []string{"Variants", "Media", "Inventory Total"},
+			row:     table.Row{"2", "1", "50"},
+		},
+		{
+			name:    "dates are formatted",
+			columns: []string{"created_at", "updated_at"},
+			titles:  []string{"Created", "Updated"},
+			row:     table.Row{"2024-11-03 16:36:15", "2025-01-12 09:10:00"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cols, rows, err := evaluate(products, productColumns, nil, nil, tc.columns, 0)
+			assert.NoError(t, err)
+
+			titles := make([]string, 0, len(cols))
+			for _, c := range cols {
+				titles = append(titles, c.Title)
+			}
+			assert.Equal(t, tc.titles, titles)
+			assert.Equal(t, []table.Row{tc.row}, rows)
+		})
+	}
+}
+
+func TestKeyme(t *testing.T) {
+	assert.Equal(t, "product_type", keyme("Product Type"))
+	assert.Equal(t, "compare_at_price", keyme("Compare At Price"))
+}
@@ -6,10 +6,12 @@ import (
 	"github.com/ankitpokhrel/shopctl/internal/cmd/auth"
 	"github.com/ankitpokhrel/shopctl/internal/cmd/config"
 	"github.com/ankitpokhrel/shopctl/internal/cmd/customer"
+	"github.com/ankitpokhrel/shopctl/internal/cmd/diff"
 	"github.com/ankitpokhrel/shopctl/internal/cmd/export"
 	"github.com/ankitpokhrel/shopctl/internal/cmd/ingest"
 	"github.com/ankitpokhrel/shopctl/internal/cmd/order"
 	"github.com/ankitpokhrel/shopctl/internal/cmd/product"
+	"github.com/ankitpokhrel/shopctl/internal/cmd/query"
 	"github.com/ankitpokhrel/shopctl/internal/cmd/version"
 	"github.com/ankitpokhrel/shopctl/internal/cmd/webhook"
 )
@@ -46,6 +48,8 @@ func addChildCommands(cmd *cobra.Command) {
 		order.NewCmdOrder(),
 		export.NewCmdExport(),
 		ingest.NewCmdImport(),
+		diff.NewCmdDiff(),
+		query.NewCmdQuery(),
 		webhook.NewCmdWebhook(),
 		version.NewCmdVersion(),
 	)
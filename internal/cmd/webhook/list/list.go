@@ -140,7 +140,7 @@ func run(cmd *cobra.Command, args []string, ctx *config.StoreContext, client *ap
 
 	query := buildSearchQuery(flag)
 
-	webhooks, err := client.GetWebhooks(int(flag.limit), nil, flag.topics, query)
+	webhooks, err := client.GetWebhooks(cmd.Context(), int(flag.limit), nil, flag.topics, query)
 	if err != nil {
 		return err
 	}
@@ -11,9 +11,7 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
-	"os/signal"
 	"strings"
-	"syscall"
 
 	"github.com/spf13/cobra"
 
@@ -118,7 +116,7 @@ func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 	)
 
 	if flag.id != "" {
-		sub, err = client.GetWebhookByID(flag.id)
+		sub, err = client.GetWebhookByID(cmd.Context(), flag.id)
 		if err != nil {
 			return err
 		}
@@ -127,7 +125,7 @@ func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 		fmt.Printf("Webhook ID %q is registerd for topic %q with endpoint %q\n", sub.ID, sub.Topic, cbURL)
 	} else {
 		// Register webhook to Shopify.
-		sub, err = client.SubscribeWebhook(flag.topic, flag.url)
+		sub, err = client.SubscribeWebhook(cmd.Context(), flag.topic, flag.url)
 		if err != nil && !errors.Is(err, api.ErrAddrTaken) {
 			return err
 		}
@@ -147,9 +145,8 @@ func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 		return handle(flag.exec, data)
 	})
 
-	q := make(chan os.Signal, 1)
-	signal.Notify(q, syscall.SIGINT, syscall.SIGTERM)
-	<-q
+	// Context of the command is cancelled on interrupt.
+	<-cmd.Context().Done()
 
 	return nil
 }
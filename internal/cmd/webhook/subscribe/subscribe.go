@@ -60,7 +60,7 @@ func run(cmd *cobra.Command, _ []string, client *api.GQLClient) error {
 	flag := &flag{}
 	flag.parse(cmd)
 
-	res, err := client.SubscribeWebhook(flag.topic, flag.url)
+	res, err := client.SubscribeWebhook(cmd.Context(), flag.topic, flag.url)
 	if err != nil {
 		return err
 	}
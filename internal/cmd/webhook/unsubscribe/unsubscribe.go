@@ -36,10 +36,10 @@ func NewCmdUnsubscribe() *cobra.Command {
 	}
 }
 
-func run(_ *cobra.Command, args []string, client *api.GQLClient) error {
+func run(cmd *cobra.Command, args []string, client *api.GQLClient) error {
 	subID := shopctl.ShopifyWebhookSubscriptionID(args[0])
 
-	_, err := client.DeleteWebhook(subID)
+	_, err := client.DeleteWebhook(cmd.Context(), subID)
 	if err != nil {
 		return err
 	}
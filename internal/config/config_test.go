@@ -40,3 +40,42 @@ func TestConfigSave(t *testing.T) {
 
 	assert.NoError(t, os.RemoveAll("./testdata/.tmp/"))
 }
+
+func TestExportState(t *testing.T) {
+	store := "teststore.myshopify.com"
+	root := "./testdata/.tmp/shopctl"
+
+	t.Setenv("SHOPIFY_CONFIG_HOME", "./testdata/.tmp/")
+
+	s, err := NewExportState(store)
+	assert.NoError(t, err)
+	assert.Nil(t, s.LastExport())
+	_, ok := s.Watermark("product")
+	assert.False(t, ok)
+
+	s.SetLastExport(BackupRef{ID: "abc123", Dir: "base"})
+	s.SetWatermark("product", "tag:premium", Watermark{UpdatedAt: "2025-01-01T10:00:00Z", BackupID: "abc123"})
+	s.SetWatermark("customer", "", Watermark{UpdatedAt: "2025-01-02T10:00:00Z", BackupID: "abc123"})
+	assert.NoError(t, s.Save())
+	assert.FileExists(t, fmt.Sprintf("%s/state/%s.yml", root, store))
+
+	s, err = NewExportState(store)
+	assert.NoError(t, err)
+	assert.Equal(t, &BackupRef{ID: "abc123", Dir: "base"}, s.LastExport())
+
+	wm, ok := s.Watermark("product")
+	assert.True(t, ok)
+	assert.Equal(t, "2025-01-01T10:00:00Z", wm.UpdatedAt)
+	assert.Equal(t, "abc123", wm.BackupID)
+	assert.True(t, wm.Matches("tag:premium"))
+	assert.False(t, wm.Matches("tag:on-sale"))
+	assert.False(t, wm.Matches(""))
+
+	wm, ok = s.Watermark("customer")
+	assert.True(t, ok)
+	assert.Empty(t, wm.Filter)
+	assert.True(t, wm.Matches(""))
+	assert.False(t, wm.Matches("tag:vip"))
+
+	assert.NoError(t, os.RemoveAll("./testdata/.tmp/"))
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+
+	"github.com/knadh/koanf/providers/structs"
+	"github.com/knadh/koanf/v2"
+)
+
+const (
+	stateDir  = "state"
+	importDir = "imports"
+)
+
+// Watermark is the point up to which a resource has been exported.
+type Watermark struct {
+	UpdatedAt string `koanf:"updatedAt" yaml:"updatedAt"`
+	BackupID  string `koanf:"backupId" yaml:"backupId"`
+	// Filter is the checksum of the filter query the resource was exported with.
+	Filter string `koanf:"filter" yaml:"filter,omitempty"`
+}
+
+// Matches tells if the watermark was recorded for the given filter query. Records that match
+// the query but not the filter of the watermark may be older than the watermark, so the
+// watermark can't be used to export them incrementally.
+func (w Watermark) Matches(query string) bool {
+	return w.Filter == filterChecksum(query)
+}
+
+// BackupRef is a reference to an export.
+type BackupRef struct {
+	ID  string `koanf:"id" yaml:"id"`
+	Dir string `koanf:"dir" yaml:"dir"`
+}
+
+type stateItems struct {
+	Store      string               `koanf:"store" yaml:"store"`
+	LastExport *BackupRef           `koanf:"lastExport" yaml:"lastExport,omitempty"`
+	Watermarks map[string]Watermark `koanf:"watermarks" yaml:"watermarks,omitempty"`
+}
+
+// ExportState keeps track of incremental exports of a store.
+type ExportState struct {
+	*config
+	data stateItems
+}
+
+// NewExportState constructs export state for the given store.
+func NewExportState(store string) (*ExportState, error) {
+	cfg, err := newConfig(filepath.Join(home(), stateDir), store, fileTypeYaml)
+	if err != nil {
+		return nil, err
+	}
+
+	var item stateItems
+	if err := cfg.writer.Unmarshal("", &item); err != nil {
+		return nil, err
+	}
+	item.Store = store
+	if item.Watermarks == nil {
+		item.Watermarks = make(map[string]Watermark)
+	}
+
+	return &ExportState{
+		config: cfg,
+		data:   item,
+	}, nil
+}
+
+// LastExport returns the last recorded export, if any.
+func (s *ExportState) LastExport() *BackupRef {
+	return s.data.LastExport
+}
+
+// SetLastExport records the last export.
+func (s *ExportState) SetLastExport(ref BackupRef) {
+	s.data.LastExport = &ref
+}
+
+// Watermark returns the recorded watermark of the resource type, if any.
+func (s *ExportState) Watermark(resource string) (Watermark, bool) {
+	wm, ok := s.data.Watermarks[resource]
+	return wm, ok
+}
+
+// SetWatermark records the watermark for the resource type exported with the given filter query.
+func (s *ExportState) SetWatermark(resource, query string, wm Watermark) {
+	wm.Filter = filterChecksum(query)
+	s.data.Watermarks[resource] = wm
+}
+
+// Save saves the export state to the file.
+func (s *ExportState) Save() error {
+	k := koanf.New(".")
+
+	if err := k.Load(structs.Provider(s.data, "yaml"), nil); err != nil {
+		return err
+	}
+	if err := s.writer.Merge(k); err != nil {
+		return err
+	}
+	return writeYAML(s.path, s.data)
+}
+
+// filterChecksum returns the checksum of the filter query. It is
+// empty if there is no filter to match the state written before
+// the filters were recorded.
+func filterChecksum(query string) string {
+	if query == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// ImportDir returns the dir to keep the restore journal and
+// the ID map of the imports of a backup to the given store.
+func ImportDir(store, backupID string) string {
+	return filepath.Join(home(), importDir, store, backupID)
+}
@@ -0,0 +1,39 @@
+// Package convert converts exports to formats that can be used outside of shopctl.
+package convert
+
+import (
+	"encoding/json"
+
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+// selectedOption is an option value of a variant.
+type selectedOption struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func selectedOptions(options []any) []selectedOption {
+	return decodeNodes[selectedOption](options)
+}
+
+func mailingAddresses(addresses []any) []schema.MailingAddress {
+	return decodeNodes[schema.MailingAddress](addresses)
+}
+
+// decodeNodes decodes the untyped nodes of a connection. Nodes that can't be decoded are skipped.
+func decodeNodes[T any](nodes []any) []T {
+	out := make([]T, 0, len(nodes))
+	for _, n := range nodes {
+		data, err := json.Marshal(n)
+		if err != nil {
+			continue
+		}
+		var v T
+		if err := json.Unmarshal(data, &v); err != nil {
+			continue
+		}
+		out = append(out, v)
+	}
+	return out
+}
@@ -0,0 +1,284 @@
+//nolint:mnd
+package convert
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/pkg/fmtout"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+// maxCSVOptions is the number of options the product CSV has columns for.
+const maxCSVOptions = 3
+
+// productCSVColumns are the columns of the product CSV of the Shopify admin.
+// See https://help.shopify.com/en/manual/products/import-export/using-csv
+var productCSVColumns = []string{
+	"Handle", "Title", "Body (HTML)", "Vendor", "Product Category", "Type", "Tags", "Published",
+	"Option1 Name", "Option1 Value", "Option2 Name", "Option2 Value", "Option3 Name", "Option3 Value",
+	"Variant SKU", "Variant Grams", "Variant Inventory Tracker", "Variant Inventory Qty", "Variant Inventory Policy",
+	"Variant Fulfillment Service", "Variant Price", "Variant Compare At Price", "Variant Requires Shipping",
+	"Variant Taxable", "Variant Barcode", "Image Src", "Image Position", "Image Alt Text", "Gift Card",
+	"SEO Title", "SEO Description", "Variant Image", "Variant Weight Unit", "Cost per item", "Status",
+}
+
+// customerCSVColumns are the columns of the customer CSV of the Shopify admin.
+// See https://help.shopify.com/en/manual/customers/import-export-customers
+var customerCSVColumns = []string{
+	"First Name", "Last Name", "Email", "Accepts Email Marketing", "Default Address Company",
+	"Default Address Address1", "Default Address Address2", "Default Address City", "Default Address Province Code",
+	"Default Address Country Code", "Default Address Zip", "Default Address Phone", "Phone",
+	"Accepts SMS Marketing", "Tags", "Note", "Tax Exempt",
+}
+
+// ToCSV writes the products and customers of the snapshot to products.csv and customers.csv in the given
+// directory. Files are only written for the resources in the snapshot and existing files are not overwritten.
+func ToCSV(snap *registry.Snapshot, dir string) error {
+	files := []struct {
+		name  string
+		count int
+		write func(*registry.Snapshot, io.Writer) error
+	}{
+		{name: "products.csv", count: len(snap.Products), write: ProductCSV},
+		{name: "customers.csv", count: len(snap.Customers), write: CustomerCSV},
+	}
+	for _, f := range files {
+		if _, err := os.Stat(filepath.Join(dir, f.name)); err == nil {
+			return fmt.Errorf("file %q already exists", filepath.Join(dir, f.name))
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.count == 0 {
+			continue
+		}
+		if err := writeFile(filepath.Join(dir, f.name), func(w io.Writer) error { return f.write(snap, w) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProductCSV writes the products of the snapshot in the layout of the product CSV of the Shopify admin so that
+// it can be edited in a spreadsheet and imported back from the admin. The first row of a product holds the
+// product details and following rows hold its remaining variants and images, identified by the handle.
+func ProductCSV(snap *registry.Snapshot, w io.Writer) error {
+	rows := make([][]string, 0, len(snap.Products))
+	for _, id := range sortedIDs(snap.Products) {
+		rows = append(rows, productCSVRows(snap.Products[id])...)
+	}
+	return writeCSV(w, productCSVColumns, rows)
+}
+
+// CustomerCSV writes the customers of the snapshot in the layout of the customer CSV of the Shopify admin.
+func CustomerCSV(snap *registry.Snapshot, w io.Writer) error {
+	rows := make([][]string, 0, len(snap.Customers))
+	for _, id := range sortedIDs(snap.Customers) {
+		rows = append(rows, customerCSVRow(snap.Customers[id]))
+	}
+	return writeCSV(w, customerCSVColumns, rows)
+}
+
+func productCSVRows(p *registry.SnapshotProduct) [][]string {
+	var (
+		product = &p.Product
+		options = slices.Clone(product.Options)
+		images  = make([]api.ProductMediaNode, 0, len(p.Media))
+		rows    = make([][]string, 0, max(len(p.Variants), 1))
+	)
+	slices.SortFunc(options, func(a, b schema.ProductOption) int { return cmp.Compare(a.Position, b.Position) })
+	for _, m := range p.Media {
+		if m.MediaContentType == schema.MediaContentTypeImage && m.Preview.Image != nil {
+			images = append(images, m)
+		}
+	}
+
+	for i := 0; i < max(len(p.Variants), len(images), 1); i++ {
+		row := make(map[string]string, len(productCSVColumns))
+		row["Handle"] = product.Handle
+
+		if i == 0 {
+			row["Title"] = product.Title
+			row["Body (HTML)"] = product.DescriptionHtml
+			row["Vendor"] = product.Vendor
+			row["Type"] = product.ProductType
+			row["Tags"] = strings.Join(runner.StringValues(product.Tags), ", ")
+			row["Published"] = csvBool(product.PublishedAt != nil)
+			row["Gift Card"] = csvBool(product.IsGiftCard)
+			row["SEO Title"] = value(product.Seo.Title)
+			row["SEO Description"] = value(product.Seo.Description)
+			row["Status"] = strings.ToLower(string(product.Status))
+			if product.Category != nil {
+				row["Product Category"] = product.Category.FullName
+			}
+			for j, o := range options[:min(len(options), maxCSVOptions)] {
+				row["Option"+strconv.Itoa(j+1)+" Name"] = o.Name
+			}
+		}
+		if i < len(p.Variants) {
+			variantCSVRow(row, &p.Variants[i], options)
+		}
+		if i < len(images) {
+			row["Image Src"] = images[i].Preview.Image.URL
+			row["Image Position"] = strconv.Itoa(i + 1)
+			row["Image Alt Text"] = value(images[i].Preview.Image.AltText)
+		}
+
+		out := make([]string, 0, len(productCSVColumns))
+		for _, c := range productCSVColumns {
+			out = append(out, row[c])
+		}
+		rows = append(rows, out)
+	}
+	return rows
+}
+
+func variantCSVRow(row map[string]string, v *schema.ProductVariant, options []schema.ProductOption) {
+	selected := selectedOptions(v.SelectedOptions)
+	for j, o := range options[:min(len(options), maxCSVOptions)] {
+		idx := slices.IndexFunc(selected, func(s selectedOption) bool { return s.Name == o.Name })
+		if idx >= 0 {
+			row["Option"+strconv.Itoa(j+1)+" Value"] = selected[idx].Value
+		}
+	}
+
+	row["Variant SKU"] = value(v.Sku)
+	row["Variant Inventory Policy"] = strings.ToLower(string(v.InventoryPolicy))
+	row["Variant Fulfillment Service"] = "manual"
+	row["Variant Price"] = v.Price
+	row["Variant Compare At Price"] = value(v.CompareAtPrice)
+	row["Variant Taxable"] = csvBool(v.Taxable)
+	row["Variant Barcode"] = value(v.Barcode)
+	if v.InventoryQuantity != nil {
+		row["Variant Inventory Qty"] = strconv.Itoa(*v.InventoryQuantity)
+	}
+	if v.Image != nil {
+		row["Variant Image"] = v.Image.URL
+	}
+
+	item := v.InventoryItem
+	if item == nil {
+		return
+	}
+	row["Variant Requires Shipping"] = csvBool(item.RequiresShipping)
+	if item.Tracked {
+		row["Variant Inventory Tracker"] = "shopify"
+	}
+	if item.UnitCost != nil {
+		row["Cost per item"] = strconv.FormatFloat(item.UnitCost.Amount, 'f', 2, 64)
+	}
+	if w := item.Measurement.Weight; w != nil {
+		grams, unit := weightInGrams(w)
+		row["Variant Grams"] = strconv.FormatFloat(math.Round(grams), 'f', 0, 64)
+		row["Variant Weight Unit"] = unit
+	}
+}
+
+func customerCSVRow(c *schema.Customer) []string {
+	row := map[string]string{
+		"First Name":              value(c.FirstName),
+		"Last Name":               value(c.LastName),
+		"Email":                   value(c.Email),
+		"Phone":                   value(c.Phone),
+		"Tags":                    strings.Join(runner.StringValues(c.Tags), ", "),
+		"Note":                    value(c.Note),
+		"Tax Exempt":              yesNo(c.TaxExempt),
+		"Accepts Email Marketing": yesNo(c.EmailMarketingConsent != nil && c.EmailMarketingConsent.MarketingState == schema.CustomerEmailMarketingStateSubscribed),
+		"Accepts SMS Marketing":   yesNo(c.SmsMarketingConsent != nil && c.SmsMarketingConsent.MarketingState == schema.CustomerSmsMarketingStateSubscribed),
+	}
+	if a := c.DefaultAddress; a != nil {
+		row["Default Address Company"] = value(a.Company)
+		row["Default Address Address1"] = value(a.Address1)
+		row["Default Address Address2"] = value(a.Address2)
+		row["Default Address City"] = value(a.City)
+		row["Default Address Province Code"] = value(a.ProvinceCode)
+		row["Default Address Zip"] = value(a.Zip)
+		row["Default Address Phone"] = value(a.Phone)
+		if a.CountryCodeV2 != nil {
+			row["Default Address Country Code"] = string(*a.CountryCodeV2)
+		}
+	}
+
+	out := make([]string, 0, len(customerCSVColumns))
+	for _, col := range customerCSVColumns {
+		out = append(out, row[col])
+	}
+	return out
+}
+
+// writeFile writes to a new file at the given path. The file is removed if the write fails.
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := errors.Join(write(f), f.Close()); err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+	return nil
+}
+
+func writeCSV(w io.Writer, cols []string, rows [][]string) error {
+	keys := make([]string, 0, len(cols))
+	for _, c := range cols {
+		keys = append(keys, keyme(c))
+	}
+	csvfmt := fmtout.NewCSV(cols, rows, fmtout.WithNoHeaders(false), fmtout.WithColumns(keys))
+	return csvfmt.Format(w)
+}
+
+// weightInGrams returns the weight in grams along with the unit it is displayed in the admin.
+func weightInGrams(w *schema.Weight) (float64, string) {
+	switch w.Unit {
+	case schema.WeightUnitKilograms:
+		return w.Value * 1000, "kg"
+	case schema.WeightUnitPounds:
+		return w.Value * 453.59237, "lb"
+	case schema.WeightUnitOunces:
+		return w.Value * 28.349523125, "oz"
+	default:
+		return w.Value, "g"
+	}
+}
+
+func value(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+func csvBool(ok bool) string {
+	if ok {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func yesNo(ok bool) string {
+	if ok {
+		return "yes"
+	}
+	return "no"
+}
+
+func keyme(s string) string {
+	s = strings.ToLower(s)
+	return strings.ReplaceAll(s, " ", "_")
+}
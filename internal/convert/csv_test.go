@@ -0,0 +1,99 @@
+package convert
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+func TestProductCSV(t *testing.T) {
+	reg, err := registry.NewRegistry("../registry/testdata/bkp")
+	assert.NoError(t, err)
+
+	snap, err := reg.Snapshot(engine.Product)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, ProductCSV(snap, &buf))
+
+	expected := `Handle,Title,Body (HTML),Vendor,Product Category,Type,Tags,Published,Option1 Name,Option1 Value,Option2 Name,Option2 Value,Option3 Name,Option3 Value,Variant SKU,Variant Grams,Variant Inventory Tracker,Variant Inventory Qty,Variant Inventory Policy,Variant Fulfillment Service,Variant Price,Variant Compare At Price,Variant Requires Shipping,Variant Taxable,Variant Barcode,Image Src,Image Position,Image Alt Text,Gift Card,SEO Title,SEO Description,Variant Image,Variant Weight Unit,Cost per item,Status
+test-product,Test Product,<p>A soft cotton shirt.</p>,Acme,,Shirts,"premium, on-sale",TRUE,Size,S,,,,,TP-S,200,shopify,20,deny,manual,19.99,24.99,TRUE,TRUE,4006381333931,https://cdn.shopify.com/s/files/1/files/front.jpg?v=1712946428,1,Front view,FALSE,,,,kg,8.50,active
+test-product,,,,,,,,,M,,,,,TP-M,,shopify,30,continue,manual,19.99,,TRUE,TRUE,,,,,,,,,,21.00,
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestProductCSVWithMoreImagesThanVariants(t *testing.T) {
+	image := func(id, url string) api.ProductMediaNode {
+		return api.ProductMediaNode{
+			ID:               id,
+			MediaContentType: schema.MediaContentTypeImage,
+			Preview:          schema.MediaPreviewImage{Image: &schema.Image{URL: url}},
+		}
+	}
+	snap := &registry.Snapshot{
+		Products: map[string]*registry.SnapshotProduct{
+			"1": {
+				Product: schema.Product{ID: "gid://shopify/Product/1", Handle: "mug", Title: "Mug", Status: "DRAFT"},
+				Media: []api.ProductMediaNode{
+					image("gid://shopify/MediaImage/1", "https://cdn.shopify.com/mug-front.jpg"),
+					{ID: "gid://shopify/ExternalVideo/2", MediaContentType: schema.MediaContentTypeExternalVideo},
+					image("gid://shopify/MediaImage/3", "https://cdn.shopify.com/mug-back.jpg"),
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, ProductCSV(snap, &buf))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 3)
+	assert.Equal(t, "mug,Mug,,,,,,FALSE,,,,,,,,,,,,,,,,,,https://cdn.shopify.com/mug-front.jpg,1,,FALSE,,,,,,draft", string(lines[1]))
+	assert.Equal(t, "mug,,,,,,,,,,,,,,,,,,,,,,,,,https://cdn.shopify.com/mug-back.jpg,2,,,,,,,,", string(lines[2]))
+}
+
+func TestCustomerCSV(t *testing.T) {
+	reg, err := registry.NewRegistry("../registry/testdata/bkp")
+	assert.NoError(t, err)
+
+	snap, err := reg.Snapshot(engine.Customer)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, CustomerCSV(snap, &buf))
+
+	expected := `First Name,Last Name,Email,Accepts Email Marketing,Default Address Company,Default Address Address1,Default Address Address2,Default Address City,Default Address Province Code,Default Address Country Code,Default Address Zip,Default Address Phone,Phone,Accepts SMS Marketing,Tags,Note,Tax Exempt
+Jon,Doe,jon@example.com,yes,,Hauptstrasse 1,,Berlin,,DE,10115,,+4915112345678,no,vip,Prefers email,no
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestToCSV(t *testing.T) {
+	dir := "./testdata/.tmp"
+
+	reg, err := registry.NewRegistry("../registry/testdata/bkp")
+	assert.NoError(t, err)
+
+	snap, err := reg.Snapshot(engine.Product)
+	assert.NoError(t, err)
+
+	assert.NoError(t, ToCSV(snap, dir))
+	assert.FileExists(t, filepath.Join(dir, "products.csv"))
+	// No customers were read, so there is nothing to write.
+	assert.NoFileExists(t, filepath.Join(dir, "customers.csv"))
+
+	// Existing files are not overwritten.
+	assert.EqualError(t, ToCSV(snap, dir), `file "testdata/.tmp/products.csv" already exists`)
+
+	// Clean up.
+	assert.NoError(t, os.RemoveAll(dir))
+}
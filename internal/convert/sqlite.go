@@ -0,0 +1,361 @@
+package convert
+
+import (
+	"cmp"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	// Pure-Go SQLite driver registered as "sqlite".
+	_ "modernc.org/sqlite"
+
+	"github.com/ankitpokhrel/shopctl"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+// sqliteSchema is the schema of the database. Resources are keyed
+// by their numeric ID and children refer to their parent by it.
+const sqliteSchema = `
+CREATE TABLE products (
+	id               INTEGER PRIMARY KEY,
+	handle           TEXT NOT NULL,
+	title            TEXT NOT NULL,
+	description_html TEXT,
+	vendor           TEXT,
+	product_type     TEXT,
+	category         TEXT,
+	status           TEXT,
+	tags             TEXT,
+	gift_card        INTEGER NOT NULL,
+	total_inventory  INTEGER,
+	created_at       TEXT,
+	updated_at       TEXT,
+	published_at     TEXT
+);
+
+CREATE TABLE product_options (
+	id         INTEGER PRIMARY KEY,
+	product_id INTEGER NOT NULL REFERENCES products(id),
+	name       TEXT NOT NULL,
+	position   INTEGER
+);
+
+CREATE TABLE product_option_values (
+	id        INTEGER PRIMARY KEY,
+	option_id INTEGER NOT NULL REFERENCES product_options(id),
+	name      TEXT NOT NULL,
+	position  INTEGER
+);
+
+CREATE TABLE variants (
+	id                 INTEGER PRIMARY KEY,
+	product_id         INTEGER NOT NULL REFERENCES products(id),
+	title              TEXT,
+	sku                TEXT,
+	barcode            TEXT,
+	price              REAL,
+	compare_at_price   REAL,
+	cost               REAL,
+	cost_currency      TEXT,
+	inventory_quantity INTEGER,
+	inventory_policy   TEXT,
+	taxable            INTEGER NOT NULL,
+	position           INTEGER,
+	created_at         TEXT,
+	updated_at         TEXT
+);
+
+CREATE TABLE variant_options (
+	variant_id INTEGER NOT NULL REFERENCES variants(id),
+	name       TEXT NOT NULL,
+	value      TEXT NOT NULL,
+	PRIMARY KEY (variant_id, name)
+);
+
+CREATE TABLE media (
+	id           INTEGER PRIMARY KEY,
+	product_id   INTEGER NOT NULL REFERENCES products(id),
+	position     INTEGER,
+	content_type TEXT,
+	status       TEXT,
+	alt          TEXT,
+	url          TEXT
+);
+
+CREATE TABLE product_metafields (
+	id         INTEGER PRIMARY KEY,
+	product_id INTEGER NOT NULL REFERENCES products(id),
+	namespace  TEXT NOT NULL,
+	key        TEXT NOT NULL,
+	type       TEXT,
+	value      TEXT
+);
+
+CREATE TABLE customers (
+	id                INTEGER PRIMARY KEY,
+	first_name        TEXT,
+	last_name         TEXT,
+	email             TEXT,
+	phone             TEXT,
+	state             TEXT,
+	tags              TEXT,
+	note              TEXT,
+	verified_email    INTEGER NOT NULL,
+	accepts_marketing INTEGER NOT NULL,
+	tax_exempt        INTEGER NOT NULL,
+	orders_count      INTEGER,
+	amount_spent      REAL,
+	currency          TEXT,
+	created_at        TEXT,
+	updated_at        TEXT
+);
+
+CREATE TABLE customer_addresses (
+	id            INTEGER PRIMARY KEY,
+	customer_id   INTEGER NOT NULL REFERENCES customers(id),
+	is_default    INTEGER NOT NULL,
+	first_name    TEXT,
+	last_name     TEXT,
+	company       TEXT,
+	address1      TEXT,
+	address2      TEXT,
+	city          TEXT,
+	province      TEXT,
+	province_code TEXT,
+	country       TEXT,
+	country_code  TEXT,
+	zip           TEXT,
+	phone         TEXT
+);
+
+CREATE TABLE customer_metafields (
+	id          INTEGER PRIMARY KEY,
+	customer_id INTEGER NOT NULL REFERENCES customers(id),
+	namespace   TEXT NOT NULL,
+	key         TEXT NOT NULL,
+	type        TEXT,
+	value       TEXT
+);
+`
+
+// ToSQLite writes the products and customers of the snapshot to a new SQLite database at the given path.
+// The database is not written if the file already exists, and is removed if the conversion fails.
+func ToSQLite(snap *registry.Snapshot, path string) (err error) {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("file %q already exists", path)
+	}
+
+	db, err := sql.Open("sqlite", path+"?_pragma=foreign_keys(1)")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, db.Close())
+		if err != nil {
+			_ = os.Remove(path)
+		}
+	}()
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("error creating schema: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, id := range sortedIDs(snap.Products) {
+		if err := insertProduct(tx, snap.Products[id]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error converting product %s: %w", id, err)
+		}
+	}
+	for _, id := range sortedIDs(snap.Customers) {
+		if err := insertCustomer(tx, snap.Customers[id], snap.CustomerMetafields[id]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error converting customer %s: %w", id, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func insertProduct(tx *sql.Tx, p *registry.SnapshotProduct) error {
+	var (
+		product  = &p.Product
+		pid      = numericID(product.ID)
+		category any
+	)
+	if product.Category != nil {
+		category = product.Category.FullName
+	}
+
+	_, err := tx.Exec(
+		`INSERT INTO products VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		pid, product.Handle, product.Title, product.DescriptionHtml, product.Vendor, product.ProductType, category,
+		string(product.Status), strings.Join(runner.StringValues(product.Tags), ", "), product.IsGiftCard,
+		product.TotalInventory, product.CreatedAt, product.UpdatedAt, product.PublishedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range product.Options {
+		res, err := tx.Exec(`INSERT INTO product_options VALUES (?, ?, ?, ?)`, numericID(o.ID), pid, o.Name, o.Position)
+		if err != nil {
+			return err
+		}
+		oid, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		// Option values without an ID are given one by the database.
+		values := o.OptionValues
+		if len(values) == 0 {
+			for _, v := range o.Values {
+				values = append(values, schema.ProductOptionValue{Name: v})
+			}
+		}
+		for i, v := range values {
+			if _, err := tx.Exec(`INSERT INTO product_option_values VALUES (?, ?, ?, ?)`, numericID(v.ID), oid, v.Name, i+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, v := range p.Variants {
+		var cost, currency any
+		if v.InventoryItem != nil && v.InventoryItem.UnitCost != nil {
+			cost, currency = v.InventoryItem.UnitCost.Amount, string(v.InventoryItem.UnitCost.CurrencyCode)
+		}
+
+		vid := numericID(v.ID)
+		_, err := tx.Exec(
+			`INSERT INTO variants VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			vid, pid, v.Title, v.Sku, v.Barcode, amount(&v.Price), amount(v.CompareAtPrice), cost, currency,
+			v.InventoryQuantity, string(v.InventoryPolicy), v.Taxable, v.Position, v.CreatedAt, v.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		for _, o := range selectedOptions(v.SelectedOptions) {
+			if _, err := tx.Exec(`INSERT INTO variant_options VALUES (?, ?, ?)`, vid, o.Name, o.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, m := range p.Media {
+		var alt, url any
+		if m.Preview.Image != nil {
+			alt, url = m.Preview.Image.AltText, m.Preview.Image.URL
+		}
+		if src := runner.MediaSource(&m); src != "" {
+			url = src
+		}
+		_, err := tx.Exec(
+			`INSERT INTO media VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			numericID(m.ID), pid, i+1, string(m.MediaContentType), string(m.Status), alt, url,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, mf := range p.Metafields {
+		_, err := tx.Exec(
+			`INSERT INTO product_metafields VALUES (?, ?, ?, ?, ?, ?)`,
+			numericID(mf.ID), pid, mf.Namespace, mf.Key, mf.Type, mf.Value,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertCustomer(tx *sql.Tx, c *schema.Customer, metafields []schema.Metafield) error {
+	var (
+		cid        = numericID(c.ID)
+		subscribed = c.EmailMarketingConsent != nil && c.EmailMarketingConsent.MarketingState == schema.CustomerEmailMarketingStateSubscribed
+		orders     any
+	)
+	if n, err := strconv.Atoi(c.NumberOfOrders); err == nil {
+		orders = n
+	}
+
+	_, err := tx.Exec(
+		`INSERT INTO customers VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		cid, c.FirstName, c.LastName, c.Email, c.Phone, string(c.State), strings.Join(runner.StringValues(c.Tags), ", "),
+		c.Note, c.VerifiedEmail, subscribed, c.TaxExempt, orders, c.AmountSpent.Amount, string(c.AmountSpent.CurrencyCode),
+		c.CreatedAt, c.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range mailingAddresses(c.AddressesV2.Nodes) {
+		isDefault := c.DefaultAddress != nil && c.DefaultAddress.ID == a.ID
+		_, err := tx.Exec(
+			`INSERT INTO customer_addresses VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			numericID(a.ID), cid, isDefault, a.FirstName, a.LastName, a.Company, a.Address1, a.Address2, a.City,
+			a.Province, a.ProvinceCode, a.Country, a.CountryCodeV2, a.Zip, a.Phone,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, mf := range metafields {
+		_, err := tx.Exec(
+			`INSERT INTO customer_metafields VALUES (?, ?, ?, ?, ?, ?)`,
+			numericID(mf.ID), cid, mf.Namespace, mf.Key, mf.Type, mf.Value,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// numericID returns the numeric part of a Shopify ID, e.g. 123 for
+// gid://shopify/MailingAddress/123?model_name=CustomerAddress. It is
+// nil if the ID is not numeric so that the database assigns one.
+func numericID(gid string) any {
+	id, _, _ := strings.Cut(shopctl.ExtractNumericID(gid), "?")
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return n
+}
+
+// amount parses a money value, e.g. a variant price. It is nil if the value is not set.
+func amount(v *string) any {
+	if v == nil {
+		return nil
+	}
+	n, err := strconv.ParseFloat(*v, 64)
+	if err != nil {
+		return nil
+	}
+	return n
+}
+
+// sortedIDs returns the numeric IDs of the resources in ascending order.
+func sortedIDs[T any](resources map[string]*T) []string {
+	ids := make([]string, 0, len(resources))
+	for id := range resources {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(a), len(b)), strings.Compare(a, b))
+	})
+	return ids
+}
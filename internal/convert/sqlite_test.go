@@ -0,0 +1,92 @@
+package convert
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+)
+
+func TestToSQLite(t *testing.T) {
+	dir := "./testdata/.tmp"
+	assert.NoError(t, os.MkdirAll(dir, 0o755))
+
+	reg, err := registry.NewRegistry("../registry/testdata/bkp")
+	assert.NoError(t, err)
+
+	snap, err := reg.Snapshot(engine.Product, engine.Customer)
+	assert.NoError(t, err)
+
+	path := filepath.Join(dir, "export.db")
+	assert.NoError(t, ToSQLite(snap, path))
+
+	db, err := sql.Open("sqlite", path)
+	assert.NoError(t, err)
+
+	count := func(table string) int {
+		var n int
+		assert.NoError(t, db.QueryRow("SELECT COUNT(*) FROM "+table).Scan(&n))
+		return n
+	}
+	assert.Equal(t, 1, count("products"))
+	assert.Equal(t, 1, count("product_options"))
+	assert.Equal(t, 2, count("product_option_values"))
+	assert.Equal(t, 2, count("variants"))
+	assert.Equal(t, 2, count("variant_options"))
+	assert.Equal(t, 1, count("media"))
+	assert.Equal(t, 1, count("product_metafields"))
+	assert.Equal(t, 1, count("customers"))
+	assert.Equal(t, 2, count("customer_addresses"))
+	assert.Equal(t, 1, count("customer_metafields"))
+
+	// Variants sold for less than they cost.
+	var handle, sku string
+	assert.NoError(t, db.QueryRow(`
+		SELECT p.handle, v.sku FROM variants v
+		JOIN products p ON p.id = v.product_id
+		WHERE v.price < v.cost`,
+	).Scan(&handle, &sku))
+	assert.Equal(t, "test-product", handle)
+	assert.Equal(t, "TP-M", sku)
+
+	var tags, material string
+	assert.NoError(t, db.QueryRow(`
+		SELECT p.tags, m.value FROM products p
+		JOIN product_metafields m ON m.product_id = p.id
+		WHERE m.namespace = 'custom' AND m.key = 'material'`,
+	).Scan(&tags, &material))
+	assert.Equal(t, "premium, on-sale", tags)
+	assert.Equal(t, "Cotton", material)
+
+	var (
+		email string
+		city  string
+		spent float64
+	)
+	assert.NoError(t, db.QueryRow(`
+		SELECT c.email, a.city, c.amount_spent FROM customers c
+		JOIN customer_addresses a ON a.customer_id = c.id
+		WHERE a.is_default = 1`,
+	).Scan(&email, &city, &spent))
+	assert.Equal(t, "jon@example.com", email)
+	assert.Equal(t, "Berlin", city)
+	assert.Equal(t, 120.5, spent)
+
+	// Every child refers to an existing parent.
+	rows, err := db.Query("PRAGMA foreign_key_check")
+	assert.NoError(t, err)
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.Close())
+	assert.NoError(t, db.Close())
+
+	// An existing database is not overwritten.
+	assert.EqualError(t, ToSQLite(snap, path), `file "testdata/.tmp/export.db" already exists`)
+
+	// Clean up.
+	assert.NoError(t, os.RemoveAll(dir))
+}
@@ -1,12 +1,18 @@
 package engine
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
+
+	"github.com/ankitpokhrel/shopctl"
+	"github.com/ankitpokhrel/shopctl/internal/version"
 )
 
 const (
@@ -16,11 +22,19 @@ const (
 
 // Backup is a backup engine.
 type Backup struct {
+	mux       sync.Mutex
 	id        string
 	store     string
+	alias     string
 	root      string
 	dir       string
 	timestamp time.Time
+	filters   []ResourceFilter
+	base      *BackupRef
+	since     map[ResourceType]Watermark
+	marks     map[ResourceType]Watermark
+	counts    map[ResourceType]int
+	cp        *checkpoint
 }
 
 // Option is a functional opt for Backup.
@@ -29,23 +43,68 @@ type Option func(*Backup)
 // NewBackup creates a new backup engine.
 func NewBackup(store string, opts ...Option) *Backup {
 	now := time.Now()
-	id := genBackupID(store, now.Unix())
+	bkp := newBackup(genBackupID(store, now.Unix()), store, now, opts...)
+
+	if bkp.dir == "" {
+		bkp.dir = fmt.Sprintf("%s_%s", bkp.timestamp.Format("2006_01_02_15_04_05"), bkp.id)
+	}
+	bkp.cp = newCheckpoint(checkpointPath(bkp.root, bkp.id), checkpointMeta{
+		ID:        bkp.id,
+		Store:     bkp.store,
+		Alias:     bkp.alias,
+		Dir:       bkp.dir,
+		StartedAt: bkp.timestamp,
+		Filters:   bkp.filters,
+		Base:      bkp.base,
+		Since:     bkp.since,
+	})
+	bkp.root = filepath.Join(bkp.root, bkp.dir)
+
+	return bkp
+}
+
+// ResumeBackup creates a backup engine that resumes an interrupted backup
+// from its checkpoint. The store, dir, filters and the base of the backup
+// are restored from the checkpoint and takes precedence over the options.
+func ResumeBackup(id string, opts ...Option) (*Backup, error) {
+	bkp := newBackup(id, "", time.Time{}, opts...)
+
+	cp, err := loadCheckpoint(checkpointPath(bkp.root, id))
+	if err != nil {
+		return nil, err
+	}
+
+	bkp.store = cp.meta.Store
+	bkp.alias = cp.meta.Alias
+	bkp.dir = cp.meta.Dir
+	bkp.timestamp = cp.meta.StartedAt
+	bkp.filters = cp.meta.Filters
+	bkp.base = cp.meta.Base
+	bkp.since = cp.meta.Since
+	if bkp.since == nil {
+		bkp.since = make(map[ResourceType]Watermark)
+	}
+	bkp.counts = maps.Clone(cp.counts)
+	bkp.cp = cp
+	bkp.root = filepath.Join(bkp.root, bkp.dir)
+
+	return bkp, nil
+}
+
+func newBackup(id, store string, timestamp time.Time, opts ...Option) *Backup {
 	bkp := Backup{
 		id:        id,
 		store:     store,
 		root:      os.TempDir(),
-		timestamp: now,
+		timestamp: timestamp,
+		since:     make(map[ResourceType]Watermark),
+		marks:     make(map[ResourceType]Watermark),
+		counts:    make(map[ResourceType]int),
 	}
 
 	for _, opt := range opts {
 		opt(&bkp)
 	}
-
-	if bkp.dir == "" {
-		bkp.dir = fmt.Sprintf("%s_%s", bkp.timestamp.Format("2006_01_02_15_04_05"), id)
-	}
-	bkp.root = filepath.Join(bkp.root, bkp.dir)
-
 	return &bkp
 }
 
@@ -63,6 +122,34 @@ func WithBackupDir(dir string) Option {
 	}
 }
 
+// WithAlias sets the context alias used for the backup.
+func WithAlias(alias string) Option {
+	return func(b *Backup) {
+		b.alias = alias
+	}
+}
+
+// WithFilters sets resources and the filters used for the backup.
+func WithFilters(filters []ResourceFilter) Option {
+	return func(b *Backup) {
+		b.filters = filters
+	}
+}
+
+// WithDeltaOf marks the backup as a delta of the given base backup.
+// Only the records updated after the watermark of a resource are exported.
+func WithDeltaOf(base BackupRef, watermarks map[ResourceType]Watermark) Option {
+	return func(b *Backup) {
+		b.base = &base
+		maps.Copy(b.since, watermarks)
+	}
+}
+
+// ID returns the backup ID.
+func (b *Backup) ID() string {
+	return b.id
+}
+
 // Store returns the store this backup will run for.
 func (b *Backup) Store() string {
 	return b.store
@@ -78,21 +165,154 @@ func (b *Backup) Dir() string {
 	return b.dir
 }
 
+// Filters returns resources and the filters used for the backup.
+func (b *Backup) Filters() []ResourceFilter {
+	return b.filters
+}
+
+// Base returns the backup this backup is a delta of, if any.
+func (b *Backup) Base() *BackupRef {
+	return b.base
+}
+
+// Since returns the time after which the records of the given
+// resource type needs to be exported. It returns nil for a full export.
+func (b *Backup) Since(rt ResourceType) *time.Time {
+	wm, ok := b.since[rt]
+	if !ok || wm.UpdatedAt.IsZero() {
+		return nil
+	}
+	return &wm.UpdatedAt
+}
+
+// SetWatermark records the last updated time seen for the resource type.
+func (b *Backup) SetWatermark(rt ResourceType, updatedAt time.Time) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.marks[rt] = Watermark{UpdatedAt: updatedAt, BackupID: b.id}
+}
+
+// Watermarks returns watermarks recorded in this backup.
+func (b *Backup) Watermarks() map[ResourceType]Watermark {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	return maps.Clone(b.marks)
+}
+
+// Cursor returns the pagination cursor to resume fetching the resource type from.
+// It returns nil if the backup was not checkpointed for the resource type.
+func (b *Backup) Cursor(rt ResourceType) *string {
+	return b.cp.cursor(rt)
+}
+
+// IsWritten checks if the resource and all of its children
+// were already written in an earlier run of the backup.
+func (b *Backup) IsWritten(rt ResourceType, id string) bool {
+	return b.cp.isWritten(rt, id)
+}
+
+// Track registers a page of resources to checkpoint. The page cursor is
+// checkpointed once all resources in the page and the pages before it are written.
+// Resources must be tracked before they are added to the engine.
+func (b *Backup) Track(rt ResourceType, endCursor *string, ids []string) {
+	b.cp.track(rt, endCursor, ids)
+}
+
+// Complete checkpoints the parent resource of a collection as written
+// if the collection was processed without any errors.
+// Implements `engine.Completer` interface.
+func (b *Backup) Complete(rc ResourceCollection, err error) {
+	if err != nil {
+		return
+	}
+
+	counts := map[ResourceType]int{rc.Parent.Type: 1}
+	for _, r := range rc.Children {
+		counts[r.Type]++
+	}
+	b.cp.done(rc.Parent.Type, filepath.Base(rc.Parent.Path), counts)
+}
+
+// SaveCheckpoint flushes the backup checkpoint to the disk,
+// e.g. before exiting an interrupted backup to resume it later.
+func (b *Backup) SaveCheckpoint() error {
+	return b.cp.close()
+}
+
+// RemoveCheckpoint removes the backup checkpoint. The backup
+// can't be resumed once the checkpoint is removed.
+func (b *Backup) RemoveCheckpoint() error {
+	return b.cp.remove()
+}
+
+// Manifest builds the manifest for the backup.
+func (b *Backup) Manifest() *Manifest {
+	b.mux.Lock()
+	counts := maps.Clone(b.counts)
+	b.mux.Unlock()
+
+	return &Manifest{
+		ID:         b.id,
+		Dir:        b.dir,
+		Store:      b.store,
+		Alias:      b.alias,
+		APIVersion: shopctl.ShopifyApiVersion,
+		Version:    version.Version,
+		Filters:    b.filters,
+		Counts:     counts,
+		StartedAt:  b.timestamp,
+		Base:       b.base,
+		Watermarks: b.Watermarks(),
+	}
+}
+
+// WriteManifest saves the backup manifest along with
+// the checksum of each file at the root of the backup dir.
+func (b *Backup) WriteManifest() error {
+	if err := os.MkdirAll(b.root, modeDir); err != nil {
+		return err
+	}
+
+	sums, err := Checksums(b.root)
+	if err != nil {
+		return fmt.Errorf("failed to calculate checksums: %w", err)
+	}
+
+	m := b.Manifest()
+	m.Checksums = sums
+	m.FinishedAt = time.Now()
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	return os.WriteFile(filepath.Join(b.root, ManifestFile), data, modeFile)
+}
+
 // Do starts the backup process.
 // Implements `engine.Doer` interface.
-func (b *Backup) Do(rs Resource, _ any) (any, error) {
+func (b *Backup) Do(ctx context.Context, rs Resource, _ any) (any, error) {
 	dir := filepath.Join(b.root, rs.Path)
 	if err := os.MkdirAll(dir, modeDir); err != nil {
 		return nil, err
 	}
 	dest := filepath.Join(dir, rs.Type.File())
 
-	data, err := rs.Handler.Handle(nil)
+	data, err := rs.Handler.Handle(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
-	err = b.saveJSON(dest, data)
-	return nil, err
+	if err = b.saveJSON(dest, data); err != nil {
+		return nil, err
+	}
+
+	b.mux.Lock()
+	b.counts[rs.Type]++
+	b.mux.Unlock()
+
+	return nil, nil
 }
 
 // saveJSON saves data to a JSON file.
@@ -1,9 +1,11 @@
 package engine
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -14,7 +16,7 @@ type mockHandler struct {
 	dataFile string
 }
 
-func (m *mockHandler) Handle(data any) (any, error) {
+func (m *mockHandler) Handle(_ context.Context, data any) (any, error) {
 	content, err := os.ReadFile(m.dataFile)
 	if err != nil {
 		return nil, err
@@ -104,7 +106,7 @@ func TestBackup_Do(t *testing.T) {
 		}
 	}()
 
-	for res := range eng.Run(Product) {
+	for res := range eng.Run(context.Background(), Product) {
 		assert.NoError(t, res.Err)
 	}
 
@@ -153,3 +155,124 @@ func TestBackup_Do(t *testing.T) {
 	// Clean up.
 	assert.NoError(t, os.RemoveAll(path))
 }
+
+func TestBackup_WriteManifest(t *testing.T) {
+	path := "./testdata/.tmp"
+	since := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	latest := time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC)
+
+	bkpEng := NewBackup(
+		"teststore.example.com",
+		WithBackupRoot(path),
+		WithBackupDir("delta"),
+		WithDeltaOf(
+			BackupRef{ID: "abc123", Dir: "base"},
+			map[ResourceType]Watermark{Product: {UpdatedAt: since, BackupID: "abc123"}},
+		),
+	)
+
+	assert.Equal(t, since, *bkpEng.Since(Product))
+	assert.Nil(t, bkpEng.Since(Customer))
+
+	bkpEng.SetWatermark(Product, latest)
+
+	_, err := bkpEng.Do(context.Background(), NewResource(Product, "products/1", &mockHandler{dataFile: "./testdata/empty.json"}), nil)
+	assert.NoError(t, err)
+	assert.NoError(t, bkpEng.WriteManifest())
+
+	m, err := ReadManifest(path + "/delta")
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-04", m.APIVersion)
+	assert.Equal(t, map[ResourceType]int{Product: 1}, m.Counts)
+	assert.Equal(t, map[string]string{
+		// SHA-256 of "{}".
+		"products/1/product.json": "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+	}, m.Checksums)
+	assert.False(t, m.FinishedAt.Before(m.StartedAt))
+	assert.True(t, m.IsDelta())
+	assert.Equal(t, bkpEng.ID(), m.ID)
+	assert.Equal(t, "delta", m.Dir)
+	assert.Equal(t, "teststore.example.com", m.Store)
+	assert.Equal(t, &BackupRef{ID: "abc123", Dir: "base"}, m.Base)
+	assert.Equal(t, map[ResourceType]Watermark{Product: {UpdatedAt: latest, BackupID: bkpEng.ID()}}, m.Watermarks)
+
+	// Clean up.
+	assert.NoError(t, os.RemoveAll(path))
+}
+
+func TestBackup_Resume(t *testing.T) {
+	path := "./testdata/.tmp"
+	cursor1, cursor2 := "cursor1", "cursor2"
+
+	bkpEng := NewBackup(
+		"teststore.example.com",
+		WithBackupRoot(path),
+		WithBackupDir("resume"),
+		WithFilters([]ResourceFilter{{Resource: "product", Query: "tag:premium"}}),
+	)
+
+	collection := func(id string) ResourceCollection {
+		parent := NewResource(Product, "products/"+id, nil)
+		return ResourceCollection{
+			Parent:   &parent,
+			Children: []Resource{NewResource(ProductVariant, "products/"+id, nil)},
+		}
+	}
+
+	bkpEng.Track(Product, &cursor1, []string{"1", "2"})
+	bkpEng.Track(Product, &cursor2, []string{"3", "4"})
+	bkpEng.Complete(collection("1"), nil)
+	bkpEng.Complete(collection("3"), nil)
+	bkpEng.Complete(collection("2"), nil)
+	bkpEng.Complete(collection("4"), fmt.Errorf("failed"))
+	assert.NoError(t, bkpEng.SaveCheckpoint())
+
+	resumed, err := ResumeBackup(bkpEng.ID(), WithBackupRoot(path))
+	assert.NoError(t, err)
+
+	assert.Equal(t, bkpEng.Root(), resumed.Root())
+	assert.Equal(t, "resume", resumed.Dir())
+	assert.Equal(t, "teststore.example.com", resumed.Store())
+	assert.Equal(t, []ResourceFilter{{Resource: "product", Query: "tag:premium"}}, resumed.Manifest().Filters)
+	assert.Equal(t, map[ResourceType]int{Product: 3, ProductVariant: 3}, resumed.Manifest().Counts)
+
+	// Only the first page is fully written.
+	assert.Equal(t, &cursor1, resumed.Cursor(Product))
+	assert.Nil(t, resumed.Cursor(Customer))
+
+	assert.True(t, resumed.IsWritten(Product, "1"))
+	assert.True(t, resumed.IsWritten(Product, "2"))
+	assert.True(t, resumed.IsWritten(Product, "3"))
+	assert.False(t, resumed.IsWritten(Product, "4"))
+
+	assert.NoError(t, resumed.RemoveCheckpoint())
+	_, err = ResumeBackup(bkpEng.ID(), WithBackupRoot(path))
+	assert.Error(t, err)
+
+	// Clean up.
+	assert.NoError(t, bkpEng.RemoveCheckpoint())
+	assert.NoError(t, os.RemoveAll(path))
+}
+
+func TestBackup_SaveCheckpointReportsWriteErrors(t *testing.T) {
+	path := "./testdata/.tmp"
+	assert.NoError(t, os.MkdirAll(path, 0o755))
+
+	// The checkpoint can't be created under a regular file.
+	root := filepath.Join(path, "root")
+	assert.NoError(t, os.WriteFile(root, nil, 0o644))
+
+	bkpEng := NewBackup("teststore.example.com", WithBackupRoot(root))
+
+	cursor := "cursor1"
+	parent := NewResource(Product, "products/1", nil)
+	bkpEng.Track(Product, &cursor, []string{"1"})
+	bkpEng.Complete(ResourceCollection{Parent: &parent}, nil)
+
+	err := bkpEng.SaveCheckpoint()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to write checkpoint")
+
+	// Clean up.
+	assert.NoError(t, os.RemoveAll(path))
+}
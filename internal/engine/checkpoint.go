@@ -0,0 +1,264 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	eventMeta    = "meta"
+	eventWritten = "written"
+	eventCursor  = "cursor"
+)
+
+// Completer is implemented by doers that need to know when
+// all resources in a collection are processed.
+type Completer interface {
+	Complete(rc ResourceCollection, err error)
+}
+
+type checkpointMeta struct {
+	ID        string                     `json:"id"`
+	Store     string                     `json:"store"`
+	Alias     string                     `json:"alias,omitempty"`
+	Dir       string                     `json:"dir"`
+	StartedAt time.Time                  `json:"startedAt"`
+	Filters   []ResourceFilter           `json:"filters,omitempty"`
+	Base      *BackupRef                 `json:"base,omitempty"`
+	Since     map[ResourceType]Watermark `json:"since,omitempty"`
+}
+
+type checkpointEvent struct {
+	Kind     string               `json:"kind"`
+	Meta     *checkpointMeta      `json:"meta,omitempty"`
+	Resource ResourceType         `json:"resource,omitempty"`
+	ID       string               `json:"id,omitempty"`
+	Cursor   *string              `json:"cursor,omitempty"`
+	Counts   map[ResourceType]int `json:"counts,omitempty"`
+}
+
+// page is a page of resources queued for backup.
+type page struct {
+	end     *string
+	pending map[string]bool
+}
+
+// checkpoint tracks progress of a backup so that it can be resumed if interrupted.
+//
+// Progress is saved as an append-only journal of events. A resource is marked
+// as written once the resource and all of its children are saved. The cursor
+// of a resource type is moved once all resources in the leading pages are written.
+// The first error writing the journal is kept and returned when the checkpoint is saved.
+type checkpoint struct {
+	mux     sync.Mutex
+	path    string
+	file    *os.File
+	meta    checkpointMeta
+	cursors map[ResourceType]*string
+	written map[ResourceType]map[string]bool
+	counts  map[ResourceType]int
+	pages   map[ResourceType][]*page
+	err     error
+}
+
+func newCheckpoint(path string, meta checkpointMeta) *checkpoint {
+	return &checkpoint{
+		path:    path,
+		meta:    meta,
+		cursors: make(map[ResourceType]*string),
+		written: make(map[ResourceType]map[string]bool),
+		counts:  make(map[ResourceType]int),
+		pages:   make(map[ResourceType][]*page),
+	}
+}
+
+func loadCheckpoint(path string) (*checkpoint, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no checkpoint found at %s", path)
+		}
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	cp := newCheckpoint(path, checkpointMeta{})
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev checkpointEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			// The last event might be partially written if the process was killed.
+			continue
+		}
+		switch ev.Kind {
+		case eventMeta:
+			cp.meta = *ev.Meta
+		case eventWritten:
+			cp.markWritten(ev.Resource, ev.ID, ev.Counts)
+		case eventCursor:
+			cp.cursors[ev.Resource] = ev.Cursor
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if cp.meta.ID == "" {
+		return nil, fmt.Errorf("invalid checkpoint %s", path)
+	}
+	return cp, nil
+}
+
+func checkpointPath(root, id string) string {
+	return filepath.Join(root, fmt.Sprintf("shopctl-%s.checkpoint", id))
+}
+
+// cursor returns the pagination cursor to resume the resource type from.
+func (c *checkpoint) cursor(rt ResourceType) *string {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	return c.cursors[rt]
+}
+
+// isWritten checks if the resource was already written.
+func (c *checkpoint) isWritten(rt ResourceType, id string) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	return c.written[rt][id]
+}
+
+// track starts tracking resources in a page that ends at the given cursor.
+func (c *checkpoint) track(rt ResourceType, end *string, ids []string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	p := page{end: end, pending: make(map[string]bool, len(ids))}
+	for _, id := range ids {
+		p.pending[id] = true
+	}
+	c.pages[rt] = append(c.pages[rt], &p)
+	c.advance(rt)
+}
+
+// done marks the resource as fully written along with the
+// number of resources of each type written for it.
+func (c *checkpoint) done(rt ResourceType, id string, counts map[ResourceType]int) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.markWritten(rt, id, counts)
+	c.record(checkpointEvent{Kind: eventWritten, Resource: rt, ID: id, Counts: counts})
+
+	for _, p := range c.pages[rt] {
+		delete(p.pending, id)
+	}
+	c.advance(rt)
+}
+
+// advance moves cursor past the leading pages that are fully written.
+func (c *checkpoint) advance(rt ResourceType) {
+	var (
+		pages = c.pages[rt]
+		moved bool
+	)
+	for len(pages) > 0 && len(pages[0].pending) == 0 {
+		c.cursors[rt] = pages[0].end
+		pages = pages[1:]
+		moved = true
+	}
+	c.pages[rt] = pages
+
+	if moved {
+		c.record(checkpointEvent{Kind: eventCursor, Resource: rt, Cursor: c.cursors[rt]})
+	}
+}
+
+func (c *checkpoint) markWritten(rt ResourceType, id string, counts map[ResourceType]int) {
+	if _, ok := c.written[rt]; !ok {
+		c.written[rt] = make(map[string]bool)
+	}
+	c.written[rt][id] = true
+
+	for t, n := range counts {
+		c.counts[t] += n
+	}
+}
+
+// record appends an event to the checkpoint file and keeps the first error, if any.
+func (c *checkpoint) record(ev checkpointEvent) {
+	if err := c.append(ev); err != nil && c.err == nil {
+		c.err = fmt.Errorf("unable to write checkpoint %s: %w", c.path, err)
+	}
+}
+
+// append appends an event to the checkpoint file. The file
+// is created with the backup metadata on the first write.
+func (c *checkpoint) append(ev checkpointEvent) error {
+	if c.file == nil {
+		_, err := os.Stat(c.path)
+		isNew := os.IsNotExist(err)
+
+		if err := os.MkdirAll(filepath.Dir(c.path), modeDir); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(c.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, modeFile)
+		if err != nil {
+			return err
+		}
+		c.file = f
+
+		if isNew {
+			if err := c.append(checkpointEvent{Kind: eventMeta, Meta: &c.meta}); err != nil {
+				return err
+			}
+		}
+	}
+
+	line, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = c.file.Write(append(line, '\n'))
+	return err
+}
+
+// close flushes the checkpoint file to the disk and closes it. It returns an
+// error if any of the events couldn't be written since the checkpoint is incomplete.
+func (c *checkpoint) close() error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.file == nil {
+		return c.err
+	}
+	err := c.file.Sync()
+	if cerr := c.file.Close(); err == nil {
+		err = cerr
+	}
+	c.file = nil
+
+	return errors.Join(c.err, err)
+}
+
+// remove removes the checkpoint file.
+func (c *checkpoint) remove() error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.file != nil {
+		_ = c.file.Close()
+		c.file = nil
+	}
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
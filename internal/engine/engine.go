@@ -1,13 +1,25 @@
 package engine
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
+
+	"github.com/ankitpokhrel/shopctl/pkg/gql/client"
 )
 
 const (
-	// TODO: Decide number of workers.
-	numWorkers = 3
+	// DefaultWorkers is the default number of workers per resource type.
+	DefaultWorkers = 3
+
+	// Workers are slowed down once the available query cost budget
+	// of the store drops below this fraction of the maximum budget.
+	slowdownThreshold = 0.5
+
+	// Time to wait before checking the throttle status again.
+	throttleBackoff = 250 * time.Millisecond
 )
 
 // ErrSkipChildren is an event that signals the engine to skip processing children.
@@ -22,22 +34,59 @@ type Result struct {
 
 // Doer is an interface that defines the handler of the engine.
 type Doer interface {
-	Do(Resource, any) (any, error)
+	Do(context.Context, Resource, any) (any, error)
+}
+
+// Throttler reports the query cost budget of the store.
+type Throttler interface {
+	ThrottleStatus() (client.ThrottleStatus, bool)
 }
 
 // Engine is an execution engine.
 type Engine struct {
-	mux  sync.Mutex
-	doer Doer
-	jobs map[ResourceType]chan ResourceCollection
+	mux     sync.Mutex
+	doer    Doer
+	jobs    map[ResourceType]chan ResourceCollection
+	workers int
+
+	throttler Throttler
+	amux      sync.Mutex
+	active    int
 }
 
+// EngineOption is a functional opt for Engine.
+type EngineOption func(*Engine)
+
 // New creates a new engine.
-func New(d Doer) *Engine {
-	return &Engine{
-		mux:  sync.Mutex{},
-		doer: d,
-		jobs: make(map[ResourceType]chan ResourceCollection, 0),
+func New(d Doer, opts ...EngineOption) *Engine {
+	eng := Engine{
+		mux:     sync.Mutex{},
+		doer:    d,
+		jobs:    make(map[ResourceType]chan ResourceCollection, 0),
+		workers: DefaultWorkers,
+	}
+
+	for _, opt := range opts {
+		opt(&eng)
+	}
+	return &eng
+}
+
+// WithWorkers sets the number of workers per resource type.
+func WithWorkers(n int) EngineOption {
+	return func(e *Engine) {
+		if n > 0 {
+			e.workers = n
+		}
+	}
+}
+
+// WithThrottler sets the throttler to adapt the concurrency to. Once the available budget
+// of the store drops below the threshold, the number of resource collections processed
+// at a time is reduced proportionally until the budget is restored.
+func WithThrottler(t Throttler) EngineOption {
+	return func(e *Engine) {
+		e.throttler = t
 	}
 }
 
@@ -51,7 +100,7 @@ func (e *Engine) Register(rt ResourceType) {
 	e.mux.Lock()
 	defer e.mux.Unlock()
 
-	e.jobs[rt] = make(chan ResourceCollection) // TODO: Decide buffer size based on store size.
+	e.jobs[rt] = make(chan ResourceCollection, e.workers)
 }
 
 // Add adds a resource to the engine.
@@ -62,29 +111,50 @@ func (e *Engine) Add(rt ResourceType, rc ResourceCollection) {
 	e.jobs[rt] <- rc
 }
 
-// Run starts the engine.
-func (e *Engine) Run(rt ResourceType) chan Result {
+// Run starts the engine. Once the context is cancelled, workers stop picking up new resource
+// collections. Collections that are already being processed are processed to completion so
+// that a collection is never left half done.
+func (e *Engine) Run(ctx context.Context, rt ResourceType) chan Result {
 	var wg sync.WaitGroup
 
 	run := func(rc ResourceCollection, out chan<- Result) {
-		data, err := e.doer.Do(*rc.Parent, nil)
+		if err := e.acquire(ctx); err != nil {
+			return
+		}
+		defer e.release()
+
+		ctx := context.WithoutCancel(ctx)
+
+		data, err := e.doer.Do(ctx, *rc.Parent, nil)
 		out <- Result{ResourceType: rc.Parent.Type, Err: err}
+
+		errs := []error{err}
 		if err == nil {
 			for _, r := range rc.Children {
-				_, err := e.doer.Do(r, data)
+				_, err := e.doer.Do(ctx, r, data)
 				out <- Result{ParentResourceType: rc.Parent.Type, ResourceType: r.Type, Err: err}
+				errs = append(errs, err)
 			}
 		}
+
+		if c, ok := e.doer.(Completer); ok {
+			c.Complete(rc, errors.Join(errs...))
+		}
 	}
 
-	out := make(chan Result, numWorkers)
-	for range numWorkers {
+	out := make(chan Result, e.workers)
+	for range e.workers {
 		wg.Add(1)
 
 		go func() {
 			defer wg.Done()
 
 			for rc := range e.jobs[rt] {
+				// Remaining collections are drained without processing
+				// so that the producer is not blocked on a full queue.
+				if ctx.Err() != nil {
+					continue
+				}
 				run(rc, out)
 			}
 		}()
@@ -103,3 +173,48 @@ func (e *Engine) Run(rt ResourceType) chan Result {
 func (e *Engine) Done(rt ResourceType) {
 	close(e.jobs[rt])
 }
+
+// acquire blocks until the throttle status of the store allows to process
+// another resource collection or the context is cancelled.
+func (e *Engine) acquire(ctx context.Context) error {
+	for {
+		e.amux.Lock()
+		if limit, ok := e.limit(); !ok || e.active < limit {
+			e.active++
+			e.amux.Unlock()
+			return nil
+		}
+		e.amux.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(throttleBackoff):
+		}
+	}
+}
+
+func (e *Engine) release() {
+	e.amux.Lock()
+	defer e.amux.Unlock()
+
+	e.active--
+}
+
+// limit returns the number of resource collections that can be processed at a time
+// based on the available budget. It returns false if the engine doesn't need to slow down.
+func (e *Engine) limit() (int, bool) {
+	if e.throttler == nil {
+		return 0, false
+	}
+	status, ok := e.throttler.ThrottleStatus()
+	if !ok || status.MaximumAvailable <= 0 {
+		return 0, false
+	}
+
+	ratio := status.CurrentlyAvailable / status.MaximumAvailable
+	if ratio >= slowdownThreshold {
+		return 0, false
+	}
+	return max(1, int(float64(e.workers)*ratio/slowdownThreshold)), true
+}
@@ -1,25 +1,28 @@
 package engine
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ankitpokhrel/shopctl/pkg/gql/client"
 )
 
 // MockDoer is a mock implementation of the Doer interface.
 type MockDoer struct {
-	doFunc func(Resource, any) (any, error)
+	doFunc func(context.Context, Resource, any) (any, error)
 }
 
 // Do mocks the Do method.
-func (m *MockDoer) Do(r Resource, d any) (any, error) {
-	return m.doFunc(r, d)
+func (m *MockDoer) Do(ctx context.Context, r Resource, d any) (any, error) {
+	return m.doFunc(ctx, r, d)
 }
 
 func TestEngine_Run(t *testing.T) {
 	doer := &MockDoer{
-		doFunc: func(r Resource, d any) (any, error) {
+		doFunc: func(_ context.Context, r Resource, d any) (any, error) {
 			if r.Type == "fail" {
 				return nil, errors.New("mock error")
 			}
@@ -48,7 +51,7 @@ func TestEngine_Run(t *testing.T) {
 	}()
 
 	// Run the engine.
-	results := engine.Run(Product)
+	results := engine.Run(context.Background(), Product)
 
 	// Collect results.
 	collected := make([]Result, 0)
@@ -68,3 +71,100 @@ func TestEngine_Run(t *testing.T) {
 
 	<-done
 }
+
+func TestEngine_RunCancelled(t *testing.T) {
+	var (
+		processed int
+		cancelled bool
+
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+
+	doer := &MockDoer{
+		doFunc: func(ctx context.Context, r Resource, d any) (any, error) {
+			if r.Type == Product {
+				processed++
+				// Interrupted while the first collection is being processed.
+				cancel()
+			}
+			cancelled = cancelled || ctx.Err() != nil
+			return nil, nil
+		},
+	}
+
+	engine := New(doer, WithWorkers(1))
+	engine.Register(Product)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 5 {
+			parent := Resource{Type: Product}
+			engine.Add(Product, ResourceCollection{
+				Parent:   &parent,
+				Children: []Resource{{Type: ProductVariant}},
+			})
+		}
+		engine.Done(Product)
+	}()
+
+	collected := make([]Result, 0)
+	for res := range engine.Run(ctx, Product) {
+		collected = append(collected, res)
+	}
+	<-done
+
+	// The collection in progress is completed and the rest are not processed.
+	assert.Equal(t, 1, processed)
+	assert.Len(t, collected, 2)
+	assert.False(t, cancelled)
+}
+
+type mockThrottler struct {
+	status *client.ThrottleStatus
+}
+
+func (m mockThrottler) ThrottleStatus() (client.ThrottleStatus, bool) {
+	if m.status == nil {
+		return client.ThrottleStatus{}, false
+	}
+	return *m.status, true
+}
+
+func TestEngine_Limit(t *testing.T) {
+	eng := New(&MockDoer{}, WithWorkers(8))
+	assert.Equal(t, 8, eng.workers)
+
+	// No throttler.
+	_, ok := eng.limit()
+	assert.False(t, ok)
+
+	// No throttle status yet.
+	eng = New(&MockDoer{}, WithWorkers(8), WithThrottler(mockThrottler{}))
+	_, ok = eng.limit()
+	assert.False(t, ok)
+
+	cases := []struct {
+		available float64
+		limit     int
+		throttled bool
+	}{
+		{available: 2000, throttled: false},
+		{available: 1000, throttled: false},
+		{available: 500, limit: 4, throttled: true},
+		{available: 100, limit: 1, throttled: true},
+		{available: 0, limit: 1, throttled: true},
+	}
+	for _, tc := range cases {
+		eng = New(&MockDoer{}, WithWorkers(8), WithThrottler(mockThrottler{
+			status: &client.ThrottleStatus{MaximumAvailable: 2000, CurrentlyAvailable: tc.available, RestoreRate: 100},
+		}))
+		limit, ok := eng.limit()
+		assert.Equal(t, tc.throttled, ok)
+		assert.Equal(t, tc.limit, limit)
+	}
+
+	// Invalid number of workers is ignored.
+	eng = New(&MockDoer{}, WithWorkers(0))
+	assert.Equal(t, DefaultWorkers, eng.workers)
+}
@@ -0,0 +1,140 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Journal entry statuses.
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// JournalEntry is a restore status of a resource. Child resources
+// are recorded against the ID of their parent resource.
+type JournalEntry struct {
+	Resource ResourceType `json:"resource"`
+	ID       string       `json:"id"`
+	NewID    string       `json:"newId,omitempty"`
+	Status   string       `json:"status"`
+	Error    string       `json:"error,omitempty"`
+}
+
+type journalMeta struct {
+	Path      string    `json:"path"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// Journal is an append-only record of a restore. It keeps the
+// latest status of each resource so that the restore can be resumed.
+type Journal struct {
+	mux     sync.Mutex
+	file    *os.File
+	meta    journalMeta
+	entries map[ResourceType]map[string]JournalEntry
+}
+
+// OpenJournal opens the restore journal at the given path for a backup. The
+// journal is started afresh unless resume is set, in which case the entries
+// of the earlier run are loaded and the new entries are appended to them.
+func OpenJournal(path string, backupPath string, resume bool) (*Journal, error) {
+	j := Journal{
+		meta:    journalMeta{Path: backupPath, StartedAt: time.Now()},
+		entries: make(map[ResourceType]map[string]JournalEntry),
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if resume {
+		if err := j.load(path); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), modeDir); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, flags, modeFile)
+	if err != nil {
+		return nil, err
+	}
+	j.file = f
+
+	if err := j.write(j.meta); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &j, nil
+}
+
+func (j *Journal) load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Resource == "" {
+			// Skip the metadata and the partially written entries.
+			continue
+		}
+		j.set(entry)
+	}
+	return scanner.Err()
+}
+
+// Entry returns the latest journal entry of a resource.
+func (j *Journal) Entry(rt ResourceType, id string) (JournalEntry, bool) {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	entry, ok := j.entries[rt][id]
+	return entry, ok
+}
+
+// Succeeded checks if the resource was restored successfully.
+func (j *Journal) Succeeded(rt ResourceType, id string) (JournalEntry, bool) {
+	entry, ok := j.Entry(rt, id)
+	return entry, ok && entry.Status == StatusSucceeded
+}
+
+// Record records the restore status of a resource.
+func (j *Journal) Record(entry JournalEntry) error {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	j.set(entry)
+	return j.write(entry)
+}
+
+// Close closes the journal file.
+func (j *Journal) Close() error {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	return j.file.Close()
+}
+
+func (j *Journal) set(entry JournalEntry) {
+	if _, ok := j.entries[entry.Resource]; !ok {
+		j.entries[entry.Resource] = make(map[string]JournalEntry)
+	}
+	j.entries[entry.Resource][entry.ID] = entry
+}
+
+func (j *Journal) write(v any) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = j.file.Write(append(line, '\n'))
+	return err
+}
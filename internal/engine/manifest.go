@@ -0,0 +1,122 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ManifestFile is the name of the manifest file at the root of a backup.
+const ManifestFile = "manifest.json"
+
+// BackupRef is a reference to a backup.
+type BackupRef struct {
+	ID  string `json:"id"`
+	Dir string `json:"dir"`
+}
+
+// ResourceFilter is a resource exported along with the filter used.
+type ResourceFilter struct {
+	Resource string `json:"resource"`
+	Query    string `json:"query,omitempty"`
+}
+
+// Watermark marks the point up to which a resource has been exported.
+type Watermark struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+	BackupID  string    `json:"backupId"`
+}
+
+// Manifest describes a backup.
+type Manifest struct {
+	ID         string                     `json:"id"`
+	Dir        string                     `json:"dir"`
+	Store      string                     `json:"store"`
+	Alias      string                     `json:"alias,omitempty"`
+	APIVersion string                     `json:"apiVersion"`
+	Version    string                     `json:"version"`
+	Filters    []ResourceFilter           `json:"filters,omitempty"`
+	Counts     map[ResourceType]int       `json:"counts"`
+	StartedAt  time.Time                  `json:"startedAt"`
+	FinishedAt time.Time                  `json:"finishedAt"`
+	Base       *BackupRef                 `json:"base,omitempty"`
+	Watermarks map[ResourceType]Watermark `json:"watermarks,omitempty"`
+	Checksums  map[string]string          `json:"checksums"`
+}
+
+// IsDelta checks if the backup only contains changes since its base backup.
+func (m *Manifest) IsDelta() bool {
+	return m.Base != nil && m.Base.ID != ""
+}
+
+// ReadManifest reads manifest from the given backup directory.
+func ReadManifest(dir string) (*Manifest, error) {
+	content, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	return ParseManifest(content)
+}
+
+// ParseManifest parses raw manifest content.
+func ParseManifest(content []byte) (*Manifest, error) {
+	var m Manifest
+	if err := json.Unmarshal(content, &m); err != nil {
+		return nil, fmt.Errorf("error unmarshalling manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// Checksums calculates SHA-256 checksum of every file in the backup dir
+// except the manifest itself. The files are keyed by their slash separated
+// path relative to the backup dir.
+func Checksums(dir string) (map[string]string, error) {
+	sums := make(map[string]string)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == ManifestFile {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+
+		sum, err := Checksum(f)
+		if err != nil {
+			return err
+		}
+		sums[filepath.ToSlash(rel)] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sums, nil
+}
+
+// Checksum calculates SHA-256 checksum of the content.
+func Checksum(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
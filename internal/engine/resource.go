@@ -1,13 +1,23 @@
 package engine
 
+import "context"
+
 const (
 	Product           ResourceType = "product"
 	ProductOption     ResourceType = "product_option"
 	ProductVariant    ResourceType = "product_variant"
 	ProductMedia      ResourceType = "product_media"
 	ProductMetaField  ResourceType = "product_metafield"
+	ProductInventory  ResourceType = "product_inventory"
+	Collection        ResourceType = "collection"
+	CollectionProduct ResourceType = "collection_product"
 	Customer          ResourceType = "customer"
 	CustomerMetaField ResourceType = "customer_metafield"
+	Order             ResourceType = "order"
+	OrderLineItem     ResourceType = "order_line_item"
+	OrderTransaction  ResourceType = "order_transaction"
+	OrderFulfillment  ResourceType = "order_fulfillment"
+	OrderMetaField    ResourceType = "order_metafield"
 )
 
 // ResourceType represents a type of a resource to backup.
@@ -26,10 +36,26 @@ func (r ResourceType) File() string {
 		return "product_media"
 	case ProductMetaField:
 		return "product_metafields"
+	case ProductInventory:
+		return "product_inventory"
+	case Collection:
+		return "collection"
+	case CollectionProduct:
+		return "collection_products"
 	case Customer:
 		return "customer"
 	case CustomerMetaField:
 		return "customer_metafields"
+	case Order:
+		return "order"
+	case OrderLineItem:
+		return "order_line_items"
+	case OrderTransaction:
+		return "order_transactions"
+	case OrderFulfillment:
+		return "order_fulfillments"
+	case OrderMetaField:
+		return "order_metafields"
 	}
 	panic("unknown resource type")
 }
@@ -39,20 +65,39 @@ func (r ResourceType) RootDir() string {
 	switch r {
 	case Product:
 		return "products"
+	case Collection:
+		return "collections"
 	case Customer:
 		return "customers"
+	case Order:
+		return "orders"
 	}
 	panic("unknown root resource type")
 }
 
 // IsPrimary checks if the resource type is primary.
 func (r ResourceType) IsPrimary() bool {
-	return r == Product || r == Customer
+	return r == Product || r == Collection || r == Customer || r == Order
+}
+
+// Prerequisites returns the primary resource types that need to be processed before the resource type.
+// Collections and customers are restored after products and orders after products and customers so
+// that the resources they refer to can be resolved.
+func (r ResourceType) Prerequisites() []ResourceType {
+	switch r {
+	case Collection:
+		return []ResourceType{Product}
+	case Customer:
+		return []ResourceType{Product}
+	case Order:
+		return []ResourceType{Product, Customer}
+	}
+	return nil
 }
 
 // ResourceHandler is a handler for a resource.
 type ResourceHandler interface {
-	Handle(data any) (any, error)
+	Handle(ctx context.Context, data any) (any, error)
 }
 
 // Resource represents a backup resource.
@@ -81,7 +126,9 @@ type ResourceCollection struct {
 func GetPrimaryResourceTypes() []ResourceType {
 	return []ResourceType{
 		Product,
+		Collection,
 		Customer,
+		Order,
 	}
 }
 
@@ -93,6 +140,15 @@ func GetProductResourceTypes() []ResourceType {
 		ProductVariant,
 		ProductMetaField,
 		ProductMedia,
+		ProductInventory,
+	}
+}
+
+// GetCollectionResourceTypes returns collection resource types in order.
+func GetCollectionResourceTypes() []ResourceType {
+	return []ResourceType{
+		Collection,
+		CollectionProduct,
 	}
 }
 
@@ -104,6 +160,17 @@ func GetCustomerResourceTypes() []ResourceType {
 	}
 }
 
+// GetOrderResourceTypes returns order resource types in order.
+func GetOrderResourceTypes() []ResourceType {
+	return []ResourceType{
+		Order,
+		OrderLineItem,
+		OrderTransaction,
+		OrderFulfillment,
+		OrderMetaField,
+	}
+}
+
 // GetAllResourceTypes returns all resource types in order.
 func GetAllResourceTypes() []ResourceType {
 	return []ResourceType{
@@ -112,7 +179,15 @@ func GetAllResourceTypes() []ResourceType {
 		ProductVariant,
 		ProductMetaField,
 		ProductMedia,
+		ProductInventory,
+		Collection,
+		CollectionProduct,
 		Customer,
 		CustomerMetaField,
+		Order,
+		OrderLineItem,
+		OrderTransaction,
+		OrderFulfillment,
+		OrderMetaField,
 	}
 }
@@ -1,25 +1,88 @@
 package engine
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
 	"time"
 )
 
 // Restore is a restore engine.
 type Restore struct {
+	mux       sync.Mutex
 	store     string
 	timestamp time.Time
+	journal   *Journal
+	resumed   map[ResourceType]int
 }
 
+// RestoreOption is a functional opt for Restore.
+type RestoreOption func(*Restore)
+
 // NewRestore creates a new restore engine.
-func NewRestore(store string) *Restore {
-	return &Restore{
+func NewRestore(store string, opts ...RestoreOption) *Restore {
+	rst := Restore{
 		store:     store,
 		timestamp: time.Now(),
+		resumed:   make(map[ResourceType]int),
+	}
+
+	for _, opt := range opts {
+		opt(&rst)
+	}
+	return &rst
+}
+
+// WithJournal sets the journal to record the restore status of each resource to.
+// Resources that were already restored according to the journal are not restored again.
+func WithJournal(j *Journal) RestoreOption {
+	return func(r *Restore) {
+		r.journal = j
 	}
 }
 
+// Resumed returns the number of resources of the given type
+// skipped as they were restored in an earlier run.
+func (r *Restore) Resumed(rt ResourceType) int {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	return r.resumed[rt]
+}
+
 // Do starts the restoration process.
 // Implements `engine.Doer` interface.
-func (r *Restore) Do(rs Resource, data any) (any, error) {
-	return rs.Handler.Handle(data)
+func (r *Restore) Do(ctx context.Context, rs Resource, data any) (any, error) {
+	if r.journal == nil {
+		return rs.Handler.Handle(ctx, data)
+	}
+
+	// Resources are stored in a dir named after their ID.
+	id := filepath.Base(rs.Path)
+	if entry, ok := r.journal.Succeeded(rs.Type, id); ok {
+		r.mux.Lock()
+		r.resumed[rs.Type]++
+		r.mux.Unlock()
+
+		return entry.NewID, nil
+	}
+
+	out, err := rs.Handler.Handle(ctx, data)
+	if errors.Is(err, ErrSkipChildren) {
+		return out, err
+	}
+
+	entry := JournalEntry{Resource: rs.Type, ID: id, Status: StatusSucceeded}
+	if err != nil {
+		entry.Status = StatusFailed
+		entry.Error = err.Error()
+	}
+	if newID, ok := out.(string); ok {
+		entry.NewID = newID
+	}
+	// Journal is best effort; failing to record only means the resource is restored again on resume.
+	_ = r.journal.Record(entry)
+
+	return out, err
 }
@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type restoreHandler struct {
+	out   any
+	err   error
+	calls int
+}
+
+func (h *restoreHandler) Handle(_ context.Context, _ any) (any, error) {
+	h.calls++
+	return h.out, h.err
+}
+
+func TestRestore_DoWithJournal(t *testing.T) {
+	path := "./testdata/.tmp/journal.jsonl"
+
+	journal, err := OpenJournal(path, "/path/to/backup", false)
+	assert.NoError(t, err)
+
+	product := &restoreHandler{out: "gid://shopify/Product/2"}
+	variant := &restoreHandler{err: fmt.Errorf("failed")}
+	skipped := &restoreHandler{err: ErrSkipChildren}
+
+	rst := NewRestore("teststore.example.com", WithJournal(journal))
+
+	out, err := rst.Do(context.Background(), NewResource(Product, "/tmp/products/1", product), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "gid://shopify/Product/2", out)
+
+	_, err = rst.Do(context.Background(), NewResource(ProductVariant, "/tmp/products/1", variant), out)
+	assert.Error(t, err)
+
+	_, err = rst.Do(context.Background(), NewResource(Product, "/tmp/products/3", skipped), nil)
+	assert.ErrorIs(t, err, ErrSkipChildren)
+	assert.NoError(t, journal.Close())
+
+	// Resumed restore skips the resources that succeeded.
+	journal, err = OpenJournal(path, "/path/to/backup", true)
+	assert.NoError(t, err)
+
+	entry, ok := journal.Succeeded(Product, "1")
+	assert.True(t, ok)
+	assert.Equal(t, JournalEntry{Resource: Product, ID: "1", NewID: "gid://shopify/Product/2", Status: StatusSucceeded}, entry)
+
+	entry, ok = journal.Entry(ProductVariant, "1")
+	assert.True(t, ok)
+	assert.Equal(t, JournalEntry{Resource: ProductVariant, ID: "1", Status: StatusFailed, Error: "failed"}, entry)
+
+	_, ok = journal.Entry(Product, "3")
+	assert.False(t, ok)
+
+	variant.err = nil
+	rst = NewRestore("teststore.example.com", WithJournal(journal))
+
+	out, err = rst.Do(context.Background(), NewResource(Product, "/tmp/products/1", product), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "gid://shopify/Product/2", out)
+	assert.Equal(t, 1, product.calls)
+	assert.Equal(t, 1, rst.Resumed(Product))
+
+	_, err = rst.Do(context.Background(), NewResource(ProductVariant, "/tmp/products/1", variant), out)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, variant.calls)
+	assert.Equal(t, 0, rst.Resumed(ProductVariant))
+
+	_, ok = journal.Succeeded(ProductVariant, "1")
+	assert.True(t, ok)
+	assert.NoError(t, journal.Close())
+
+	// A new restore starts the journal afresh.
+	journal, err = OpenJournal(path, "/path/to/backup", false)
+	assert.NoError(t, err)
+	_, ok = journal.Entry(Product, "1")
+	assert.False(t, ok)
+	assert.NoError(t, journal.Close())
+
+	// Clean up.
+	assert.NoError(t, os.RemoveAll("./testdata/.tmp"))
+}
@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Scheduler runs tasks of primary resource types in the order of their prerequisites.
+// A task starts once the tasks of all of its scheduled prerequisites are done, so the
+// resource types that don't depend on each other are still processed in parallel.
+type Scheduler struct {
+	mux   sync.Mutex
+	tasks map[ResourceType]func(context.Context) error
+	order []ResourceType
+}
+
+// NewScheduler creates a new scheduler.
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		tasks: make(map[ResourceType]func(context.Context) error),
+	}
+}
+
+// Add schedules a task for the resource type. A resource type can only be scheduled once.
+func (s *Scheduler) Add(rt ResourceType, fn func(context.Context) error) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if _, ok := s.tasks[rt]; ok {
+		return fmt.Errorf("resource type %s is already scheduled", rt)
+	}
+	s.tasks[rt] = fn
+	s.order = append(s.order, rt)
+
+	return nil
+}
+
+// Run executes scheduled tasks and waits for them to finish. A task still runs
+// if one of its prerequisites failed; errors of all tasks are returned joined.
+// Tasks that are waiting for their prerequisites are not started once the
+// context is cancelled.
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	var (
+		wg   sync.WaitGroup
+		emux sync.Mutex
+		errs []error
+	)
+
+	done := make(map[ResourceType]chan struct{}, len(s.tasks))
+	for _, rt := range s.order {
+		done[rt] = make(chan struct{})
+	}
+
+	for _, rt := range s.order {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer close(done[rt])
+
+			for _, pre := range rt.Prerequisites() {
+				if ch, ok := done[pre]; ok {
+					<-ch
+				}
+			}
+
+			if ctx.Err() != nil {
+				return
+			}
+			if err := s.tasks[rt](ctx); err != nil {
+				emux.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", rt, err))
+				emux.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler_Run(t *testing.T) {
+	var (
+		mux    sync.Mutex
+		events []string
+
+		// Collections and customers wait for each other to start, so the
+		// test only completes if they are processed in parallel.
+		started = map[ResourceType]chan struct{}{
+			Collection: make(chan struct{}),
+			Customer:   make(chan struct{}),
+		}
+		peers = map[ResourceType]ResourceType{Collection: Customer, Customer: Collection}
+	)
+
+	record := func(ev string) {
+		mux.Lock()
+		defer mux.Unlock()
+
+		events = append(events, ev)
+	}
+	task := func(rt ResourceType, err error) func(context.Context) error {
+		return func(context.Context) error {
+			record("start " + string(rt))
+			if peer, ok := peers[rt]; ok {
+				close(started[rt])
+				<-started[peer]
+			}
+			record("finish " + string(rt))
+
+			return err
+		}
+	}
+	at := func(ev string) int {
+		return slices.Index(events, ev)
+	}
+
+	s := NewScheduler()
+	assert.NoError(t, s.Add(Order, task(Order, nil)))
+	assert.NoError(t, s.Add(Customer, task(Customer, nil)))
+	assert.NoError(t, s.Add(Collection, task(Collection, nil)))
+	assert.NoError(t, s.Add(Product, task(Product, errors.New("mock error"))))
+	assert.Error(t, s.Add(Product, task(Product, nil)))
+
+	err := s.Run(context.Background())
+	assert.EqualError(t, err, "product: mock error")
+	assert.Len(t, events, 8)
+
+	// Collections and customers wait for products and orders wait for both products and customers.
+	assert.Greater(t, at("start collection"), at("finish product"))
+	assert.Greater(t, at("start customer"), at("finish product"))
+	assert.Greater(t, at("start order"), at("finish customer"))
+
+	// Collections and customers don't depend on each other.
+	assert.Less(t, at("start collection"), at("finish customer"))
+	assert.Less(t, at("start customer"), at("finish collection"))
+}
+
+func TestScheduler_RunWithoutPrerequisites(t *testing.T) {
+	var ran []ResourceType
+
+	s := NewScheduler()
+	assert.NoError(t, s.Add(Order, func(context.Context) error {
+		ran = append(ran, Order)
+		return nil
+	}))
+
+	assert.NoError(t, s.Run(context.Background()))
+	assert.Equal(t, []ResourceType{Order}, ran)
+}
+
+func TestScheduler_RunCancelled(t *testing.T) {
+	var (
+		mux sync.Mutex
+		ran []ResourceType
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := NewScheduler()
+	assert.NoError(t, s.Add(Product, func(context.Context) error {
+		mux.Lock()
+		ran = append(ran, Product)
+		mux.Unlock()
+
+		// Interrupted while products are being processed.
+		cancel()
+		return nil
+	}))
+	assert.NoError(t, s.Add(Customer, func(context.Context) error {
+		mux.Lock()
+		ran = append(ran, Customer)
+		mux.Unlock()
+		return nil
+	}))
+
+	assert.NoError(t, s.Run(ctx))
+	assert.Equal(t, []ResourceType{Product}, ran)
+}
@@ -36,13 +36,15 @@ var (
 		// Product, ProductVariant, Collection, Inventory.
 		"write_products",
 		"read_product_listings",
-		"read_inventory",
+		"write_inventory",
+		"read_locations",
 
 		// Customer data.
 		"write_customers",
 
 		// Order.
 		"write_orders",
+		"write_merchant_managed_fulfillment_orders",
 
 		// Files.
 		"write_files",
@@ -0,0 +1,113 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+)
+
+// ErrNoManifest is returned if a backup doesn't have a manifest.
+var ErrNoManifest = fmt.Errorf("manifest not found")
+
+// ReadManifest reads manifest of a backup directory or a .tar.gz file.
+func ReadManifest(path string) (*engine.Manifest, error) {
+	var (
+		content []byte
+		err     error
+	)
+
+	if strings.HasSuffix(path, ".tar.gz") {
+		content, err = ReadFileFromZip(path, engine.ManifestFile)
+	} else {
+		content, err = ReadFileContents(filepath.Join(path, engine.ManifestFile))
+	}
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNoManifest
+		}
+		return nil, err
+	}
+	return engine.ParseManifest(content)
+}
+
+// ResolveChain resolves the backup at the given path along with all the base
+// backups it is a delta of. Backups are ordered base first so that the latter
+// ones take precedence when applied in order.
+//
+// Base backups are looked up next to the given backup either by their
+// directory name or by their backup ID.
+func ResolveChain(path string) ([]string, error) {
+	var (
+		chain = []string{path}
+		seen  = map[string]bool{}
+	)
+
+	for curr := path; ; {
+		m, err := ReadManifest(curr)
+		if err != nil {
+			if errors.Is(err, ErrNoManifest) {
+				break
+			}
+			return nil, err
+		}
+		if seen[m.ID] {
+			return nil, fmt.Errorf("backup %s: circular reference to the base backup", m.ID)
+		}
+		seen[m.ID] = true
+
+		if !m.IsDelta() {
+			break
+		}
+		base, err := locateBackup(m.Base, filepath.Dir(curr))
+		if err != nil {
+			return nil, fmt.Errorf("backup %s: unable to locate base backup %s: %w", m.ID, m.Base.ID, err)
+		}
+		chain = append(chain, base)
+		curr = base
+	}
+
+	slices.Reverse(chain)
+	return chain, nil
+}
+
+// MergeChain merges backups in a chain to a temp location. Records
+// in a later backup overwrite the ones from the earlier backups.
+// Deletions are not exported, so records deleted after the first
+// backup in the chain are kept.
+func MergeChain(chain []string, name string) (string, error) {
+	tmpDir, err := os.MkdirTemp("", fmt.Sprintf("shopctl-%s-*", name))
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	for _, path := range chain {
+		if strings.HasSuffix(path, ".tar.gz") {
+			err = ExtractZip(path, tmpDir)
+		} else {
+			err = CopyDir(path, tmpDir)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return tmpDir, nil
+}
+
+func locateBackup(ref *engine.BackupRef, in string) (string, error) {
+	if ref.Dir != "" {
+		for _, loc := range []string{
+			filepath.Join(in, ref.Dir),
+			filepath.Join(in, ref.Dir+".tar.gz"),
+		} {
+			if Exists(loc) {
+				return loc, nil
+			}
+		}
+	}
+	return LookForDirWithSuffix(ref.ID, in)
+}
@@ -0,0 +1,63 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveChain(t *testing.T) {
+	path := "./testdata/.tmp"
+
+	backups := map[string]string{
+		"base":   `{"id":"b1","dir":"base"}`,
+		"delta1": `{"id":"d1","dir":"delta1","base":{"id":"b1","dir":"base"}}`,
+		"delta2": `{"id":"d2","dir":"delta2","base":{"id":"d1","dir":"delta1"}}`,
+	}
+	for dir, manifest := range backups {
+		assert.NoError(t, os.MkdirAll(filepath.Join(path, dir, "products", "1"), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(path, dir, "manifest.json"), []byte(manifest), 0o644))
+		assert.NoError(t, os.WriteFile(filepath.Join(path, dir, "products", "1", "product.json"), []byte(`{"id":"gid://shopify/Product/1","title":"`+dir+`"}`), 0o644))
+	}
+	assert.NoError(t, os.MkdirAll(filepath.Join(path, "base", "products", "2"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(path, "base", "products", "2", "product.json"), []byte(`{"id":"gid://shopify/Product/2","title":"base"}`), 0o644))
+
+	chain, err := ResolveChain(filepath.Join(path, "delta2"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(path, "base"),
+		filepath.Join(path, "delta1"),
+		filepath.Join(path, "delta2"),
+	}, chain)
+
+	chain, err = ResolveChain(filepath.Join(path, "base"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(path, "base")}, chain)
+
+	// Latest version of a product is picked from the chain.
+	reg, err := NewRegistry(filepath.Join(path, "delta2"))
+	assert.NoError(t, err)
+
+	product, err := reg.GetProductByID("1")
+	assert.NoError(t, err)
+	assert.Equal(t, "delta2", product.Title)
+
+	product, err = reg.GetProductByID("2")
+	assert.NoError(t, err)
+	assert.Equal(t, "base", product.Title)
+
+	merged, err := MergeChain(chain, "test")
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(merged, "products", "2", "product.json"))
+	assert.NoError(t, os.RemoveAll(merged))
+
+	// Missing base backup.
+	assert.NoError(t, os.RemoveAll(filepath.Join(path, "delta1")))
+	_, err = ResolveChain(filepath.Join(path, "delta2"))
+	assert.Error(t, err)
+
+	// Clean up.
+	assert.NoError(t, os.RemoveAll(path))
+}
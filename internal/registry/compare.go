@@ -0,0 +1,191 @@
+package registry
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/ankitpokhrel/shopctl"
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/pkg/diff"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+// Status of a resource in the snapshot diff.
+const (
+	StatusAdded    = "added"
+	StatusRemoved  = "removed"
+	StatusModified = "modified"
+)
+
+// ignoredFields are the fields that change without the resource being modified
+// or that differ between stores. Variant and media counts are compared separately.
+var ignoredFields = []string{
+	"id",
+	"legacyResourceId",
+	"createdAt",
+	"updatedAt",
+	"defaultCursor",
+	"variantsCount",
+	"mediaCount",
+}
+
+// ResourceDiff is the difference of a resource between two snapshots.
+type ResourceDiff struct {
+	Resource engine.ResourceType `json:"resource"`
+	Key      string              `json:"key"`
+	Status   string              `json:"status"`
+	Changes  []diff.Change       `json:"changes,omitempty"`
+
+	// Old and New are the compared values without the ignored
+	// fields. They are nil if the resource is missing on that side.
+	Old any `json:"-"`
+	New any `json:"-"`
+}
+
+// CompareSnapshots compares the snapshots and returns the resources that were added, removed
+// or modified in `to`. Products are matched by their handle, variants by their title, media
+// by their ID and customers by their email or phone. Variants and media of an added or a
+// removed product are not listed separately.
+func CompareSnapshots(from, to *Snapshot) ([]ResourceDiff, error) {
+	out := make([]ResourceDiff, 0)
+
+	fromProducts := byKey(from.Products, func(p *SnapshotProduct) string { return p.Product.Handle })
+	toProducts := byKey(to.Products, func(p *SnapshotProduct) string { return p.Product.Handle })
+
+	for _, key := range sortedKeys(fromProducts, toProducts) {
+		a, b := fromProducts[key], toProducts[key]
+
+		var pa, pb *schema.Product
+		if a != nil {
+			pa = &a.Product
+		}
+		if b != nil {
+			pb = &b.Product
+		}
+		d, err := compareResource(engine.Product, key, pa, pb)
+		if err != nil {
+			return nil, err
+		}
+		if d != nil {
+			out = append(out, *d)
+		}
+		if a == nil || b == nil {
+			continue
+		}
+
+		variants, err := compareList(engine.ProductVariant, key, a.Variants, b.Variants, func(v schema.ProductVariant) string { return v.Title })
+		if err != nil {
+			return nil, err
+		}
+		media, err := compareList(engine.ProductMedia, key, a.Media, b.Media, func(m api.ProductMediaNode) string { return shopctl.ExtractNumericID(m.ID) })
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, variants...)
+		out = append(out, media...)
+	}
+
+	fromCustomers := byKey(from.Customers, snapshotCustomerKey)
+	toCustomers := byKey(to.Customers, snapshotCustomerKey)
+
+	for _, key := range sortedKeys(fromCustomers, toCustomers) {
+		d, err := compareResource(engine.Customer, key, fromCustomers[key], toCustomers[key])
+		if err != nil {
+			return nil, err
+		}
+		if d != nil {
+			out = append(out, *d)
+		}
+	}
+	return out, nil
+}
+
+// compareList compares the child resources of a product. Keys are prefixed with the product handle.
+func compareList[T any](rt engine.ResourceType, parent string, from, to []T, keyFn func(T) string) ([]ResourceDiff, error) {
+	a := make(map[string]*T, len(from))
+	for i := range from {
+		a[keyFn(from[i])] = &from[i]
+	}
+	b := make(map[string]*T, len(to))
+	for i := range to {
+		b[keyFn(to[i])] = &to[i]
+	}
+
+	out := make([]ResourceDiff, 0)
+	for _, key := range sortedKeys(a, b) {
+		d, err := compareResource(rt, parent+"/"+key, a[key], b[key])
+		if err != nil {
+			return nil, err
+		}
+		if d != nil {
+			out = append(out, *d)
+		}
+	}
+	return out, nil
+}
+
+func compareResource[T any](rt engine.ResourceType, key string, from, to *T) (*ResourceDiff, error) {
+	d := ResourceDiff{Resource: rt, Key: key}
+
+	var err error
+	if from != nil {
+		if d.Old, err = diff.Normalize(from, diff.Ignore(ignoredFields...)); err != nil {
+			return nil, err
+		}
+	}
+	if to != nil {
+		if d.New, err = diff.Normalize(to, diff.Ignore(ignoredFields...)); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case from == nil:
+		d.Status = StatusAdded
+	case to == nil:
+		d.Status = StatusRemoved
+	default:
+		if d.Changes, err = diff.Compare(d.Old, d.New); err != nil {
+			return nil, err
+		}
+		if len(d.Changes) == 0 {
+			return nil, nil
+		}
+		d.Status = StatusModified
+	}
+	return &d, nil
+}
+
+func byKey[T any](items map[string]*T, keyFn func(*T) string) map[string]*T {
+	out := make(map[string]*T, len(items))
+	for id, item := range items {
+		key := keyFn(item)
+		if key == "" {
+			key = id
+		}
+		out[key] = item
+	}
+	return out
+}
+
+func sortedKeys[T any](a, b map[string]*T) []string {
+	keys := slices.Collect(maps.Keys(a))
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func snapshotCustomerKey(c *schema.Customer) string {
+	if c.Email != nil && *c.Email != "" {
+		return *c.Email
+	}
+	if c.Phone != nil {
+		return *c.Phone
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/pkg/diff"
+)
+
+func TestCompareSnapshots(t *testing.T) {
+	path := "./testdata/.tmp"
+
+	backups := map[string]map[string]string{
+		"monday": {
+			"products/1/product.json":          `{"id":"gid://shopify/Product/1","handle":"shirt","title":"Shirt","updatedAt":"2025-01-01T00:00:00Z"}`,
+			"products/1/product_variants.json": `{"id":"gid://shopify/Product/1","variants":{"nodes":[{"id":"gid://shopify/ProductVariant/1","title":"S","price":"10.00"},{"id":"gid://shopify/ProductVariant/2","title":"M","price":"10.00"}]}}`,
+			"products/2/product.json":          `{"id":"gid://shopify/Product/2","handle":"cap","title":"Cap"}`,
+			"customers/1/customer.json":        `{"id":"gid://shopify/Customer/1","email":"jon@example.com","firstName":"Jon"}`,
+		},
+		"friday": {
+			"products/1/product.json":          `{"id":"gid://shopify/Product/1","handle":"shirt","title":"Red shirt","updatedAt":"2025-01-05T00:00:00Z"}`,
+			"products/1/product_variants.json": `{"id":"gid://shopify/Product/1","variants":{"nodes":[{"id":"gid://shopify/ProductVariant/1","title":"S","price":"12.00"},{"id":"gid://shopify/ProductVariant/3","title":"L","price":"10.00"}]}}`,
+			"products/1/product_media.json":    `{"id":"gid://shopify/Product/1","media":{"nodes":[{"id":"gid://shopify/MediaImage/1","status":"READY"}]}}`,
+			"products/3/product.json":          `{"id":"gid://shopify/Product/3","handle":"socks","title":"Socks"}`,
+			"customers/1/customer.json":        `{"id":"gid://shopify/Customer/1","email":"jon@example.com","firstName":"Jon"}`,
+		},
+	}
+
+	snapshots := make(map[string]*Snapshot)
+	for name, files := range backups {
+		for file, content := range files {
+			loc := filepath.Join(path, name, file)
+			assert.NoError(t, os.MkdirAll(filepath.Dir(loc), 0o755))
+			assert.NoError(t, os.WriteFile(loc, []byte(content), 0o644))
+		}
+
+		reg, err := NewRegistry(filepath.Join(path, name))
+		assert.NoError(t, err)
+
+		snap, err := reg.Snapshot(engine.Product, engine.Customer)
+		assert.NoError(t, err)
+		snapshots[name] = snap
+	}
+
+	assert.Len(t, snapshots["friday"].Products, 2)
+	assert.Len(t, snapshots["friday"].Products["1"].Variants, 2)
+	assert.Len(t, snapshots["friday"].Customers, 1)
+
+	diffs, err := CompareSnapshots(snapshots["monday"], snapshots["friday"])
+	assert.NoError(t, err)
+
+	type result struct {
+		Resource engine.ResourceType
+		Key      string
+		Status   string
+		Changes  []diff.Change
+	}
+	got := make([]result, 0, len(diffs))
+	for _, d := range diffs {
+		got = append(got, result{Resource: d.Resource, Key: d.Key, Status: d.Status, Changes: d.Changes})
+	}
+
+	assert.Equal(t, []result{
+		{Resource: engine.Product, Key: "cap", Status: StatusRemoved},
+		{Resource: engine.Product, Key: "shirt", Status: StatusModified, Changes: []diff.Change{{Field: "title", Old: "Shirt", New: "Red shirt"}}},
+		{Resource: engine.ProductVariant, Key: "shirt/L", Status: StatusAdded},
+		{Resource: engine.ProductVariant, Key: "shirt/M", Status: StatusRemoved},
+		{Resource: engine.ProductVariant, Key: "shirt/S", Status: StatusModified, Changes: []diff.Change{{Field: "price", Old: "10.00", New: "12.00"}}},
+		{Resource: engine.ProductMedia, Key: "shirt/1", Status: StatusAdded},
+		{Resource: engine.Product, Key: "socks", Status: StatusAdded},
+	}, got)
+
+	// Products only.
+	reg, err := NewRegistry(filepath.Join(path, "friday"))
+	assert.NoError(t, err)
+	products, err := reg.Snapshot(engine.Product)
+	assert.NoError(t, err)
+	assert.Empty(t, products.Customers)
+
+	// Clean up.
+	assert.NoError(t, os.RemoveAll(path))
+}
@@ -5,7 +5,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -84,25 +86,33 @@ func GetAllInDir(dir, ext string) (<-chan File, error) {
 
 // ExtractZipToTemp extracts .tar.gz file to a temp location.
 func ExtractZipToTemp(zipPath string, name string) (string, error) {
+	tmpDir, err := os.MkdirTemp("", fmt.Sprintf("shopctl-%s-*", name))
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	if err := ExtractZip(zipPath, tmpDir); err != nil {
+		return "", err
+	}
+	return tmpDir, nil
+}
+
+// ExtractZip extracts .tar.gz file to the given location.
+// Existing files in the destination are overwritten.
+func ExtractZip(zipPath string, destDir string) error {
 	zipFile, err := os.Open(zipPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to open zip file: %w", err)
+		return fmt.Errorf("failed to open zip file: %w", err)
 	}
 	defer func() { _ = zipFile.Close() }()
 
 	gz, err := gzip.NewReader(zipFile)
 	if err != nil {
-		return "", err
+		return err
 	}
 	defer func() { _ = gz.Close() }()
 
-	tmpDir, err := os.MkdirTemp("", fmt.Sprintf("shopctl-%s-*", name))
-	if err != nil {
-		return "", fmt.Errorf("failed to create temp directory: %w", err)
-	}
-
 	fileHandler := func(ctx context.Context, file archives.FileInfo) error {
-		destPath := filepath.Join(tmpDir, file.NameInArchive)
+		destPath := filepath.Join(destDir, file.NameInArchive)
 
 		if file.IsDir() {
 			if err := os.MkdirAll(destPath, os.ModePerm); err != nil {
@@ -111,36 +121,151 @@ func ExtractZipToTemp(zipPath string, name string) (string, error) {
 			return nil
 		}
 
-		if err := os.MkdirAll(filepath.Dir(destPath), os.ModePerm); err != nil {
-			return fmt.Errorf("failed to create directories for %s: %w", destPath, err)
-		}
-
 		src, err := file.Open()
 		if err != nil {
 			return fmt.Errorf("failed to open file %s in zip: %w", file.NameInArchive, err)
 		}
 		defer func() { _ = src.Close() }()
 
-		dest, err := os.Create(destPath)
+		return writeFile(destPath, src)
+	}
+
+	var zipFormat archives.Tar
+
+	err = zipFormat.Extract(context.Background(), gz, fileHandler)
+	if err != nil {
+		return fmt.Errorf("failed to extract zip file: %w", err)
+	}
+	return nil
+}
+
+// WalkBackup walks all files in a backup directory or a .tar.gz file. The
+// callback receives the file name relative to the backup root along with its content.
+func WalkBackup(loc string, fn func(name string, r io.Reader) error) error {
+	if !strings.HasSuffix(loc, ".tar.gz") {
+		return filepath.Walk(loc, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(loc, path)
+			if err != nil {
+				return err
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+
+			return fn(filepath.ToSlash(rel), f)
+		})
+	}
+
+	zipFile, err := os.Open(loc)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = zipFile.Close() }()
+
+	gz, err := gzip.NewReader(zipFile)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = gz.Close() }()
+
+	var format archives.Tar
+	return format.Extract(context.Background(), gz, func(ctx context.Context, f archives.FileInfo) error {
+		if f.IsDir() {
+			return nil
+		}
+
+		file, err := f.Open()
 		if err != nil {
-			return fmt.Errorf("failed to create file %s: %w", destPath, err)
+			return err
 		}
-		defer func() { _ = dest.Close() }()
+		defer func() { _ = file.Close() }()
+
+		return fn(archiveName(f.NameInArchive), file)
+	})
+}
 
-		if _, err := io.Copy(dest, src); err != nil {
-			return fmt.Errorf("failed to write file %s: %w", destPath, err)
+// CopyDir copies contents of a directory to the given location.
+// Existing files in the destination are overwritten.
+func CopyDir(srcDir string, destDir string) error {
+	return filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
 		}
+		destPath := filepath.Join(destDir, rel)
 
-		return nil
+		if info.IsDir() {
+			return os.MkdirAll(destPath, os.ModePerm)
+		}
+
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = src.Close() }()
+
+		return writeFile(destPath, src)
+	})
+}
+
+// ReadFileFromZip reads contents of a file at the given path inside the .tar.gz file.
+func ReadFileFromZip(zipPath string, name string) ([]byte, error) {
+	var (
+		content []byte
+		found   bool
+		format  archives.Tar
+	)
+
+	zipFile, err := os.Open(zipPath)
+	if err != nil {
+		return nil, err
 	}
+	defer func() { _ = zipFile.Close() }()
 
-	var zipFormat archives.Tar
+	gz, err := gzip.NewReader(zipFile)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = gz.Close() }()
 
-	err = zipFormat.Extract(context.Background(), gz, fileHandler)
+	name = archiveName(name)
+	err = format.Extract(context.Background(), gz, func(ctx context.Context, f archives.FileInfo) error {
+		if f.IsDir() || archiveName(f.NameInArchive) != name {
+			return nil
+		}
+
+		file, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = file.Close() }()
+
+		content, err = io.ReadAll(file)
+		if err != nil {
+			return err
+		}
+		found = true
+		return fs.SkipAll // Stop walking.
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to extract zip file: %w", err)
+		return nil, err
 	}
-	return tmpDir, nil
+	if !found {
+		return nil, os.ErrNotExist
+	}
+	return content, nil
 }
 
 // ReadFileContents reads the contents of a file at a specified path.
@@ -196,3 +321,26 @@ func lookForDir(in string, cmpFn func(os.FileInfo) bool) (string, error) {
 	}
 	return loc, nil
 }
+
+// archiveName normalizes the name of a file in an archive
+// to a slash separated path relative to the archive root.
+func archiveName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(name)), "/")
+}
+
+func writeFile(destPath string, src io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(destPath), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create directories for %s: %w", destPath, err)
+	}
+
+	dest, err := os.Create(destPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", destPath, err)
+	}
+	defer func() { _ = dest.Close() }()
+
+	if _, err := io.Copy(dest, src); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", destPath, err)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/mholt/archives"
@@ -23,11 +24,13 @@ var (
 	ErrTargetFound = fmt.Errorf("target found")
 	// ErrNoTargetFound is returned if a target is not found.
 	ErrNoTargetFound = fmt.Errorf("no target found")
+	// ErrProductNotFound is returned if a product is not found in the backup.
+	ErrProductNotFound = fmt.Errorf("product not found")
 )
 
 // Registry is a backup registry.
 type Registry struct {
-	dir string
+	chain []string
 }
 
 // NewRegistry constructs a new registry.
@@ -39,19 +42,47 @@ func NewRegistry(path string) (*Registry, error) {
 	if !strings.HasSuffix(info.Name(), ".tar.gz") && !info.IsDir() {
 		return nil, fmt.Errorf("provided path is not a directory or .tar.gz file")
 	}
-	return &Registry{dir: path}, nil
+
+	chain, err := ResolveChain(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Registry{chain: chain}, nil
+}
+
+// Path returns path to the latest backup in the registry.
+func (r *Registry) Path() string {
+	return r.chain[len(r.chain)-1]
 }
 
 // GetProductByID fetches a product by ID.
+//
+// If the registry is a delta of other backups, the product
+// is looked up from the latest to the base backup.
 func (r *Registry) GetProductByID(id string) (*schema.Product, error) {
-	if strings.HasSuffix(r.dir, ".tar.gz") {
-		return r.getProductByIDFromZip(id)
+	var (
+		product *schema.Product
+		err     error
+	)
+
+	for _, path := range slices.Backward(r.chain) {
+		if strings.HasSuffix(path, ".tar.gz") {
+			product, err = getProductByIDFromZip(path, id)
+		} else {
+			product, err = getProductByIDFromDir(path, id)
+		}
+		if err == nil || !errors.Is(err, ErrProductNotFound) {
+			break
+		}
 	}
+	return product, err
+}
 
-	loc, err := LookForDir(id, r.dir)
+func getProductByIDFromDir(dir string, id string) (*schema.Product, error) {
+	loc, err := LookForDir(id, dir)
 	if err != nil {
 		if errors.Is(err, ErrNoTargetFound) {
-			return nil, fmt.Errorf("product not found")
+			return nil, ErrProductNotFound
 		}
 		return nil, err
 	}
@@ -93,7 +124,7 @@ func (r *Registry) GetProductByID(id string) (*schema.Product, error) {
 	return &product, nil
 }
 
-func (r *Registry) getProductByIDFromZip(id string) (*schema.Product, error) {
+func getProductByIDFromZip(path string, id string) (*schema.Product, error) {
 	var (
 		product        *schema.Product
 		productVariant []schema.ProductVariant
@@ -108,7 +139,7 @@ func (r *Registry) getProductByIDFromZip(id string) (*schema.Product, error) {
 		return nil, err
 	}
 
-	zipFile, err := os.Open(r.dir)
+	zipFile, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
@@ -170,7 +201,7 @@ func (r *Registry) getProductByIDFromZip(id string) (*schema.Product, error) {
 	}
 
 	if product == nil {
-		return nil, fmt.Errorf("product not found")
+		return nil, ErrProductNotFound
 	}
 	if productVariant != nil {
 		product.Variants.Nodes = productVariant
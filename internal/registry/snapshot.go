@@ -0,0 +1,196 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+
+	"github.com/ankitpokhrel/shopctl"
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+const snapshotBatchSize = 50
+
+// SnapshotProduct is a product along with its variants, media and metafields.
+type SnapshotProduct struct {
+	Product  schema.Product
+	Variants []schema.ProductVariant
+	Media    []api.ProductMediaNode
+	// Metafields are only read from backups.
+	Metafields []schema.Metafield
+}
+
+// Snapshot is the state of the products and customers of a
+// store at a point in time, read from a backup or the store.
+type Snapshot struct {
+	// Products are keyed by their numeric ID.
+	Products map[string]*SnapshotProduct
+	// Customers are keyed by their numeric ID.
+	Customers map[string]*schema.Customer
+	// CustomerMetafields are keyed by the numeric ID of the
+	// customer. They are only read from backups.
+	CustomerMetafields map[string][]schema.Metafield
+}
+
+func newSnapshot() *Snapshot {
+	return &Snapshot{
+		Products:           make(map[string]*SnapshotProduct),
+		Customers:          make(map[string]*schema.Customer),
+		CustomerMetafields: make(map[string][]schema.Metafield),
+	}
+}
+
+// Snapshot reads the given resource types from the backups in the registry. If the
+// registry is a delta of other backups, resources from the latest backup take precedence.
+func (r *Registry) Snapshot(rts ...engine.ResourceType) (*Snapshot, error) {
+	snap := newSnapshot()
+
+	for _, loc := range r.chain {
+		err := WalkBackup(loc, func(name string, rdr io.Reader) error {
+			var (
+				id   = path.Base(path.Dir(name))
+				file = path.Base(name)
+			)
+
+			switch {
+			case slices.Contains(rts, engine.Product) && slices.Contains([]string{"product.json", "product_variants.json", "product_media.json", "product_metafields.json"}, file):
+				return readSnapshotProduct(snap, id, file, rdr)
+			case slices.Contains(rts, engine.Customer) && file == "customer.json":
+				var customer schema.Customer
+				if err := json.NewDecoder(rdr).Decode(&customer); err != nil {
+					return fmt.Errorf("error unmarshalling %s: %w", name, err)
+				}
+				snap.Customers[id] = &customer
+			case slices.Contains(rts, engine.Customer) && file == "customer_metafields.json":
+				var metafields api.CustomerMetafieldsData
+				if err := json.NewDecoder(rdr).Decode(&metafields); err != nil {
+					return fmt.Errorf("error unmarshalling %s: %w", name, err)
+				}
+				snap.CustomerMetafields[id] = metafields.Metafields.Nodes
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Variants and media without a product file can't be compared.
+	for id, p := range snap.Products {
+		if p.Product.ID == "" {
+			delete(snap.Products, id)
+		}
+	}
+	return snap, nil
+}
+
+func readSnapshotProduct(snap *Snapshot, id string, file string, rdr io.Reader) error {
+	p, ok := snap.Products[id]
+	if !ok {
+		p = &SnapshotProduct{}
+		snap.Products[id] = p
+	}
+
+	var err error
+	switch file {
+	case "product.json":
+		err = json.NewDecoder(rdr).Decode(&p.Product)
+	case "product_variants.json":
+		var variants api.ProductVariantData
+		if err = json.NewDecoder(rdr).Decode(&variants); err == nil {
+			p.Variants = variants.Variants.Nodes
+		}
+	case "product_media.json":
+		var media api.ProductMediaData
+		if err = json.NewDecoder(rdr).Decode(&media); err == nil {
+			p.Media = media.Media.Nodes
+		}
+	case "product_metafields.json":
+		var metafields api.ProductMetafieldsData
+		if err = json.NewDecoder(rdr).Decode(&metafields); err == nil {
+			p.Metafields = metafields.Metafields.Nodes
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("error unmarshalling %s of product %s: %w", file, id, err)
+	}
+	return nil
+}
+
+// StoreSnapshot fetches the given resource types from the store.
+func StoreSnapshot(ctx context.Context, client *api.GQLClient, rts ...engine.ResourceType) (*Snapshot, error) {
+	snap := newSnapshot()
+
+	if slices.Contains(rts, engine.Product) {
+		if err := fetchSnapshotProducts(ctx, client, snap); err != nil {
+			return nil, err
+		}
+	}
+	if slices.Contains(rts, engine.Customer) {
+		if err := fetchSnapshotCustomers(ctx, client, snap); err != nil {
+			return nil, err
+		}
+	}
+	return snap, nil
+}
+
+func fetchSnapshotProducts(ctx context.Context, client *api.GQLClient, snap *Snapshot) error {
+	var (
+		products = make([]schema.Product, 0)
+		ch       = make(chan *api.ProductsResponse)
+		errCh    = make(chan error, 1)
+	)
+
+	go func() {
+		defer close(ch)
+		errCh <- client.GetAllProducts(ctx, ch, snapshotBatchSize, nil, nil)
+	}()
+	for out := range ch {
+		for _, e := range out.Data.Products.Edges {
+			products = append(products, e.Node)
+		}
+	}
+	if err := <-errCh; err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		variants, err := client.GetProductVariants(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+		media, err := client.GetProductMedias(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+		snap.Products[shopctl.ExtractNumericID(product.ID)] = &SnapshotProduct{
+			Product:  product,
+			Variants: variants.Variants.Nodes,
+			Media:    media.Data.Product.Media.Nodes,
+		}
+	}
+	return nil
+}
+
+func fetchSnapshotCustomers(ctx context.Context, client *api.GQLClient, snap *Snapshot) error {
+	var (
+		ch    = make(chan *api.CustomersResponse)
+		errCh = make(chan error, 1)
+	)
+
+	go func() {
+		defer close(ch)
+		errCh <- client.GetAllCustomers(ctx, ch, snapshotBatchSize, nil, nil)
+	}()
+	for out := range ch {
+		for _, c := range out.Data.Customers.Nodes {
+			snap.Customers[shopctl.ExtractNumericID(c.ID)] = &c
+		}
+	}
+	return <-errCh
+}
@@ -0,0 +1,252 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ankitpokhrel/shopctl"
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+// ErrChecksumMismatch is returned if backup contents doesn't match its manifest.
+var ErrChecksumMismatch = fmt.Errorf("checksum mismatch")
+
+const (
+	manifestVerified = "verified"
+	manifestMissing  = "missing"
+)
+
+// parentFiles maps resource files to the file of their parent resource.
+var parentFiles = map[string]string{
+	"product.json":             "",
+	"product_variants.json":    "product.json",
+	"product_media.json":       "product.json",
+	"product_metafields.json":  "product.json",
+	"product_inventory.json":   "product.json",
+	"collection.json":          "",
+	"collection_products.json": "collection.json",
+	"customer.json":            "",
+	"customer_metafields.json": "customer.json",
+	"order.json":               "",
+	"order_line_items.json":    "order.json",
+	"order_transactions.json":  "order.json",
+	"order_fulfillments.json":  "order.json",
+	"order_metafields.json":    "order.json",
+}
+
+// VerifyReport is a result of an offline backup verification.
+type VerifyReport struct {
+	Path     string         `json:"path"`
+	OK       bool           `json:"ok"`
+	Manifest string         `json:"manifest"`
+	Counts   map[string]int `json:"counts"`
+	Invalid  []FileIssue    `json:"invalid"`
+	Orphaned []FileIssue    `json:"orphaned"`
+}
+
+// FileIssue is an issue found in a backup file.
+type FileIssue struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
+
+// Verify verifies that every resource file in the latest backup of the registry
+// decodes into the types the restore handlers use and that each child resource
+// file has its parent. Checksums are verified if the backup has a manifest.
+func (r *Registry) Verify() (*VerifyReport, error) {
+	loc := r.Path()
+	report := VerifyReport{
+		Path:     loc,
+		Counts:   make(map[string]int),
+		Invalid:  make([]FileIssue, 0),
+		Orphaned: make([]FileIssue, 0),
+	}
+
+	_, err := VerifyChecksums(loc)
+	switch {
+	case err == nil:
+		report.Manifest = manifestVerified
+	case errors.Is(err, ErrNoManifest):
+		report.Manifest = manifestMissing
+	case errors.Is(err, ErrChecksumMismatch):
+		report.Manifest = err.Error()
+	default:
+		return nil, err
+	}
+
+	found := make(map[string]bool)
+	err = WalkBackup(loc, func(name string, rdr io.Reader) error {
+		if _, ok := parentFiles[path.Base(name)]; !ok {
+			return nil
+		}
+		found[name] = true
+		report.Counts[path.Base(name)]++
+
+		content, err := io.ReadAll(rdr)
+		if err != nil {
+			return err
+		}
+		if err := decodeResourceFile(name, content); err != nil {
+			report.Invalid = append(report.Invalid, FileIssue{File: name, Error: err.Error()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range found {
+		parent := parentFiles[path.Base(name)]
+		if parent == "" {
+			continue
+		}
+		if !found[path.Join(path.Dir(name), parent)] {
+			report.Orphaned = append(report.Orphaned, FileIssue{File: name, Error: fmt.Sprintf("%s not found", parent)})
+		}
+	}
+
+	sortIssues := func(a, b FileIssue) int { return strings.Compare(a.File, b.File) }
+	slices.SortFunc(report.Invalid, sortIssues)
+	slices.SortFunc(report.Orphaned, sortIssues)
+
+	report.OK = len(report.Invalid) == 0 &&
+		len(report.Orphaned) == 0 &&
+		!strings.HasPrefix(report.Manifest, ErrChecksumMismatch.Error())
+
+	return &report, nil
+}
+
+func decodeResourceFile(name string, content []byte) error {
+	var (
+		id  string
+		err error
+	)
+
+	switch path.Base(name) {
+	case "product.json":
+		var product schema.Product
+		err = json.Unmarshal(content, &product)
+		id = product.ID
+	case "product_variants.json":
+		var variants api.ProductVariantData
+		err = json.Unmarshal(content, &variants)
+	case "product_media.json":
+		var media api.ProductMediaData
+		err = json.Unmarshal(content, &media)
+	case "product_metafields.json":
+		var metafields api.ProductMetafieldsData
+		err = json.Unmarshal(content, &metafields)
+	case "product_inventory.json":
+		var inventory api.ProductInventoryData
+		err = json.Unmarshal(content, &inventory)
+	case "collection.json":
+		var collection schema.Collection
+		err = json.Unmarshal(content, &collection)
+		id = collection.ID
+	case "collection_products.json":
+		var products api.CollectionProductsData
+		err = json.Unmarshal(content, &products)
+	case "customer.json":
+		var customer schema.Customer
+		err = json.Unmarshal(content, &customer)
+		id = customer.ID
+	case "customer_metafields.json":
+		var metafields api.CustomerMetafieldsData
+		err = json.Unmarshal(content, &metafields)
+	case "order.json":
+		var order schema.Order
+		err = json.Unmarshal(content, &order)
+		id = order.ID
+	case "order_line_items.json":
+		var lineItems api.OrderLineItemsData
+		err = json.Unmarshal(content, &lineItems)
+	case "order_transactions.json":
+		var transactions api.OrderTransactionsData
+		err = json.Unmarshal(content, &transactions)
+	case "order_fulfillments.json":
+		var fulfillments api.OrderFulfillmentsData
+		err = json.Unmarshal(content, &fulfillments)
+	case "order_metafields.json":
+		var metafields api.OrderMetafieldsData
+		err = json.Unmarshal(content, &metafields)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Parent resources are restored by their ID so make sure it exists and matches the dir.
+	if parentFiles[path.Base(name)] == "" {
+		if id == "" {
+			return fmt.Errorf("resource id is missing")
+		}
+		if dir := path.Base(path.Dir(name)); shopctl.ExtractNumericID(id) != dir {
+			return fmt.Errorf("resource id %s doesn't match the directory %s", id, dir)
+		}
+	}
+	return nil
+}
+
+// VerifyChecksums verifies files in a backup directory or a .tar.gz
+// file against the checksums recorded in its manifest.
+func VerifyChecksums(path string) (*engine.Manifest, error) {
+	m, err := ReadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var sums map[string]string
+	if strings.HasSuffix(path, ".tar.gz") {
+		sums, err = checksumsFromZip(path)
+	} else {
+		sums, err = engine.Checksums(path)
+	}
+	if err != nil {
+		return m, err
+	}
+
+	mismatched := make([]string, 0)
+	for file, sum := range m.Checksums {
+		if got, ok := sums[file]; !ok {
+			mismatched = append(mismatched, fmt.Sprintf("%s (missing)", file))
+		} else if got != sum {
+			mismatched = append(mismatched, fmt.Sprintf("%s (modified)", file))
+		}
+	}
+	for file := range sums {
+		if _, ok := m.Checksums[file]; !ok {
+			mismatched = append(mismatched, fmt.Sprintf("%s (unexpected)", file))
+		}
+	}
+	if len(mismatched) > 0 {
+		slices.Sort(mismatched)
+		return m, fmt.Errorf("%w: %s", ErrChecksumMismatch, strings.Join(mismatched, ", "))
+	}
+	return m, nil
+}
+
+func checksumsFromZip(zipPath string) (map[string]string, error) {
+	sums := make(map[string]string)
+
+	err := WalkBackup(zipPath, func(name string, r io.Reader) error {
+		if name == engine.ManifestFile {
+			return nil
+		}
+		sum, err := engine.Checksum(r)
+		if err != nil {
+			return err
+		}
+		sums[name] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sums, nil
+}
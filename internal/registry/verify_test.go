@@ -0,0 +1,120 @@
+package registry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ankitpokhrel/shopctl/internal/cmdutil"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+)
+
+type rawHandler struct {
+	data map[string]any
+}
+
+func (h rawHandler) Handle(context.Context, any) (any, error) {
+	return h.data, nil
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	path := "./testdata/.tmp"
+
+	bkpEng := engine.NewBackup("teststore.example.com", engine.WithBackupRoot(path), engine.WithBackupDir("bkp"))
+	for _, id := range []string{"1", "2"} {
+		_, err := bkpEng.Do(context.Background(), engine.NewResource(
+			engine.Product,
+			filepath.Join("products", id),
+			rawHandler{data: map[string]any{"id": "gid://shopify/Product/" + id}},
+		), nil)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, bkpEng.WriteManifest())
+	assert.NoError(t, cmdutil.Archive(bkpEng.Root(), path, "bkp"))
+
+	m, err := VerifyChecksums(bkpEng.Root())
+	assert.NoError(t, err)
+	assert.Len(t, m.Checksums, 2)
+
+	m, err = VerifyChecksums(filepath.Join(path, "bkp.tar.gz"))
+	assert.NoError(t, err)
+	assert.Len(t, m.Checksums, 2)
+
+	// Modified and unexpected files.
+	assert.NoError(t, os.WriteFile(filepath.Join(bkpEng.Root(), "products", "1", "product.json"), []byte(`{}`), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(bkpEng.Root(), "products", "2", "extra.json"), []byte(`{}`), 0o644))
+
+	_, err = VerifyChecksums(bkpEng.Root())
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.ErrorContains(t, err, "products/1/product.json (modified), products/2/extra.json (unexpected)")
+
+	// Missing manifest.
+	assert.NoError(t, os.Remove(filepath.Join(bkpEng.Root(), engine.ManifestFile)))
+	_, err = VerifyChecksums(bkpEng.Root())
+	assert.ErrorIs(t, err, ErrNoManifest)
+
+	// Clean up.
+	assert.NoError(t, os.RemoveAll(path))
+}
+
+func TestRegistry_Verify(t *testing.T) {
+	path := "./testdata/.tmp"
+
+	files := map[string]string{
+		"products/1/product.json":                `{"id":"gid://shopify/Product/1"}`,
+		"products/1/product_variants.json":       `{"id":"gid://shopify/Product/1","variants":{"nodes":[]}}`,
+		"products/2/product_media.json":          `{"id":"gid://shopify/Product/2"}`,
+		"products/1/product_inventory.json":      `{"id":"gid://shopify/Product/1","variants":{"nodes":[]}}`,
+		"products/3/product.json":                `{"id":"gid://shopify/Product/4"}`,
+		"customers/1/customer.json":              `{"id":"gid://shopify/Customer/1"}`,
+		"customers/1/customer_metafields.json":   `{"id": 1}`,
+		"collections/1/collection.json":          `{"id":"gid://shopify/Collection/1","ruleSet":{"appliedDisjunctively":false,"rules":[{"column":"TAG","relation":"EQUALS","condition":"sale"}]}}`,
+		"collections/2/collection_products.json": `{"id":"gid://shopify/Collection/2","products":{"nodes":[{"id":"gid://shopify/Product/1","handle":"shirt"}]}}`,
+		"orders/1/order.json":                    `{"id":"gid://shopify/Order/1","totalPriceSet":{"shopMoney":{"amount":"10.50","currencyCode":"EUR"}}}`,
+		"orders/1/order_line_items.json":         `{"id":"gid://shopify/Order/1","lineItems":{"nodes":[{"id":"gid://shopify/LineItem/1","quantity":1}]}}`,
+		"orders/1/order_transactions.json":       `{"id":"gid://shopify/Order/1","transactions":[]}`,
+		"orders/2/order_fulfillments.json":       `{"id":"gid://shopify/Order/2","fulfillments":[]}`,
+	}
+	for name, content := range files {
+		loc := filepath.Join(path, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(loc), 0o755))
+		assert.NoError(t, os.WriteFile(loc, []byte(content), 0o644))
+	}
+
+	reg, err := NewRegistry(path)
+	assert.NoError(t, err)
+
+	report, err := reg.Verify()
+	assert.NoError(t, err)
+	assert.False(t, report.OK)
+	assert.Equal(t, "missing", report.Manifest)
+	assert.Equal(t, map[string]int{
+		"product.json":             2,
+		"product_variants.json":    1,
+		"product_media.json":       1,
+		"product_inventory.json":   1,
+		"collection.json":          1,
+		"collection_products.json": 1,
+		"customer.json":            1,
+		"customer_metafields.json": 1,
+		"order.json":               1,
+		"order_line_items.json":    1,
+		"order_transactions.json":  1,
+		"order_fulfillments.json":  1,
+	}, report.Counts)
+	assert.Equal(t, []FileIssue{
+		{File: "customers/1/customer_metafields.json", Error: "json: cannot unmarshal number into Go struct field CustomerMetafieldsData.id of type string"},
+		{File: "products/3/product.json", Error: "resource id gid://shopify/Product/4 doesn't match the directory 3"},
+	}, report.Invalid)
+	assert.Equal(t, []FileIssue{
+		{File: "collections/2/collection_products.json", Error: "collection.json not found"},
+		{File: "orders/2/order_fulfillments.json", Error: "order.json not found"},
+		{File: "products/2/product_media.json", Error: "product.json not found"},
+	}, report.Orphaned)
+
+	// Clean up.
+	assert.NoError(t, os.RemoveAll(path))
+}
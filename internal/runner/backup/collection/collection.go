@@ -0,0 +1,143 @@
+package collection
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/ankitpokhrel/shopctl"
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/internal/runner/backup/collection/provider"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+)
+
+const batchSize = 250
+
+// Runner is a collection backup runner.
+type Runner struct {
+	eng    *engine.Engine
+	bkpEng *engine.Backup
+	client *api.GQLClient
+	filter *string
+	logger *tlog.Logger
+	stats  map[engine.ResourceType]*runner.Summary
+	latest time.Time
+}
+
+// NewRunner constructs a new backup runner.
+func NewRunner(eng *engine.Engine, client *api.GQLClient, filter string, logger *tlog.Logger) *Runner {
+	bkpEng := eng.Doer().(*engine.Backup)
+
+	var f *string
+	if filter != "" {
+		f = &filter
+	}
+
+	stats := make(map[engine.ResourceType]*runner.Summary)
+	for _, rt := range engine.GetCollectionResourceTypes() {
+		stats[rt] = &runner.Summary{}
+	}
+
+	return &Runner{
+		eng:    eng,
+		bkpEng: bkpEng,
+		client: client,
+		filter: f,
+		logger: logger,
+		stats:  stats,
+	}
+}
+
+// Kind returns runner type; implements `runner.Runner` interface.
+func (r *Runner) Kind() engine.ResourceType {
+	return engine.Collection
+}
+
+// Stats returns runner stats.
+func (r *Runner) Stats() map[engine.ResourceType]*runner.Summary {
+	return r.stats
+}
+
+// Run executes collection backup; implements `runner.Runner` interface.
+func (r *Runner) Run(ctx context.Context) error {
+	r.eng.Register(engine.Collection)
+	backupStart := time.Now()
+
+	go func() {
+		defer r.eng.Done(engine.Collection)
+		r.backup(ctx, batchSize, r.bkpEng.Cursor(engine.Collection), runner.DeltaQuery(r.filter, r.bkpEng.Since(engine.Collection)))
+	}()
+
+	for res := range r.eng.Run(ctx, engine.Collection) {
+		if res.Err != nil {
+			r.stats[res.ResourceType].Failed += 1
+			r.logger.Errorf("Failed to export resource %s: %v\n", res.ResourceType, res.Err)
+		} else if res.ResourceType == engine.Collection {
+			r.stats[res.ResourceType].Passed += 1
+		}
+	}
+
+	runner.Watermark(r.bkpEng, engine.Collection, r.latest, r.stats, r.logger)
+
+	r.logger.V(tlog.VL3).Infof(
+		"Collection export complete in %s",
+		time.Since(backupStart),
+	)
+	return nil
+}
+
+func (r *Runner) backup(ctx context.Context, limit int, after *string, query *string) {
+	collectionsCh := make(chan *api.CollectionsResponse, batchSize)
+
+	go func() {
+		defer close(collectionsCh)
+
+		if err := r.client.GetAllCollections(ctx, collectionsCh, limit, after, query); err != nil {
+			r.logger.Error("Failed to fetch collections", "limit", limit, "after", after, "error", err)
+		}
+	}()
+
+	for collections := range collectionsCh {
+		r.stats[r.Kind()].Count += len(collections.Data.Collections.Nodes)
+
+		pending := make([]string, 0, len(collections.Data.Collections.Nodes))
+		for _, collection := range collections.Data.Collections.Nodes {
+			if cid := shopctl.ExtractNumericID(collection.ID); !r.bkpEng.IsWritten(engine.Collection, cid) {
+				pending = append(pending, cid)
+			}
+		}
+		r.bkpEng.Track(engine.Collection, collections.Data.Collections.PageInfo.EndCursor, pending)
+
+		for _, collection := range collections.Data.Collections.Nodes {
+			cid := shopctl.ExtractNumericID(collection.ID)
+			r.latest = runner.LatestTime(r.latest, collection.UpdatedAt)
+
+			if r.bkpEng.IsWritten(engine.Collection, cid) {
+				r.stats[r.Kind()].Skipped += 1
+				r.logger.V(tlog.VL2).Infof("Collection %s: already exported in the interrupted run, skipping", cid)
+				continue
+			}
+
+			path := filepath.Join(engine.Collection.RootDir(), cid)
+			r.logger.V(tlog.VL2).Infof("Collection %s: registering export to path %s/%s", cid, r.bkpEng.Dir(), path)
+
+			collectionFn := &provider.Collection{Collection: &collection}
+			parent := engine.NewResource(engine.Collection, path, collectionFn)
+
+			// Products of the smart collections are derived from
+			// its rules, so only custom collections keep their products.
+			var children []engine.Resource
+			if collection.RuleSet == nil {
+				productFn := &provider.Product{Client: r.client, Logger: r.logger, CollectionID: collection.ID}
+				children = append(children, engine.NewResource(engine.CollectionProduct, path, productFn))
+			}
+
+			r.eng.Add(engine.Collection, engine.ResourceCollection{
+				Parent:   &parent,
+				Children: children,
+			})
+		}
+	}
+}
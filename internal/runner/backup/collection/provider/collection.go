@@ -0,0 +1,15 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+type Collection struct {
+	Collection *schema.Collection
+}
+
+func (c *Collection) Handle(_ context.Context, _ any) (any, error) {
+	return c.Collection, nil
+}
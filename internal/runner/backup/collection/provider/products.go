@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+)
+
+type Product struct {
+	Client       *api.GQLClient
+	Logger       *tlog.Logger
+	CollectionID string
+}
+
+func (p *Product) Handle(ctx context.Context, _ any) (any, error) {
+	p.Logger.Infof("Collection %s: processing products", p.CollectionID)
+
+	products, err := p.Client.GetCollectionProducts(ctx, p.CollectionID)
+	if err != nil {
+		p.Logger.Error("Error when fetching collection products", "collectionID", p.CollectionID, "error", err)
+		return nil, err
+	}
+	return products, nil
+}
@@ -1,7 +1,11 @@
 package customer
 
 import (
+	"context"
+	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/ankitpokhrel/shopctl"
@@ -9,24 +13,63 @@ import (
 	"github.com/ankitpokhrel/shopctl/internal/engine"
 	"github.com/ankitpokhrel/shopctl/internal/runner"
 	"github.com/ankitpokhrel/shopctl/internal/runner/backup/customer/provider"
+	"github.com/ankitpokhrel/shopctl/pkg/search"
 	"github.com/ankitpokhrel/shopctl/pkg/tlog"
 )
 
 const batchSize = 250
 
+// SearchFields are the fields customers can be filtered by.
+// See https://shopify.dev/docs/api/admin-graphql/latest/queries/customers
+var SearchFields = []string{
+	"accepts_marketing",
+	"country",
+	"customer_date",
+	"email",
+	"first_name",
+	"id",
+	"last_abandoned_order_date",
+	"last_name",
+	"order_date",
+	"orders_count",
+	"phone",
+	"state",
+	"tag",
+	"tag_not",
+	"total_spent",
+	"updated_at",
+}
+
+// ValidateQuery checks that the query filters customers by the supported search fields only.
+func ValidateQuery(query string) error {
+	for _, f := range search.Fields(query) {
+		if !slices.Contains(SearchFields, f) {
+			return fmt.Errorf("unsupported customer search field %q, supported fields are: %s", f, strings.Join(SearchFields, ", "))
+		}
+	}
+	return nil
+}
+
 // Runner is a customer backup runner.
 type Runner struct {
 	eng    *engine.Engine
 	bkpEng *engine.Backup
 	client *api.GQLClient
+	filter *string
 	logger *tlog.Logger
 	stats  map[engine.ResourceType]*runner.Summary
+	latest time.Time
 }
 
 // NewRunner constructs a new backup runner.
-func NewRunner(eng *engine.Engine, client *api.GQLClient, logger *tlog.Logger) *Runner {
+func NewRunner(eng *engine.Engine, client *api.GQLClient, filter string, logger *tlog.Logger) *Runner {
 	bkpEng := eng.Doer().(*engine.Backup)
 
+	var f *string
+	if filter != "" {
+		f = &filter
+	}
+
 	stats := make(map[engine.ResourceType]*runner.Summary)
 	for _, rt := range engine.GetCustomerResourceTypes() {
 		stats[rt] = &runner.Summary{}
@@ -36,6 +79,7 @@ func NewRunner(eng *engine.Engine, client *api.GQLClient, logger *tlog.Logger) *
 		eng:    eng,
 		bkpEng: bkpEng,
 		client: client,
+		filter: f,
 		logger: logger,
 		stats:  stats,
 	}
@@ -52,16 +96,16 @@ func (r *Runner) Stats() map[engine.ResourceType]*runner.Summary {
 }
 
 // Run executes customer backup; implements `runner.Runner` interface.
-func (r *Runner) Run() error {
+func (r *Runner) Run(ctx context.Context) error {
 	r.eng.Register(engine.Customer)
 	backupStart := time.Now()
 
 	go func() {
 		defer r.eng.Done(engine.Customer)
-		r.backup(batchSize, nil)
+		r.backup(ctx, batchSize, r.bkpEng.Cursor(engine.Customer), runner.DeltaQuery(r.filter, r.bkpEng.Since(engine.Customer)))
 	}()
 
-	for res := range r.eng.Run(engine.Customer) {
+	for res := range r.eng.Run(ctx, engine.Customer) {
 		if res.Err != nil {
 			r.stats[res.ResourceType].Failed += 1
 			r.logger.Errorf("Failed to export resource %s: %v\n", res.ResourceType, res.Err)
@@ -70,6 +114,8 @@ func (r *Runner) Run() error {
 		}
 	}
 
+	runner.Watermark(r.bkpEng, engine.Customer, r.latest, r.stats, r.logger)
+
 	r.logger.V(tlog.VL3).Infof(
 		"Customer export complete in %s",
 		time.Since(backupStart),
@@ -77,13 +123,13 @@ func (r *Runner) Run() error {
 	return nil
 }
 
-func (r *Runner) backup(limit int, after *string) {
+func (r *Runner) backup(ctx context.Context, limit int, after *string, query *string) {
 	customersCh := make(chan *api.CustomersResponse, batchSize)
 
 	go func() {
 		defer close(customersCh)
 
-		if err := r.client.GetAllCustomers(customersCh, limit, after); err != nil {
+		if err := r.client.GetAllCustomers(ctx, customersCh, limit, after, query); err != nil {
 			r.logger.Error("error when fetching customres", "limit", limit, "after", after, "error", err)
 		}
 	}()
@@ -91,8 +137,23 @@ func (r *Runner) backup(limit int, after *string) {
 	for customers := range customersCh {
 		r.stats[r.Kind()].Count += len(customers.Data.Customers.Nodes)
 
+		pending := make([]string, 0, len(customers.Data.Customers.Nodes))
+		for _, customer := range customers.Data.Customers.Nodes {
+			if cid := shopctl.ExtractNumericID(customer.ID); !r.bkpEng.IsWritten(engine.Customer, cid) {
+				pending = append(pending, cid)
+			}
+		}
+		r.bkpEng.Track(engine.Customer, customers.Data.Customers.PageInfo.EndCursor, pending)
+
 		for _, customer := range customers.Data.Customers.Nodes {
 			cid := shopctl.ExtractNumericID(customer.ID)
+			r.latest = runner.LatestTime(r.latest, customer.UpdatedAt)
+
+			if r.bkpEng.IsWritten(engine.Customer, cid) {
+				r.stats[r.Kind()].Skipped += 1
+				r.logger.V(tlog.VL2).Infof("Customer %s: already exported in the interrupted run, skipping", cid)
+				continue
+			}
 
 			path := filepath.Join(engine.Customer.RootDir(), cid)
 			r.logger.V(tlog.VL2).Infof("Customer %s: registering export to path %s/%s", cid, r.bkpEng.Dir(), path)
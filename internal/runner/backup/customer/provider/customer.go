@@ -1,11 +1,15 @@
 package provider
 
-import "github.com/ankitpokhrel/shopctl/schema"
+import (
+	"context"
+
+	"github.com/ankitpokhrel/shopctl/schema"
+)
 
 type Customer struct {
 	Customer *schema.Customer
 }
 
-func (c *Customer) Handle(_ any) (any, error) {
+func (c *Customer) Handle(_ context.Context, _ any) (any, error) {
 	return c.Customer, nil
 }
@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"context"
+
 	"github.com/ankitpokhrel/shopctl/internal/api"
 	"github.com/ankitpokhrel/shopctl/pkg/tlog"
 )
@@ -11,10 +13,10 @@ type MetaField struct {
 	CustomerID string
 }
 
-func (m *MetaField) Handle(_ any) (any, error) {
+func (m *MetaField) Handle(ctx context.Context, _ any) (any, error) {
 	m.Logger.Infof("Customer %s: processing meta fields", m.CustomerID)
 
-	metafields, err := m.Client.GetCustomerMetaFields(m.CustomerID)
+	metafields, err := m.Client.GetCustomerMetaFields(ctx, m.CustomerID)
 	if err != nil {
 		m.Logger.Error("Error when fetching metafield", "customerID", m.CustomerID, "error", err)
 		return nil, err
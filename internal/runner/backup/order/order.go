@@ -0,0 +1,145 @@
+package order
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/ankitpokhrel/shopctl"
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/internal/runner/backup/order/provider"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+)
+
+const batchSize = 250
+
+// Runner is a order backup runner.
+type Runner struct {
+	eng    *engine.Engine
+	bkpEng *engine.Backup
+	client *api.GQLClient
+	filter *string
+	logger *tlog.Logger
+	stats  map[engine.ResourceType]*runner.Summary
+	latest time.Time
+}
+
+// NewRunner constructs a new backup runner.
+func NewRunner(eng *engine.Engine, client *api.GQLClient, filter string, logger *tlog.Logger) *Runner {
+	bkpEng := eng.Doer().(*engine.Backup)
+
+	var f *string
+	if filter != "" {
+		f = &filter
+	}
+
+	stats := make(map[engine.ResourceType]*runner.Summary)
+	for _, rt := range engine.GetOrderResourceTypes() {
+		stats[rt] = &runner.Summary{}
+	}
+
+	return &Runner{
+		eng:    eng,
+		bkpEng: bkpEng,
+		client: client,
+		filter: f,
+		logger: logger,
+		stats:  stats,
+	}
+}
+
+// Kind returns runner type; implements `runner.Runner` interface.
+func (r *Runner) Kind() engine.ResourceType {
+	return engine.Order
+}
+
+// Stats returns runner stats.
+func (r *Runner) Stats() map[engine.ResourceType]*runner.Summary {
+	return r.stats
+}
+
+// Run executes order backup; implements `runner.Runner` interface.
+func (r *Runner) Run(ctx context.Context) error {
+	r.eng.Register(engine.Order)
+	backupStart := time.Now()
+
+	go func() {
+		defer r.eng.Done(engine.Order)
+		r.backup(ctx, batchSize, r.bkpEng.Cursor(engine.Order), runner.DeltaQuery(r.filter, r.bkpEng.Since(engine.Order)))
+	}()
+
+	for res := range r.eng.Run(ctx, engine.Order) {
+		if res.Err != nil {
+			r.stats[res.ResourceType].Failed += 1
+			r.logger.Errorf("Failed to export resource %s: %v\n", res.ResourceType, res.Err)
+		} else if res.ResourceType == engine.Order {
+			r.stats[res.ResourceType].Passed += 1
+		}
+	}
+
+	runner.Watermark(r.bkpEng, engine.Order, r.latest, r.stats, r.logger)
+
+	r.logger.V(tlog.VL3).Infof(
+		"Order export complete in %s",
+		time.Since(backupStart),
+	)
+	return nil
+}
+
+func (r *Runner) backup(ctx context.Context, limit int, after *string, query *string) {
+	ordersCh := make(chan *api.OrdersResponse, batchSize)
+
+	go func() {
+		defer close(ordersCh)
+
+		if err := r.client.GetAllOrders(ctx, ordersCh, limit, after, query); err != nil {
+			r.logger.Error("Failed to fetch orders", "limit", limit, "after", after, "error", err)
+		}
+	}()
+
+	for orders := range ordersCh {
+		r.stats[r.Kind()].Count += len(orders.Data.Orders.Nodes)
+
+		pending := make([]string, 0, len(orders.Data.Orders.Nodes))
+		for _, order := range orders.Data.Orders.Nodes {
+			if oid := shopctl.ExtractNumericID(order.ID); !r.bkpEng.IsWritten(engine.Order, oid) {
+				pending = append(pending, oid)
+			}
+		}
+		r.bkpEng.Track(engine.Order, orders.Data.Orders.PageInfo.EndCursor, pending)
+
+		for _, order := range orders.Data.Orders.Nodes {
+			oid := shopctl.ExtractNumericID(order.ID)
+			r.latest = runner.LatestTime(r.latest, order.UpdatedAt)
+
+			if r.bkpEng.IsWritten(engine.Order, oid) {
+				r.stats[r.Kind()].Skipped += 1
+				r.logger.V(tlog.VL2).Infof("Order %s: already exported in the interrupted run, skipping", oid)
+				continue
+			}
+
+			path := filepath.Join(engine.Order.RootDir(), oid)
+			r.logger.V(tlog.VL2).Infof("Order %s: registering export to path %s/%s", oid, r.bkpEng.Dir(), path)
+
+			orderFn := &provider.Order{Order: &order}
+			lineItemFn := &provider.LineItem{Client: r.client, Logger: r.logger, OrderID: order.ID}
+			transactionFn := &provider.Transaction{Client: r.client, Logger: r.logger, OrderID: order.ID}
+			fulfillmentFn := &provider.Fulfillment{Client: r.client, Logger: r.logger, OrderID: order.ID}
+			metafieldFn := &provider.MetaField{Client: r.client, Logger: r.logger, OrderID: order.ID}
+
+			parent := engine.NewResource(engine.Order, path, orderFn)
+
+			r.eng.Add(engine.Order, engine.ResourceCollection{
+				Parent: &parent,
+				Children: []engine.Resource{
+					engine.NewResource(engine.OrderLineItem, path, lineItemFn),
+					engine.NewResource(engine.OrderTransaction, path, transactionFn),
+					engine.NewResource(engine.OrderFulfillment, path, fulfillmentFn),
+					engine.NewResource(engine.OrderMetaField, path, metafieldFn),
+				},
+			})
+		}
+	}
+}
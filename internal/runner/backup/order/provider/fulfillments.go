@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+)
+
+type Fulfillment struct {
+	Client  *api.GQLClient
+	Logger  *tlog.Logger
+	OrderID string
+}
+
+func (f *Fulfillment) Handle(ctx context.Context, _ any) (any, error) {
+	f.Logger.Infof("Order %s: processing fulfillments", f.OrderID)
+
+	fulfillments, err := f.Client.GetOrderFulfillments(ctx, f.OrderID)
+	if err != nil {
+		f.Logger.Error("Error when fetching fulfillments", "orderID", f.OrderID, "error", err)
+		return nil, err
+	}
+	return fulfillments.Data.Order, nil
+}
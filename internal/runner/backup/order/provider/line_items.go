@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+)
+
+type LineItem struct {
+	Client  *api.GQLClient
+	Logger  *tlog.Logger
+	OrderID string
+}
+
+func (l *LineItem) Handle(ctx context.Context, _ any) (any, error) {
+	l.Logger.Infof("Order %s: processing line items", l.OrderID)
+
+	lineItems, err := l.Client.GetOrderLineItems(ctx, l.OrderID)
+	if err != nil {
+		l.Logger.Error("Error when fetching line items", "orderID", l.OrderID, "error", err)
+		return nil, err
+	}
+	return lineItems.Data.Order, nil
+}
@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+)
+
+type MetaField struct {
+	Client  *api.GQLClient
+	Logger  *tlog.Logger
+	OrderID string
+}
+
+func (m *MetaField) Handle(ctx context.Context, _ any) (any, error) {
+	m.Logger.Infof("Order %s: processing meta fields", m.OrderID)
+
+	metafields, err := m.Client.GetOrderMetaFields(ctx, m.OrderID)
+	if err != nil {
+		m.Logger.Error("Error when fetching metafield", "orderID", m.OrderID, "error", err)
+		return nil, err
+	}
+	return metafields.Data.Order, nil
+}
@@ -0,0 +1,15 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+type Order struct {
+	Order *schema.Order
+}
+
+func (o *Order) Handle(_ context.Context, _ any) (any, error) {
+	return o.Order, nil
+}
@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+)
+
+type Transaction struct {
+	Client  *api.GQLClient
+	Logger  *tlog.Logger
+	OrderID string
+}
+
+func (t *Transaction) Handle(ctx context.Context, _ any) (any, error) {
+	t.Logger.Infof("Order %s: processing transactions", t.OrderID)
+
+	transactions, err := t.Client.GetOrderTransactions(ctx, t.OrderID)
+	if err != nil {
+		t.Logger.Error("Error when fetching transactions", "orderID", t.OrderID, "error", err)
+		return nil, err
+	}
+	return transactions.Data.Order, nil
+}
@@ -0,0 +1,292 @@
+package product
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ankitpokhrel/shopctl"
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/internal/runner/backup/product/provider"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+
+	// Lines of a bulk operation result can be larger than the default buffer of the scanner.
+	maxBulkLineSize = 10 * 1024 * 1024
+)
+
+// BulkRunner is a product backup runner that fetches products and their
+// nested resources with a single bulk operation instead of paginating.
+type BulkRunner struct {
+	*Runner
+	pollInterval time.Duration
+}
+
+// BulkOption is a functional opt for BulkRunner.
+type BulkOption func(*BulkRunner)
+
+// WithPollInterval sets the interval to poll the status of the bulk operation with.
+func WithPollInterval(d time.Duration) BulkOption {
+	return func(r *BulkRunner) {
+		r.pollInterval = d
+	}
+}
+
+// WithOptions applies the options of the product backup runner to the bulk runner.
+func WithOptions(opts ...Option) BulkOption {
+	return func(r *BulkRunner) {
+		for _, opt := range opts {
+			opt(r.Runner)
+		}
+	}
+}
+
+// NewBulkRunner constructs a new bulk backup runner.
+func NewBulkRunner(eng *engine.Engine, client *api.GQLClient, filter string, logger *tlog.Logger, opts ...BulkOption) *BulkRunner {
+	rnr := BulkRunner{
+		Runner:       NewRunner(eng, client, filter, logger),
+		pollInterval: defaultPollInterval,
+	}
+
+	for _, opt := range opts {
+		opt(&rnr)
+	}
+	return &rnr
+}
+
+// Run executes product backup; implements `runner.Runner` interface.
+func (r *BulkRunner) Run(ctx context.Context) error {
+	return r.run(ctx, func() {
+		if err := r.backup(ctx, runner.DeltaQuery(r.filter, r.bkpEng.Since(engine.Product))); err != nil {
+			r.logger.Error("Failed to fetch products in bulk", "error", err)
+		}
+	})
+}
+
+func (r *BulkRunner) backup(ctx context.Context, query *string) error {
+	op, err := r.client.RunBulkQuery(ctx, api.ProductsBulkQuery(query))
+	if err != nil {
+		return err
+	}
+	r.logger.V(tlog.VL1).Infof("Bulk operation %s was submitted", op.ID)
+
+	if op, err = runner.WaitBulkOperation(ctx, r.client, op.ID, r.pollInterval, r.logger); err != nil {
+		return err
+	}
+	// URL is empty if the operation didn't find any records.
+	if op.URL == nil {
+		return nil
+	}
+
+	res, err := r.client.DownloadBulkResult(ctx, *op.URL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Close() }()
+
+	return parseBulkProducts(res, r.add)
+}
+
+// add registers the export of the product read from the bulk operation result.
+func (r *BulkRunner) add(p *bulkProduct) {
+	pid := shopctl.ExtractNumericID(p.product.ID)
+
+	r.stats[r.Kind()].Count += 1
+	r.latest = runner.LatestTime(r.latest, p.product.UpdatedAt)
+
+	if r.bkpEng.IsWritten(engine.Product, pid) {
+		r.stats[r.Kind()].Skipped += 1
+		r.logger.V(tlog.VL2).Infof("Product %s: already exported in the interrupted run, skipping", pid)
+		return
+	}
+	r.bkpEng.Track(engine.Product, nil, []string{pid})
+
+	path := filepath.Join(engine.Product.RootDir(), pid)
+	r.logger.V(tlog.VL2).Infof("Product %s: registering export to path %s/%s", pid, r.bkpEng.Dir(), path)
+
+	parent := engine.NewResource(engine.Product, path, &provider.Product{Product: &p.product})
+
+	r.eng.Add(engine.Product, engine.ResourceCollection{
+		Parent: &parent,
+		Children: []engine.Resource{
+			engine.NewResource(engine.ProductVariant, path, &provider.Prefetched{Data: p.variants}),
+			engine.NewResource(engine.ProductMedia, path, r.mediaProvider(path, p.media)),
+			engine.NewResource(engine.ProductMetaField, path, &provider.Prefetched{Data: p.metafields}),
+			engine.NewResource(engine.ProductInventory, path, &provider.Prefetched{Data: p.inventory}),
+		},
+	})
+}
+
+// mediaProvider returns the provider of the fetched media of the product at the given path.
+func (r *BulkRunner) mediaProvider(path string, media *api.ProductMediaData) engine.ResourceHandler {
+	if !r.withMediaFiles {
+		return &provider.Prefetched{Data: media}
+	}
+	return &provider.Media{Client: r.client, Logger: r.logger, ProductID: media.ProductID, Dir: r.mediaDir(path), Data: media}
+}
+
+// bulkProduct is a product with the nested resources read from a bulk operation result.
+type bulkProduct struct {
+	product    schema.Product
+	variants   *api.ProductVariantData
+	media      *api.ProductMediaData
+	metafields *api.ProductMetafieldsData
+	inventory  *api.ProductInventoryData
+}
+
+func newBulkProduct(product schema.Product) *bulkProduct {
+	p := bulkProduct{
+		product:    product,
+		variants:   &api.ProductVariantData{ProductID: product.ID},
+		media:      &api.ProductMediaData{ProductID: product.ID},
+		metafields: &api.ProductMetafieldsData{ProductID: product.ID},
+		inventory:  &api.ProductInventoryData{ProductID: product.ID},
+	}
+	p.variants.Variants.Nodes = make([]schema.ProductVariant, 0)
+	p.media.Media.Nodes = make([]api.ProductMediaNode, 0)
+	p.metafields.Metafields.Nodes = make([]schema.Metafield, 0)
+	p.inventory.Variants.Nodes = make([]api.ProductInventoryNode, 0)
+
+	return &p
+}
+
+// parseBulkProducts reads products from the JSONL result of the bulk operation and passes
+// them to fn in the order they appear in the result. Each nested record is in its own line
+// that refers to its parent with the `__parentId` field and follows the line of its parent,
+// so only the product being read is kept in memory. It is passed to fn once the next product
+// starts or the result ends.
+//
+//nolint:gocyclo
+func parseBulkProducts(rd io.Reader, fn func(*bulkProduct)) error {
+	type line struct {
+		ID       string `json:"id"`
+		ParentID string `json:"__parentId"`
+	}
+
+	var (
+		current *bulkProduct
+		num     int
+
+		// Index of the inventory item of a variant in the inventory file of the current product.
+		inventory = make(map[string]int)
+	)
+
+	flush := func() {
+		if current != nil {
+			fn(current)
+		}
+		current, inventory = nil, make(map[string]int)
+	}
+
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxBulkLineSize)
+
+	for scanner.Scan() {
+		num++
+		raw := scanner.Bytes()
+		if len(bytes.TrimSpace(raw)) == 0 {
+			continue
+		}
+
+		var ln line
+		if err := json.Unmarshal(raw, &ln); err != nil {
+			return fmt.Errorf("line %d: %w", num, err)
+		}
+
+		if ln.ParentID == "" {
+			flush()
+
+			var product schema.Product
+			if err := json.Unmarshal(raw, &product); err != nil {
+				return fmt.Errorf("line %d: %w", num, err)
+			}
+			current = newBulkProduct(product)
+			continue
+		}
+
+		if gidType(ln.ID) == "InventoryLevel" {
+			idx, ok := inventory[ln.ParentID]
+			if !ok {
+				return fmt.Errorf("line %d: parent %s of %s not found", num, ln.ParentID, ln.ID)
+			}
+			var level api.InventoryLevelNode
+			if err := json.Unmarshal(raw, &level); err != nil {
+				return fmt.Errorf("line %d: %w", num, err)
+			}
+			item := &current.inventory.Variants.Nodes[idx].InventoryItem
+			item.InventoryLevels.Nodes = append(item.InventoryLevels.Nodes, level)
+			continue
+		}
+
+		p := current
+		if p == nil || p.product.ID != ln.ParentID {
+			return fmt.Errorf("line %d: parent %s of %s not found", num, ln.ParentID, ln.ID)
+		}
+
+		var err error
+		switch gidType(ln.ID) {
+		case "ProductVariant":
+			var (
+				variant schema.ProductVariant
+				inv     api.ProductInventoryNode
+			)
+			if err = json.Unmarshal(raw, &variant); err != nil {
+				break
+			}
+			if err = json.Unmarshal(raw, &inv); err != nil {
+				break
+			}
+			inv.InventoryItem.InventoryLevels.Nodes = make([]api.InventoryLevelNode, 0)
+
+			p.variants.Variants.Nodes = append(p.variants.Variants.Nodes, variant)
+			p.inventory.Variants.Nodes = append(p.inventory.Variants.Nodes, inv)
+
+			// Inventory levels refer to either the variant or its inventory item.
+			idx := len(p.inventory.Variants.Nodes) - 1
+			inventory[inv.ID] = idx
+			if inv.InventoryItem.ID != "" {
+				inventory[inv.InventoryItem.ID] = idx
+			}
+		case "Metafield":
+			var metafield schema.Metafield
+			if err = json.Unmarshal(raw, &metafield); err == nil {
+				p.metafields.Metafields.Nodes = append(p.metafields.Metafields.Nodes, metafield)
+			}
+		default:
+			var media api.ProductMediaNode
+			if err = json.Unmarshal(raw, &media); err == nil {
+				p.media.Media.Nodes = append(p.media.Media.Nodes, media)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %w", num, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	flush()
+
+	return nil
+}
+
+// gidType returns the type of the Shopify global ID, e.g. `ProductVariant` for `gid://shopify/ProductVariant/1`.
+func gidType(id string) string {
+	parts := strings.Split(strings.TrimPrefix(id, "gid://shopify/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0]
+}
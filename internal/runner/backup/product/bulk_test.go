@@ -0,0 +1,181 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/config"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+func TestBulkRunner_Run(t *testing.T) {
+	t.Setenv("SHOPIFY_ACCESS_TOKEN", "12345")
+
+	path := "./testdata/.tmp"
+	polls := 0
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/result.jsonl" {
+			http.ServeFile(w, r, "./testdata/bulk_products.jsonl")
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		assert.NoError(t, json.Unmarshal(body, &req))
+
+		var resp string
+		switch {
+		case strings.Contains(req.Query, "bulkOperationRunQuery"):
+			assert.Contains(t, req.Variables["query"], `products(query: "tag:premium")`)
+			assert.Contains(t, req.Variables["query"], "inventoryLevels")
+			resp = `{"data":{"bulkOperationRunQuery":{"bulkOperation":{"id":"gid://shopify/BulkOperation/1","status":"CREATED"},"userErrors":[]}}}`
+		case strings.Contains(req.Query, "GetBulkOperation"):
+			polls++
+			if polls == 1 {
+				resp = `{"data":{"node":{"id":"gid://shopify/BulkOperation/1","status":"RUNNING","objectCount":"3"}}}`
+			} else {
+				resp = `{"data":{"node":{"id":"gid://shopify/BulkOperation/1","status":"COMPLETED","objectCount":"9","url":"` + server.URL + `/result.jsonl"}}}`
+			}
+		default:
+			t.Fatalf("unexpected query: %s", req.Query)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(resp))
+	}))
+	defer server.Close()
+
+	client := api.NewGQLClient(&config.StoreContext{Store: "teststore.example.com", Alias: "test"}, api.WithServer(server.URL))
+	bkpEng := engine.NewBackup("teststore.example.com", engine.WithBackupRoot(path), engine.WithBackupDir("bulk"))
+	eng := engine.New(bkpEng)
+
+	rnr := NewBulkRunner(eng, client, "tag:premium", tlog.New(tlog.VerboseLevel(tlog.VL1), true), WithPollInterval(time.Millisecond))
+	assert.NoError(t, rnr.Run(context.Background()))
+	assert.Equal(t, 2, polls)
+
+	stats := rnr.Stats()
+	assert.Equal(t, 2, stats[engine.Product].Count)
+	assert.Equal(t, 2, stats[engine.Product].Passed)
+	assert.Equal(t, 0, stats[engine.ProductVariant].Failed)
+
+	// Files are written in the same layout as the paginated export.
+	for _, id := range []string{"1", "2"} {
+		for _, f := range []string{"product.json", "product_variants.json", "product_media.json", "product_metafields.json", "product_inventory.json"} {
+			assert.FileExists(t, filepath.Join(bkpEng.Root(), "products", id, f))
+		}
+	}
+
+	read := func(file string, v any) {
+		content, err := os.ReadFile(filepath.Join(bkpEng.Root(), "products", "1", file))
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(content, v))
+	}
+
+	var product schema.Product
+	read("product.json", &product)
+	assert.Equal(t, "shirt", product.Handle)
+
+	var variants api.ProductVariantData
+	read("product_variants.json", &variants)
+	assert.Equal(t, "gid://shopify/Product/1", variants.ProductID)
+	assert.Len(t, variants.Variants.Nodes, 2)
+	assert.Equal(t, "S", variants.Variants.Nodes[0].Title)
+
+	var media api.ProductMediaData
+	read("product_media.json", &media)
+	assert.Len(t, media.Media.Nodes, 1)
+	assert.Equal(t, "gid://shopify/MediaImage/21", media.Media.Nodes[0].ID)
+
+	var metafields api.ProductMetafieldsData
+	read("product_metafields.json", &metafields)
+	assert.Len(t, metafields.Metafields.Nodes, 1)
+	assert.Equal(t, "material", metafields.Metafields.Nodes[0].Key)
+
+	var inventory api.ProductInventoryData
+	read("product_inventory.json", &inventory)
+	assert.Len(t, inventory.Variants.Nodes, 2)
+	assert.Equal(t, "gid://shopify/InventoryItem/112", inventory.Variants.Nodes[1].InventoryItem.ID)
+	assert.Len(t, inventory.Variants.Nodes[1].InventoryItem.InventoryLevels.Nodes, 1)
+	assert.Equal(t, "Warehouse", inventory.Variants.Nodes[1].InventoryItem.InventoryLevels.Nodes[0].Location.Name)
+
+	// Clean up.
+	assert.NoError(t, os.RemoveAll(path))
+	assert.NoError(t, bkpEng.RemoveCheckpoint())
+}
+
+func TestParseBulkProducts(t *testing.T) {
+	f, err := os.Open("./testdata/bulk_products.jsonl")
+	assert.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	// Each product is passed on as soon as the next one starts, the last one at the end of the result.
+	rd := &lineCounter{r: f}
+	read := make(map[string]int)
+	products := make([]*bulkProduct, 0)
+
+	assert.NoError(t, parseBulkProducts(rd, func(p *bulkProduct) {
+		read[p.product.Handle] = rd.lines
+		products = append(products, p)
+	}))
+
+	assert.Len(t, products, 2)
+	assert.Equal(t, 8, read["shirt"])
+	assert.Equal(t, 9, read["mug"])
+
+	assert.Len(t, products[0].variants.Variants.Nodes, 2)
+	assert.Len(t, products[0].inventory.Variants.Nodes[0].InventoryItem.InventoryLevels.Nodes, 1)
+	assert.Len(t, products[0].media.Media.Nodes, 1)
+	assert.Len(t, products[0].metafields.Metafields.Nodes, 1)
+	assert.Len(t, products[1].variants.Variants.Nodes, 1)
+	assert.Empty(t, products[1].media.Media.Nodes)
+}
+
+func TestParseBulkProducts_OrphanedLine(t *testing.T) {
+	err := parseBulkProducts(strings.NewReader(`{"id":"gid://shopify/Metafield/1","__parentId":"gid://shopify/Product/9"}`), func(*bulkProduct) {})
+	assert.EqualError(t, err, "line 1: parent gid://shopify/Product/9 of gid://shopify/Metafield/1 not found")
+
+	// Nested records of a product are expected right after it.
+	err = parseBulkProducts(strings.NewReader(strings.Join([]string{
+		`{"id":"gid://shopify/Product/1"}`,
+		`{"id":"gid://shopify/Product/2"}`,
+		`{"id":"gid://shopify/Metafield/1","__parentId":"gid://shopify/Product/1"}`,
+	}, "\n")), func(*bulkProduct) {})
+	assert.EqualError(t, err, "line 3: parent gid://shopify/Product/1 of gid://shopify/Metafield/1 not found")
+}
+
+// lineCounter counts the lines read so far, one byte at a time.
+type lineCounter struct {
+	r     io.Reader
+	lines int
+}
+
+func (c *lineCounter) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n, err := c.r.Read(p[:1])
+	if n > 0 && p[0] == '\n' {
+		c.lines++
+	}
+	return n, err
+}
@@ -1,6 +1,7 @@
 package product
 
 import (
+	"context"
 	"path/filepath"
 	"time"
 
@@ -22,10 +23,23 @@ type Runner struct {
 	filter *string
 	logger *tlog.Logger
 	stats  map[engine.ResourceType]*runner.Summary
+	latest time.Time
+
+	withMediaFiles bool
+}
+
+// Option is a functional opt for Runner.
+type Option func(*Runner)
+
+// WithMediaFiles downloads the original files of the product media along with the export.
+func WithMediaFiles() Option {
+	return func(r *Runner) {
+		r.withMediaFiles = true
+	}
 }
 
 // NewRunner constructs a new backup runner.
-func NewRunner(eng *engine.Engine, client *api.GQLClient, filter string, logger *tlog.Logger) *Runner {
+func NewRunner(eng *engine.Engine, client *api.GQLClient, filter string, logger *tlog.Logger, opts ...Option) *Runner {
 	bkpEng := eng.Doer().(*engine.Backup)
 
 	var f *string
@@ -38,7 +52,7 @@ func NewRunner(eng *engine.Engine, client *api.GQLClient, filter string, logger
 		stats[rt] = &runner.Summary{}
 	}
 
-	return &Runner{
+	rnr := Runner{
 		eng:    eng,
 		bkpEng: bkpEng,
 		client: client,
@@ -46,6 +60,11 @@ func NewRunner(eng *engine.Engine, client *api.GQLClient, filter string, logger
 		logger: logger,
 		stats:  stats,
 	}
+
+	for _, opt := range opts {
+		opt(&rnr)
+	}
+	return &rnr
 }
 
 // Kind returns runner type; implements `runner.Runner` interface.
@@ -59,16 +78,23 @@ func (r *Runner) Stats() map[engine.ResourceType]*runner.Summary {
 }
 
 // Run executes product backup; implements `runner.Runner` interface.
-func (r *Runner) Run() error {
+func (r *Runner) Run(ctx context.Context) error {
+	return r.run(ctx, func() {
+		r.backup(ctx, batchSize, r.bkpEng.Cursor(engine.Product), runner.DeltaQuery(r.filter, r.bkpEng.Since(engine.Product)))
+	})
+}
+
+// run registers products fetched by the given fetcher to the engine and collects the results.
+func (r *Runner) run(ctx context.Context, fetch func()) error {
 	r.eng.Register(engine.Product)
 	backupStart := time.Now()
 
 	go func() {
 		defer r.eng.Done(engine.Product)
-		r.backup(batchSize, nil, r.filter)
+		fetch()
 	}()
 
-	for res := range r.eng.Run(engine.Product) {
+	for res := range r.eng.Run(ctx, engine.Product) {
 		if res.Err != nil {
 			r.stats[res.ResourceType].Failed += 1
 			r.logger.Errorf("Failed to export resource %s: %v\n", res.ResourceType, res.Err)
@@ -77,6 +103,8 @@ func (r *Runner) Run() error {
 		}
 	}
 
+	runner.Watermark(r.bkpEng, engine.Product, r.latest, r.stats, r.logger)
+
 	r.logger.V(tlog.VL3).Infof(
 		"Product export complete in %s",
 		time.Since(backupStart),
@@ -84,13 +112,13 @@ func (r *Runner) Run() error {
 	return nil
 }
 
-func (r *Runner) backup(limit int, after *string, query *string) {
+func (r *Runner) backup(ctx context.Context, limit int, after *string, query *string) {
 	productsCh := make(chan *api.ProductsResponse, batchSize)
 
 	go func() {
 		defer close(productsCh)
 
-		if err := r.client.GetAllProducts(productsCh, limit, after, query); err != nil {
+		if err := r.client.GetAllProducts(ctx, productsCh, limit, after, query); err != nil {
 			r.logger.Error("Failed to fetch products", "limit", limit, "after", after, "error", err)
 		}
 	}()
@@ -98,16 +126,32 @@ func (r *Runner) backup(limit int, after *string, query *string) {
 	for products := range productsCh {
 		r.stats[r.Kind()].Count += len(products.Data.Products.Edges)
 
+		pending := make([]string, 0, len(products.Data.Products.Edges))
+		for _, product := range products.Data.Products.Edges {
+			if pid := shopctl.ExtractNumericID(product.Node.ID); !r.bkpEng.IsWritten(engine.Product, pid) {
+				pending = append(pending, pid)
+			}
+		}
+		r.bkpEng.Track(engine.Product, products.Data.Products.PageInfo.EndCursor, pending)
+
 		for _, product := range products.Data.Products.Edges {
 			pid := shopctl.ExtractNumericID(product.Node.ID)
+			r.latest = runner.LatestTime(r.latest, product.Node.UpdatedAt)
+
+			if r.bkpEng.IsWritten(engine.Product, pid) {
+				r.stats[r.Kind()].Skipped += 1
+				r.logger.V(tlog.VL2).Infof("Product %s: already exported in the interrupted run, skipping", pid)
+				continue
+			}
 
 			path := filepath.Join(engine.Product.RootDir(), pid)
 			r.logger.V(tlog.VL2).Infof("Product %s: registering export to path %s/%s", pid, r.bkpEng.Dir(), path)
 
 			productFn := &provider.Product{Product: &product.Node}
 			variantFn := &provider.Variant{Client: r.client, Logger: r.logger, ProductID: product.Node.ID}
-			mediaFn := &provider.Media{Client: r.client, Logger: r.logger, ProductID: product.Node.ID}
+			mediaFn := &provider.Media{Client: r.client, Logger: r.logger, ProductID: product.Node.ID, Dir: r.mediaDir(path)}
 			metafieldFn := &provider.MetaField{Client: r.client, Logger: r.logger, ProductID: product.Node.ID}
+			inventoryFn := &provider.Inventory{Client: r.client, Logger: r.logger, ProductID: product.Node.ID}
 
 			parent := engine.NewResource(engine.Product, path, productFn)
 
@@ -117,8 +161,18 @@ func (r *Runner) backup(limit int, after *string, query *string) {
 					engine.NewResource(engine.ProductVariant, path, variantFn),
 					engine.NewResource(engine.ProductMedia, path, mediaFn),
 					engine.NewResource(engine.ProductMetaField, path, metafieldFn),
+					engine.NewResource(engine.ProductInventory, path, inventoryFn),
 				},
 			})
 		}
 	}
 }
+
+// mediaDir returns the directory to download the media files of the product at the given path to.
+// It is empty if the media files are not exported.
+func (r *Runner) mediaDir(path string) string {
+	if !r.withMediaFiles {
+		return ""
+	}
+	return filepath.Join(r.bkpEng.Root(), path, runner.MediaDir)
+}
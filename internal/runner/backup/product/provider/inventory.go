@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+)
+
+type Inventory struct {
+	Client    *api.GQLClient
+	Logger    *tlog.Logger
+	ProductID string
+}
+
+func (i *Inventory) Handle(ctx context.Context, _ any) (any, error) {
+	i.Logger.Infof("Product %s: processing inventory levels", i.ProductID)
+
+	inventory, err := i.Client.GetProductInventory(ctx, i.ProductID)
+	if err != nil {
+		i.Logger.Error("Error when fetching inventory levels", "productID", i.ProductID, "error", err)
+		return nil, err
+	}
+	return inventory, nil
+}
@@ -1,7 +1,13 @@
 package provider
 
 import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
 	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
 	"github.com/ankitpokhrel/shopctl/pkg/tlog"
 )
 
@@ -9,15 +15,87 @@ type Media struct {
 	Client    *api.GQLClient
 	Logger    *tlog.Logger
 	ProductID string
+	// Dir is the directory to download the media files to.
+	// Files are not downloaded if it is empty.
+	Dir string
+	// Data is the media fetched in advance, e.g. by a bulk operation.
+	Data *api.ProductMediaData
 }
 
-func (m *Media) Handle(_ any) (any, error) {
+func (m *Media) Handle(ctx context.Context, _ any) (any, error) {
 	m.Logger.Infof("Product %s: processing media items", m.ProductID)
 
-	medias, err := m.Client.GetProductMedias(m.ProductID)
+	data := m.Data
+	if data == nil {
+		medias, err := m.Client.GetProductMedias(ctx, m.ProductID)
+		if err != nil {
+			m.Logger.Error("Error when fetching media", "productID", m.ProductID, "error", err)
+			return nil, err
+		}
+		data = &medias.Data.Product
+	}
+
+	if m.Dir != "" {
+		if err := m.download(ctx, data.Media.Nodes); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// download downloads the original files of the media. Files that can't be downloaded
+// are skipped as the media can still be restored from their url while it is available.
+func (m *Media) download(ctx context.Context, nodes []api.ProductMediaNode) error {
+	const modeDir = 0o755
+
+	if err := os.MkdirAll(m.Dir, modeDir); err != nil {
+		return err
+	}
+
+	for _, n := range nodes {
+		name := runner.MediaFile(&n)
+		if name == "" {
+			m.Logger.V(tlog.VL2).Infof("Product %s: media %s doesn't have a file to download, skipping", m.ProductID, n.ID)
+			continue
+		}
+		dest := filepath.Join(m.Dir, name)
+		// The file may already be downloaded in the interrupted run.
+		if _, err := os.Stat(dest); err == nil {
+			continue
+		}
+
+		m.Logger.V(tlog.VL3).Infof("Product %s: downloading media file %s", m.ProductID, name)
+		if err := m.save(ctx, runner.MediaSource(&n), dest); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			m.Logger.Warn("Unable to download media file", "productID", m.ProductID, "mediaID", n.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+func (m *Media) save(ctx context.Context, url string, dest string) error {
+	res, err := m.Client.DownloadMediaFile(ctx, url)
 	if err != nil {
-		m.Logger.Error("Error when fetching media", "productID", m.ProductID, "error", err)
-		return nil, err
+		return err
+	}
+	defer func() { _ = res.Close() }()
+
+	// Write to a temp file first so that a partially downloaded file is not mistaken as complete.
+	tmp := dest + ".part"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, res); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
 	}
-	return medias.Data.Product, nil
+	return os.Rename(tmp, dest)
 }
@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"context"
+
 	"github.com/ankitpokhrel/shopctl/internal/api"
 	"github.com/ankitpokhrel/shopctl/pkg/tlog"
 )
@@ -11,10 +13,10 @@ type MetaField struct {
 	ProductID string
 }
 
-func (m *MetaField) Handle(_ any) (any, error) {
+func (m *MetaField) Handle(ctx context.Context, _ any) (any, error) {
 	m.Logger.Infof("Product %s: processing meta fields", m.ProductID)
 
-	metafields, err := m.Client.GetProductMetaFields(m.ProductID)
+	metafields, err := m.Client.GetProductMetaFields(ctx, m.ProductID)
 	if err != nil {
 		m.Logger.Error("Error when fetching metafield", "productID", m.ProductID, "error", err)
 		return nil, err
@@ -0,0 +1,12 @@
+package provider
+
+import "context"
+
+// Prefetched provides data that was already fetched, e.g. by a bulk operation.
+type Prefetched struct {
+	Data any
+}
+
+func (p *Prefetched) Handle(_ context.Context, _ any) (any, error) {
+	return p.Data, nil
+}
@@ -1,11 +1,15 @@
 package provider
 
-import "github.com/ankitpokhrel/shopctl/schema"
+import (
+	"context"
+
+	"github.com/ankitpokhrel/shopctl/schema"
+)
 
 type Product struct {
 	Product *schema.Product
 }
 
-func (p *Product) Handle(_ any) (any, error) {
+func (p *Product) Handle(_ context.Context, _ any) (any, error) {
 	return p.Product, nil
 }
@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"context"
+
 	"github.com/ankitpokhrel/shopctl/internal/api"
 	"github.com/ankitpokhrel/shopctl/pkg/tlog"
 )
@@ -11,10 +13,10 @@ type Variant struct {
 	ProductID string
 }
 
-func (v *Variant) Handle(_ any) (any, error) {
+func (v *Variant) Handle(ctx context.Context, _ any) (any, error) {
 	v.Logger.Infof("Product %s: processing variants", v.ProductID)
 
-	variants, err := v.Client.GetProductVariants(v.ProductID)
+	variants, err := v.Client.GetProductVariants(ctx, v.ProductID)
 	if err != nil {
 		v.Logger.Error("Error when fetching variants", "productId", v.ProductID, "error", err)
 		return nil, err
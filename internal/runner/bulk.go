@@ -0,0 +1,42 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+// WaitBulkOperation polls the bulk operation with the given interval until it is finished.
+// It returns an error if the operation failed, was canceled or expired, or if the context
+// is cancelled while waiting. The operation itself keeps running on the store.
+func WaitBulkOperation(ctx context.Context, client *api.GQLClient, id string, interval time.Duration, logger *tlog.Logger) (*schema.BulkOperation, error) {
+	for {
+		op, err := client.GetBulkOperation(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch op.Status {
+		case schema.BulkOperationStatusCompleted:
+			logger.V(tlog.VL1).Infof("Bulk operation %s completed with %s objects", id, op.ObjectCount)
+			return op, nil
+		case schema.BulkOperationStatusFailed, schema.BulkOperationStatusCanceled, schema.BulkOperationStatusExpired:
+			if op.ErrorCode != nil {
+				return nil, fmt.Errorf("bulk operation %s finished with status %s: %s", id, op.Status, *op.ErrorCode)
+			}
+			return nil, fmt.Errorf("bulk operation %s finished with status %s", id, op.Status)
+		}
+
+		logger.V(tlog.VL2).Infof("Bulk operation %s is %s, %s objects processed so far", id, strings.ToLower(string(op.Status)), op.ObjectCount)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
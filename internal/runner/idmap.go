@@ -0,0 +1,225 @@
+package runner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/pkg/fmtout"
+)
+
+// ID map files written at the end of a restore.
+const (
+	IDMapFileCSV  = "id_map.csv"
+	IDMapFileJSON = "id_map.json"
+)
+
+// ID map actions.
+const (
+	ActionCreated   = "created"
+	ActionUpdated   = "updated"
+	ActionSkipped   = "skipped"
+	ActionUnchanged = "unchanged"
+)
+
+var idMapColumns = []string{"Resource", "Old ID", "New ID", "Key", "Action"}
+
+// IDMapping maps a resource in the backup to the resource in the store.
+type IDMapping struct {
+	Resource engine.ResourceType `json:"resource"`
+	OldID    string              `json:"oldId"`
+	NewID    string              `json:"newId"`
+	Key      string              `json:"key"`
+	Action   string              `json:"action"`
+}
+
+// IDMap keeps track of old to new ID mapping of the restored resources.
+// A nil IDMap is valid and doesn't record or resolve anything.
+type IDMap struct {
+	mux      sync.Mutex
+	base     *IDMap
+	mappings map[engine.ResourceType]map[string]IDMapping
+}
+
+// IDMapOption is a functional opt for IDMap.
+type IDMapOption func(*IDMap)
+
+// NewIDMap constructs an empty ID map.
+func NewIDMap(opts ...IDMapOption) *IDMap {
+	m := IDMap{
+		mappings: make(map[engine.ResourceType]map[string]IDMapping),
+	}
+
+	for _, opt := range opts {
+		opt(&m)
+	}
+	return &m
+}
+
+// WithBase sets the ID map to fall back to when resolving IDs, e.g. an ID
+// map from an earlier import. Mappings of the base map are not recorded.
+func WithBase(base *IDMap) IDMapOption {
+	return func(m *IDMap) {
+		m.base = base
+	}
+}
+
+// LoadIDMap loads ID map from a .json or a .csv file.
+func LoadIDMap(path string) (*IDMap, error) {
+	ext := filepath.Ext(path)
+	if ext != ".json" && ext != ".csv" {
+		return nil, fmt.Errorf("unsupported id map file %s: expected a .json or a .csv file", path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var mappings []IDMapping
+
+	switch ext {
+	case ".json":
+		if err := json.NewDecoder(f).Decode(&mappings); err != nil {
+			return nil, fmt.Errorf("unable to parse id map %s: %w", path, err)
+		}
+	case ".csv":
+		records, err := csv.NewReader(f).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse id map %s: %w", path, err)
+		}
+		for i, rec := range records {
+			if i == 0 && slices.Equal(rec, idMapColumns) {
+				continue
+			}
+			if len(rec) != len(idMapColumns) {
+				return nil, fmt.Errorf("unable to parse id map %s: line %d: expected %d columns", path, i+1, len(idMapColumns))
+			}
+			mappings = append(mappings, IDMapping{
+				Resource: engine.ResourceType(rec[0]),
+				OldID:    rec[1],
+				NewID:    rec[2],
+				Key:      rec[3],
+				Action:   rec[4],
+			})
+		}
+	}
+
+	m := NewIDMap()
+	for _, mp := range mappings {
+		m.Add(mp)
+	}
+	return m, nil
+}
+
+// Add adds a mapping. The mapping for the same resource is replaced.
+func (m *IDMap) Add(mp IDMapping) {
+	if m == nil || mp.OldID == "" {
+		return
+	}
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if _, ok := m.mappings[mp.Resource]; !ok {
+		m.mappings[mp.Resource] = make(map[string]IDMapping)
+	}
+	m.mappings[mp.Resource][mp.OldID] = mp
+}
+
+// Merge adds all mappings from the other ID map.
+func (m *IDMap) Merge(other *IDMap) {
+	if other == nil {
+		return
+	}
+	for _, mp := range other.Mappings() {
+		m.Add(mp)
+	}
+}
+
+// Resolve returns the new ID of a resource from its old ID.
+func (m *IDMap) Resolve(rt engine.ResourceType, oldID string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+
+	m.mux.Lock()
+	mp, ok := m.mappings[rt][oldID]
+	m.mux.Unlock()
+
+	if !ok || mp.NewID == "" {
+		return m.base.Resolve(rt, oldID)
+	}
+	return mp.NewID, true
+}
+
+// Mappings returns all recorded mappings ordered by resource type and the old ID.
+func (m *IDMap) Mappings() []IDMapping {
+	if m == nil {
+		return nil
+	}
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	out := make([]IDMapping, 0)
+	for _, rt := range engine.GetAllResourceTypes() {
+		ids := slices.Sorted(maps.Keys(m.mappings[rt]))
+		for _, id := range ids {
+			out = append(out, m.mappings[rt][id])
+		}
+	}
+	return out
+}
+
+// Len returns the number of mappings.
+func (m *IDMap) Len() int {
+	return len(m.Mappings())
+}
+
+// Save writes the ID map as both csv and json files to the given dir.
+func (m *IDMap) Save(dir string) error {
+	const (
+		modeDir  = 0o755
+		modeFile = 0o644
+	)
+
+	if err := os.MkdirAll(dir, modeDir); err != nil {
+		return err
+	}
+
+	mappings := m.Mappings()
+
+	data, err := json.MarshalIndent(mappings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, IDMapFileJSON), data, modeFile); err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(mappings))
+	for _, mp := range mappings {
+		rows = append(rows, []string{string(mp.Resource), mp.OldID, mp.NewID, mp.Key, mp.Action})
+	}
+	cols := make([]string, 0, len(idMapColumns))
+	for _, c := range idMapColumns {
+		cols = append(cols, strings.ReplaceAll(strings.ToLower(c), " ", "_"))
+	}
+
+	f, err := os.Create(filepath.Join(dir, IDMapFileCSV))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	return fmtout.NewCSV(idMapColumns, rows, fmtout.WithColumns(cols), fmtout.WithNoHeaders(false)).Format(f)
+}
@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+)
+
+func TestIDMap(t *testing.T) {
+	dir := "./testdata/.tmp"
+
+	m := NewIDMap()
+	m.Add(IDMapping{Resource: engine.Customer, OldID: "gid://shopify/Customer/1", NewID: "gid://shopify/Customer/10", Key: "jon@example.com", Action: ActionCreated})
+	m.Add(IDMapping{Resource: engine.Product, OldID: "gid://shopify/Product/2", NewID: "gid://shopify/Product/20", Key: "bag, green", Action: ActionUpdated})
+	m.Add(IDMapping{Resource: engine.Product, OldID: "gid://shopify/Product/1", Key: "shoe", Action: ActionSkipped})
+
+	assert.NoError(t, m.Save(dir))
+	assert.FileExists(t, filepath.Join(dir, IDMapFileJSON))
+
+	csv, err := os.ReadFile(filepath.Join(dir, IDMapFileCSV))
+	assert.NoError(t, err)
+	assert.Equal(t, `Resource,Old ID,New ID,Key,Action
+product,gid://shopify/Product/1,,shoe,skipped
+product,gid://shopify/Product/2,gid://shopify/Product/20,"bag, green",updated
+customer,gid://shopify/Customer/1,gid://shopify/Customer/10,jon@example.com,created
+`, string(csv))
+
+	for _, file := range []string{IDMapFileCSV, IDMapFileJSON} {
+		loaded, err := LoadIDMap(filepath.Join(dir, file))
+		assert.NoError(t, err)
+		assert.Equal(t, m.Mappings(), loaded.Mappings())
+
+		id, ok := loaded.Resolve(engine.Product, "gid://shopify/Product/2")
+		assert.True(t, ok)
+		assert.Equal(t, "gid://shopify/Product/20", id)
+
+		// Skipped resources don't resolve.
+		_, ok = loaded.Resolve(engine.Product, "gid://shopify/Product/1")
+		assert.False(t, ok)
+	}
+
+	// IDs are resolved from the base map but its mappings are not recorded.
+	curr := NewIDMap(WithBase(m))
+	curr.Add(IDMapping{Resource: engine.Product, OldID: "gid://shopify/Product/3", NewID: "gid://shopify/Product/30", Action: ActionCreated})
+
+	id, ok := curr.Resolve(engine.Customer, "gid://shopify/Customer/1")
+	assert.True(t, ok)
+	assert.Equal(t, "gid://shopify/Customer/10", id)
+	assert.Equal(t, 1, curr.Len())
+
+	// Nil map is a no-op.
+	var empty *IDMap
+	empty.Add(IDMapping{Resource: engine.Product, OldID: "gid://shopify/Product/1"})
+	_, ok = empty.Resolve(engine.Product, "gid://shopify/Product/1")
+	assert.False(t, ok)
+
+	_, err = LoadIDMap(filepath.Join(dir, "id_map.txt"))
+	assert.ErrorContains(t, err, "expected a .json or a .csv file")
+
+	assert.NoError(t, os.RemoveAll(dir))
+}
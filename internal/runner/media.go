@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"mime"
+	"net/url"
+	"path"
+
+	"github.com/ankitpokhrel/shopctl"
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+// MediaDir is the directory in the product dir the media files are downloaded to.
+const MediaDir = "media"
+
+// MediaSource returns the url of the original file of the media. It is
+// empty for external videos as they are not hosted by the store.
+func MediaSource(m *api.ProductMediaNode) string {
+	switch m.MediaContentType {
+	case schema.MediaContentTypeImage:
+		if m.Preview.Image != nil {
+			return m.Preview.Image.URL
+		}
+	case schema.MediaContentTypeVideo, schema.MediaContentTypeModel3d:
+		if m.OriginalSource != nil {
+			return m.OriginalSource.URL
+		}
+	}
+	return ""
+}
+
+// MediaFile returns the name of the downloaded file of the media, i.e. the numeric
+// ID of the media with the extension of its original file. It is empty if the
+// media doesn't have a file to download.
+func MediaFile(m *api.ProductMediaNode) string {
+	src := MediaSource(m)
+	if src == "" {
+		return ""
+	}
+	u, err := url.Parse(src)
+	if err != nil {
+		return ""
+	}
+	return shopctl.ExtractNumericID(m.ID) + path.Ext(u.Path)
+}
+
+// MediaMimeType returns the mime type of the original file of the media.
+func MediaMimeType(m *api.ProductMediaNode) string {
+	if m.OriginalSource != nil && m.OriginalSource.MimeType != "" {
+		return m.OriginalSource.MimeType
+	}
+	if typ := mime.TypeByExtension(path.Ext(MediaFile(m))); typ != "" {
+		return typ
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,72 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+func TestMediaFile(t *testing.T) {
+	cases := []struct {
+		name     string
+		media    api.ProductMediaNode
+		source   string
+		file     string
+		mimeType string
+	}{
+		{
+			name: "image",
+			media: api.ProductMediaNode{
+				ID:               "gid://shopify/MediaImage/1",
+				MediaContentType: schema.MediaContentTypeImage,
+				Preview:          schema.MediaPreviewImage{Image: &schema.Image{URL: "https://cdn.shopify.com/s/files/1/files/Main.jpg?v=1712946428"}},
+			},
+			source:   "https://cdn.shopify.com/s/files/1/files/Main.jpg?v=1712946428",
+			file:     "1.jpg",
+			mimeType: "image/jpeg",
+		},
+		{
+			name: "video",
+			media: api.ProductMediaNode{
+				ID:               "gid://shopify/Video/2",
+				MediaContentType: schema.MediaContentTypeVideo,
+				Preview:          schema.MediaPreviewImage{Image: &schema.Image{URL: "https://cdn.shopify.com/s/files/1/files/preview.jpg"}},
+				OriginalSource:   &api.MediaSource{URL: "https://cdn.shopify.com/videos/c/o/v/demo.mp4", MimeType: "video/mp4"},
+			},
+			source:   "https://cdn.shopify.com/videos/c/o/v/demo.mp4",
+			file:     "2.mp4",
+			mimeType: "video/mp4",
+		},
+		{
+			name: "3d model",
+			media: api.ProductMediaNode{
+				ID:               "gid://shopify/Model3d/3",
+				MediaContentType: schema.MediaContentTypeModel3d,
+				OriginalSource:   &api.MediaSource{URL: "https://cdn.shopify.com/3d/models/o/chair.glb", MimeType: "model/gltf-binary"},
+			},
+			source:   "https://cdn.shopify.com/3d/models/o/chair.glb",
+			file:     "3.glb",
+			mimeType: "model/gltf-binary",
+		},
+		{
+			name: "external video",
+			media: api.ProductMediaNode{
+				ID:               "gid://shopify/ExternalVideo/4",
+				MediaContentType: schema.MediaContentTypeExternalVideo,
+				Preview:          schema.MediaPreviewImage{Image: &schema.Image{URL: "https://cdn.shopify.com/s/files/1/files/hqdefault.jpg"}},
+			},
+			mimeType: "application/octet-stream",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.source, MediaSource(&tc.media))
+			assert.Equal(t, tc.file, MediaFile(&tc.media))
+			assert.Equal(t, tc.mimeType, MediaMimeType(&tc.media))
+		})
+	}
+}
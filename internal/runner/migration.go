@@ -0,0 +1,290 @@
+package runner
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/pkg/fmtout"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+// UnresolvedFileCSV is the report of unresolved references written at the end of a migration.
+const UnresolvedFileCSV = "unresolved.csv"
+
+// Kinds of store-specific references.
+const (
+	RefCategory  = "category"
+	RefLocation  = "location"
+	RefMetafield = "metafield"
+	RefMedia     = "media"
+)
+
+var unresolvedColumns = []string{"Resource", "Owner", "Reference", "Value", "Reason"}
+
+// referenceTypes are the metafield reference types that can be mapped with the ID map.
+var referenceTypes = map[string]engine.ResourceType{
+	"product_reference":    engine.Product,
+	"variant_reference":    engine.ProductVariant,
+	"collection_reference": engine.Collection,
+	"customer_reference":   engine.Customer,
+}
+
+// portableReferenceTypes are the metafield reference types that refer to the
+// same resource in every store, e.g. the values of the standard product taxonomy.
+var portableReferenceTypes = []string{"product_taxonomy_value_reference"}
+
+// Unresolved is a reference to a resource of the source store that couldn't be found in the target store.
+type Unresolved struct {
+	Resource engine.ResourceType `json:"resource"`
+	// Owner is the ID of the resource in the backup that holds the reference.
+	Owner  string `json:"owner"`
+	Ref    string `json:"reference"`
+	Value  string `json:"value"`
+	Reason string `json:"reason"`
+}
+
+// Migration resolves the references to store-specific resources when an export is imported
+// to another store than it was made from. References are resolved by their natural keys in
+// the target store, and the ones that can't be resolved are recorded and left out instead of
+// failing the mutations. A nil Migration is valid and means the export is imported as is.
+type Migration struct {
+	client *api.GQLClient
+	idMap  *IDMap
+
+	mux         sync.Mutex
+	categories  map[string]string
+	unresolved  []Unresolved
+	defsMux     sync.Mutex
+	definitions map[schema.MetafieldOwnerType]map[string]string
+}
+
+// NewMigration constructs a migration to the store of the client. Referenced
+// products, variants, collections and customers are resolved with the ID map.
+func NewMigration(client *api.GQLClient, idMap *IDMap) *Migration {
+	return &Migration{
+		client:      client,
+		idMap:       idMap,
+		categories:  make(map[string]string),
+		definitions: make(map[schema.MetafieldOwnerType]map[string]string),
+	}
+}
+
+// Unresolve records a reference that couldn't be resolved.
+func (m *Migration) Unresolve(u Unresolved) {
+	if m == nil {
+		return
+	}
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.unresolved = append(m.unresolved, u)
+}
+
+// Unresolved returns the references that couldn't be resolved ordered by resource type and the owner.
+func (m *Migration) Unresolved() []Unresolved {
+	if m == nil {
+		return nil
+	}
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	order := make(map[engine.ResourceType]int)
+	for i, rt := range engine.GetAllResourceTypes() {
+		order[rt] = i
+	}
+
+	out := slices.Clone(m.unresolved)
+	slices.SortStableFunc(out, func(a, b Unresolved) int {
+		return cmp.Or(
+			cmp.Compare(order[a.Resource], order[b.Resource]),
+			cmp.Compare(a.Owner, b.Owner),
+		)
+	})
+	return out
+}
+
+// Category resolves the product category by its full name in the target store.
+func (m *Migration) Category(ctx context.Context, c *schema.TaxonomyCategory) (string, bool, error) {
+	m.mux.Lock()
+	id, ok := m.categories[c.FullName]
+	m.mux.Unlock()
+
+	if ok {
+		return id, id != "", nil
+	}
+
+	nodes, err := m.client.GetTaxonomyCategories(ctx, c.Name)
+	if err != nil {
+		return "", false, err
+	}
+	for _, n := range nodes {
+		if n.FullName == c.FullName {
+			id = n.ID
+			break
+		}
+	}
+
+	m.mux.Lock()
+	m.categories[c.FullName] = id
+	m.mux.Unlock()
+
+	return id, id != "", nil
+}
+
+// ResolveMetafields resolves the metafields of a resource in the target store. The metafields that
+// have a definition of a different type in the target store or refer to resources that can't be
+// resolved are recorded and left out. It returns the resolved metafields and the namespaced keys
+// of the metafields that were left out.
+func (m *Migration) ResolveMetafields(
+	ctx context.Context,
+	rt engine.ResourceType,
+	owner string,
+	ownerType schema.MetafieldOwnerType,
+	metafields []schema.Metafield,
+) ([]schema.Metafield, map[string]bool, error) {
+	if m == nil {
+		return metafields, nil, nil
+	}
+
+	definitions, err := m.getDefinitions(ctx, ownerType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		resolved = make([]schema.Metafield, 0, len(metafields))
+		skipped  = make(map[string]bool)
+	)
+	for _, mf := range metafields {
+		key := mf.Namespace + "." + mf.Key
+
+		if typ, ok := definitions[key]; ok && typ != mf.Type {
+			m.Unresolve(Unresolved{
+				Resource: rt, Owner: owner, Ref: RefMetafield, Value: key,
+				Reason: "definition in the target store has type " + typ,
+			})
+			skipped[key] = true
+			continue
+		}
+
+		value, missing := remapReferences(m.idMap, mf.Type, mf.Value)
+		if len(missing) > 0 {
+			m.Unresolve(Unresolved{
+				Resource: rt, Owner: owner, Ref: RefMetafield, Value: key,
+				Reason: "referenced resources not found: " + strings.Join(missing, ","),
+			})
+			skipped[key] = true
+			continue
+		}
+
+		mf.Value = value
+		resolved = append(resolved, mf)
+	}
+	return resolved, skipped, nil
+}
+
+func (m *Migration) getDefinitions(ctx context.Context, ownerType schema.MetafieldOwnerType) (map[string]string, error) {
+	m.defsMux.Lock()
+	defer m.defsMux.Unlock()
+
+	if defs, ok := m.definitions[ownerType]; ok {
+		return defs, nil
+	}
+
+	nodes, err := m.client.GetMetafieldDefinitions(ctx, ownerType)
+	if err != nil {
+		return nil, err
+	}
+	defs := make(map[string]string, len(nodes))
+	for _, n := range nodes {
+		defs[n.Namespace+"."+n.Key] = n.Type.Name
+	}
+	m.definitions[ownerType] = defs
+	return defs, nil
+}
+
+// remapReferences maps the IDs of the resources the metafield value refers to.
+// It returns the IDs that can't be mapped along with the value.
+func remapReferences(idMap *IDMap, typ string, value string) (string, []string) {
+	base := strings.TrimPrefix(typ, "list.")
+	if !strings.HasSuffix(base, "_reference") || slices.Contains(portableReferenceTypes, base) {
+		return value, nil
+	}
+
+	rt, ok := referenceTypes[base]
+	if base == typ {
+		if !ok {
+			return value, []string{value}
+		}
+		id, ok := idMap.Resolve(rt, value)
+		if !ok {
+			return value, []string{value}
+		}
+		return id, nil
+	}
+
+	var ids []string
+	if err := json.Unmarshal([]byte(value), &ids); err != nil {
+		return value, []string{value}
+	}
+	if !ok {
+		return value, ids
+	}
+
+	missing := make([]string, 0)
+	for i, old := range ids {
+		id, ok := idMap.Resolve(rt, old)
+		if !ok {
+			missing = append(missing, old)
+			continue
+		}
+		ids[i] = id
+	}
+	if len(missing) > 0 {
+		return value, missing
+	}
+
+	out, err := json.Marshal(ids)
+	if err != nil {
+		return value, []string{value}
+	}
+	return string(out), nil
+}
+
+// Save writes the unresolved references as a csv file to the given dir.
+func (m *Migration) Save(dir string) error {
+	const modeDir = 0o755
+
+	if err := os.MkdirAll(dir, modeDir); err != nil {
+		return err
+	}
+
+	unresolved := m.Unresolved()
+
+	rows := make([][]string, 0, len(unresolved))
+	for _, u := range unresolved {
+		rows = append(rows, []string{string(u.Resource), u.Owner, u.Ref, u.Value, u.Reason})
+	}
+	cols := make([]string, 0, len(unresolvedColumns))
+	for _, c := range unresolvedColumns {
+		cols = append(cols, strings.ToLower(c))
+	}
+
+	f, err := os.Create(filepath.Join(dir, UnresolvedFileCSV))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	return fmtout.NewCSV(unresolvedColumns, rows, fmtout.WithColumns(cols), fmtout.WithNoHeaders(false)).Format(f)
+}
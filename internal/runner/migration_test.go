@@ -0,0 +1,128 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+func TestRemapReferences(t *testing.T) {
+	idMap := NewIDMap()
+	idMap.Add(IDMapping{Resource: engine.Product, OldID: "gid://shopify/Product/1", NewID: "gid://shopify/Product/10", Action: ActionCreated})
+	idMap.Add(IDMapping{Resource: engine.Product, OldID: "gid://shopify/Product/2", NewID: "gid://shopify/Product/20", Action: ActionCreated})
+
+	cases := []struct {
+		name    string
+		typ     string
+		value   string
+		want    string
+		missing []string
+	}{
+		{
+			name:  "not a reference",
+			typ:   "single_line_text_field",
+			value: "gid://shopify/Product/1",
+			want:  "gid://shopify/Product/1",
+		},
+		{
+			name:  "portable reference",
+			typ:   "list.product_taxonomy_value_reference",
+			value: `["gid://shopify/TaxonomyValue/1"]`,
+			want:  `["gid://shopify/TaxonomyValue/1"]`,
+		},
+		{
+			name:  "mapped reference",
+			typ:   "product_reference",
+			value: "gid://shopify/Product/1",
+			want:  "gid://shopify/Product/10",
+		},
+		{
+			name:  "mapped list of references",
+			typ:   "list.product_reference",
+			value: `["gid://shopify/Product/1","gid://shopify/Product/2"]`,
+			want:  `["gid://shopify/Product/10","gid://shopify/Product/20"]`,
+		},
+		{
+			name:    "unmapped reference in a list",
+			typ:     "list.product_reference",
+			value:   `["gid://shopify/Product/1","gid://shopify/Product/3"]`,
+			want:    `["gid://shopify/Product/1","gid://shopify/Product/3"]`,
+			missing: []string{"gid://shopify/Product/3"},
+		},
+		{
+			name:    "store-specific reference",
+			typ:     "file_reference",
+			value:   "gid://shopify/MediaImage/1",
+			want:    "gid://shopify/MediaImage/1",
+			missing: []string{"gid://shopify/MediaImage/1"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, missing := remapReferences(idMap, tc.typ, tc.value)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.missing, missing)
+		})
+	}
+}
+
+func TestMigration(t *testing.T) {
+	dir := "./testdata/.tmp"
+
+	idMap := NewIDMap()
+	idMap.Add(IDMapping{Resource: engine.Product, OldID: "gid://shopify/Product/1", NewID: "gid://shopify/Product/10", Action: ActionCreated})
+
+	m := NewMigration(nil, idMap)
+	m.definitions[schema.MetafieldOwnerTypeProduct] = map[string]string{
+		"custom.size":    "number_integer",
+		"custom.related": "product_reference",
+	}
+
+	resolved, skipped, err := m.ResolveMetafields(context.Background(), engine.ProductMetaField, "gid://shopify/Product/2", schema.MetafieldOwnerTypeProduct, []schema.Metafield{
+		{Namespace: "custom", Key: "size", Type: "single_line_text_field", Value: "XL"},
+		{Namespace: "custom", Key: "related", Type: "product_reference", Value: "gid://shopify/Product/1"},
+		{Namespace: "custom", Key: "manual", Type: "file_reference", Value: "gid://shopify/GenericFile/1"},
+		{Namespace: "custom", Key: "note", Type: "single_line_text_field", Value: "Fragile"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"custom.size": true, "custom.manual": true}, skipped)
+	assert.Len(t, resolved, 2)
+	assert.Equal(t, "gid://shopify/Product/10", resolved[0].Value)
+	assert.Equal(t, "Fragile", resolved[1].Value)
+
+	m.Unresolve(Unresolved{Resource: engine.Customer, Owner: "gid://shopify/Customer/1", Ref: RefMetafield, Value: "custom.tier", Reason: "referenced resources not found"})
+	m.Unresolve(Unresolved{Resource: engine.Product, Owner: "gid://shopify/Product/3", Ref: RefCategory, Value: "Apparel > Shoes", Reason: "category not found"})
+
+	unresolved := m.Unresolved()
+	assert.Len(t, unresolved, 4)
+	assert.Equal(t, RefCategory, unresolved[0].Ref)
+	assert.Equal(t, "custom.size", unresolved[1].Value)
+	assert.Equal(t, "definition in the target store has type number_integer", unresolved[1].Reason)
+	assert.Equal(t, "custom.manual", unresolved[2].Value)
+	assert.Equal(t, engine.Customer, unresolved[3].Resource)
+
+	assert.NoError(t, m.Save(dir))
+
+	csv, err := os.ReadFile(filepath.Join(dir, UnresolvedFileCSV))
+	assert.NoError(t, err)
+	assert.Contains(t, string(csv), "Resource,Owner,Reference,Value,Reason\n")
+	assert.Contains(t, string(csv), "product,gid://shopify/Product/3,category,Apparel > Shoes,category not found\n")
+
+	// Nil migration doesn't resolve anything.
+	var empty *Migration
+	resolved, skipped, err = empty.ResolveMetafields(context.Background(), engine.ProductMetaField, "", schema.MetafieldOwnerTypeProduct, []schema.Metafield{{Key: "size"}})
+	assert.NoError(t, err)
+	assert.Nil(t, skipped)
+	assert.Len(t, resolved, 1)
+	empty.Unresolve(Unresolved{Resource: engine.Product})
+	assert.Empty(t, empty.Unresolved())
+
+	assert.NoError(t, os.RemoveAll(dir))
+}
@@ -0,0 +1,252 @@
+package runner
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/pkg/diff"
+)
+
+// Plan actions.
+const (
+	PlanCreate    = "create"
+	PlanUpdate    = "update"
+	PlanDelete    = "delete"
+	PlanUnchanged = "unchanged"
+)
+
+// PlanEntry is a change an import would make to a resource in the store.
+type PlanEntry struct {
+	Resource engine.ResourceType `json:"resource"`
+	// Parent is the ID of the parent resource in the backup, e.g. the product of a variant.
+	Parent     string        `json:"parent,omitempty"`
+	Key        string        `json:"key"`
+	OldID      string        `json:"oldId,omitempty"`
+	UpstreamID string        `json:"upstreamId,omitempty"`
+	Action     string        `json:"action"`
+	Changes    []diff.Change `json:"changes,omitempty"`
+	// Upstream is the checksum of the state of the resource in the store
+	// the plan was made against. It is empty if the resource doesn't exist.
+	Upstream string `json:"upstream,omitempty"`
+}
+
+func (e PlanEntry) id() string {
+	if e.Parent == "" {
+		return fmt.Sprintf("%s %s", e.Resource, e.Key)
+	}
+	return fmt.Sprintf("%s %s/%s", e.Resource, e.Parent, e.Key)
+}
+
+// Plan keeps track of the changes an import would make to the store. It is used to
+// review the changes before applying them. A nil Plan is valid and doesn't record anything.
+type Plan struct {
+	Store     string         `json:"store"`
+	From      string         `json:"from"`
+	Resources []string       `json:"resources"`
+	Summary   map[string]int `json:"summary"`
+	Entries   []PlanEntry    `json:"entries"`
+
+	mux sync.Mutex
+	// applied are the entries of the saved plan that is being applied, keyed by their id.
+	applied map[string]PlanEntry
+}
+
+// NewPlan constructs an empty plan to import the given resources from the backup at `from` to the store.
+func NewPlan(store string, from string, resources []string) *Plan {
+	return &Plan{
+		Store:     store,
+		From:      from,
+		Resources: resources,
+		Entries:   make([]PlanEntry, 0),
+	}
+}
+
+// NewApplyPlan constructs a plan to apply the saved plan with. Entries added to it are
+// checked against the saved plan right before the resources are mutated, see `Plan.Add`.
+func NewApplyPlan(saved *Plan) *Plan {
+	p := NewPlan(saved.Store, saved.From, saved.Resources)
+	p.applied = make(map[string]PlanEntry, len(saved.Entries))
+	for _, e := range saved.Entries {
+		p.applied[e.id()] = e
+	}
+	return p
+}
+
+// IsApply tells if the plan is used to apply a saved plan.
+func (p *Plan) IsApply() bool {
+	return p != nil && p.applied != nil
+}
+
+// LoadPlan loads a plan from a json file.
+func LoadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("unable to parse plan %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// Add adds an entry to the plan.
+//
+// If a saved plan is being applied, the entry is compared with the saved one and an error is
+// returned if they differ, e.g. if the resource was changed in the store since the plan was
+// made, so that the resource is not mutated beyond what was reviewed.
+func (p *Plan) Add(e PlanEntry) error {
+	if p == nil {
+		return nil
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.Entries = append(p.Entries, e)
+
+	if p.applied == nil {
+		return nil
+	}
+	pe, ok := p.applied[e.id()]
+	if reason := compareEntry(pe, ok, e); reason != "" {
+		return fmt.Errorf("%s: %s", e.id(), reason)
+	}
+	return nil
+}
+
+// Record adds an entry to the plan along with the checksum of the state of the
+// resource in the store. Upstream is nil if the resource doesn't exist in the store.
+func (p *Plan) Record(e PlanEntry, upstream any) error {
+	if p == nil {
+		return nil
+	}
+	if upstream != nil {
+		sum, err := Checksum(upstream)
+		if err != nil {
+			return err
+		}
+		e.Upstream = sum
+	}
+	return p.Add(e)
+}
+
+// Sort orders the entries by resource type, the parent and the key, and updates the summary.
+func (p *Plan) Sort() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	order := make(map[engine.ResourceType]int)
+	for i, rt := range engine.GetAllResourceTypes() {
+		order[rt] = i
+	}
+	slices.SortStableFunc(p.Entries, func(a, b PlanEntry) int {
+		return cmp.Or(
+			cmp.Compare(order[a.Resource], order[b.Resource]),
+			cmp.Compare(a.Parent, b.Parent),
+			cmp.Compare(a.Key, b.Key),
+		)
+	})
+
+	p.Summary = map[string]int{PlanCreate: 0, PlanUpdate: 0, PlanDelete: 0, PlanUnchanged: 0}
+	for _, e := range p.Entries {
+		p.Summary[e.Action]++
+	}
+}
+
+// Save writes the plan as a json file.
+func (p *Plan) Save(path string) error {
+	const (
+		modeDir  = 0o755
+		modeFile = 0o644
+	)
+
+	if err := os.MkdirAll(filepath.Dir(path), modeDir); err != nil {
+		return err
+	}
+
+	p.Sort()
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, modeFile)
+}
+
+// Drift compares the plan with a plan made against the current state of the store and returns
+// the resources that differ, e.g. if the resource was changed in the store after the plan was made.
+func (p *Plan) Drift(current *Plan) []string {
+	planned := make(map[string]PlanEntry, len(p.Entries))
+	for _, e := range p.Entries {
+		planned[e.id()] = e
+	}
+
+	drift := make([]string, 0)
+	for _, e := range current.Entries {
+		pe, ok := planned[e.id()]
+		delete(planned, e.id())
+
+		if reason := compareEntry(pe, ok, e); reason != "" {
+			drift = append(drift, fmt.Sprintf("%s: %s", e.id(), reason))
+		}
+	}
+	for id, e := range planned {
+		drift = append(drift, fmt.Sprintf("%s: planned to %s, no longer applicable", id, e.Action))
+	}
+
+	slices.Sort(drift)
+	return drift
+}
+
+// compareEntry compares the planned entry, if any, with the entry for the current state of
+// the store and returns the reason they differ. It is empty if the entries are the same.
+func compareEntry(planned PlanEntry, ok bool, current PlanEntry) string {
+	switch {
+	case !ok:
+		return fmt.Sprintf("not in the plan, would %s", current.Action)
+	case planned.Upstream != current.Upstream || planned.UpstreamID != current.UpstreamID:
+		return "changed in the store since the plan was made"
+	case planned.Action != current.Action || !equalChanges(planned.Changes, current.Changes):
+		return fmt.Sprintf("planned to %s, would %s now", planned.Action, current.Action)
+	}
+	return ""
+}
+
+// Checksum returns the checksum of the JSON representation of the value,
+// e.g. to record the state of a resource in the store in a plan entry.
+func Checksum(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// equalChanges compares the changes by their JSON representation
+// as the values loaded from a plan file are generic JSON values.
+func equalChanges(a, b []diff.Change) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	na, err := diff.Normalize(a)
+	if err != nil {
+		return false
+	}
+	nb, err := diff.Normalize(b)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(na, nb)
+}
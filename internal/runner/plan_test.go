@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/pkg/diff"
+)
+
+func TestPlan(t *testing.T) {
+	dir := "./testdata/.tmp"
+	path := filepath.Join(dir, "plan.json")
+
+	sum, err := Checksum(map[string]any{"title": "Shoe"})
+	assert.NoError(t, err)
+
+	p := NewPlan("example.myshopify.com", "/path/to/export", []string{"product", "customer=tags:vip"})
+	assert.NoError(t, p.Add(PlanEntry{Resource: engine.Customer, Key: "jon@example.com", OldID: "gid://shopify/Customer/1", Action: PlanCreate}))
+	assert.NoError(t, p.Add(PlanEntry{Resource: engine.ProductVariant, Parent: "gid://shopify/Product/1", Key: "Red", Action: PlanCreate}))
+	assert.NoError(t, p.Record(PlanEntry{
+		Resource: engine.Product, Key: "shoe", OldID: "gid://shopify/Product/1", UpstreamID: "gid://shopify/Product/10", Action: PlanUpdate,
+		Changes: []diff.Change{{Field: "title", Old: "Shoe", New: "Shoes"}, {Field: "tags", Old: []any{"a"}, New: []any{"a", "b"}}},
+	}, map[string]any{"title": "Shoe"}))
+
+	assert.NoError(t, p.Save(path))
+
+	loaded, err := LoadPlan(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "example.myshopify.com", loaded.Store)
+	assert.Equal(t, []string{"product", "customer=tags:vip"}, loaded.Resources)
+	assert.Equal(t, map[string]int{PlanCreate: 2, PlanUpdate: 1, PlanDelete: 0, PlanUnchanged: 0}, loaded.Summary)
+
+	// Entries are ordered by the resource type.
+	assert.Len(t, loaded.Entries, 3)
+	assert.Equal(t, engine.Product, loaded.Entries[0].Resource)
+	assert.Equal(t, sum, loaded.Entries[0].Upstream)
+	assert.Equal(t, engine.ProductVariant, loaded.Entries[1].Resource)
+	assert.Equal(t, engine.Customer, loaded.Entries[2].Resource)
+
+	// Plan made against the same state of the store doesn't drift.
+	assert.Empty(t, loaded.Drift(p))
+
+	curr := NewPlan("example.myshopify.com", "/path/to/export", nil)
+	assert.NoError(t, curr.Record(PlanEntry{
+		Resource: engine.Product, Key: "shoe", OldID: "gid://shopify/Product/1", UpstreamID: "gid://shopify/Product/10", Action: PlanUpdate,
+		Changes: []diff.Change{{Field: "title", Old: "Sneaker", New: "Shoes"}},
+	}, map[string]any{"title": "Sneaker"}))
+	assert.NoError(t, curr.Add(PlanEntry{Resource: engine.ProductVariant, Parent: "gid://shopify/Product/1", Key: "Red", Action: PlanCreate}))
+	assert.NoError(t, curr.Add(PlanEntry{Resource: engine.ProductMedia, Parent: "gid://shopify/Product/1", Key: "gid://shopify/MediaImage/1", Action: PlanDelete}))
+
+	assert.Equal(t, []string{
+		"customer jon@example.com: planned to create, no longer applicable",
+		"product shoe: changed in the store since the plan was made",
+		"product_media gid://shopify/Product/1/gid://shopify/MediaImage/1: not in the plan, would delete",
+	}, loaded.Drift(curr))
+
+	// Nil plan is a no-op.
+	var empty *Plan
+	assert.NoError(t, empty.Add(PlanEntry{Resource: engine.Product, Key: "shoe", Action: PlanCreate}))
+	assert.NoError(t, empty.Record(PlanEntry{Resource: engine.Product, Key: "shoe", Action: PlanCreate}, nil))
+	assert.False(t, empty.IsApply())
+
+	assert.NoError(t, os.RemoveAll(dir))
+}
+
+func TestApplyPlan(t *testing.T) {
+	saved := NewPlan("example.myshopify.com", "/path/to/export", []string{"product"})
+	assert.NoError(t, saved.Record(PlanEntry{
+		Resource: engine.Product, Key: "shoe", OldID: "gid://shopify/Product/1", UpstreamID: "gid://shopify/Product/10", Action: PlanUpdate,
+		Changes: []diff.Change{{Field: "title", Old: "Shoe", New: "Shoes"}},
+	}, map[string]any{"title": "Shoe"}))
+	assert.NoError(t, saved.Add(PlanEntry{Resource: engine.ProductVariant, Parent: "gid://shopify/Product/1", Key: "Red", Action: PlanCreate}))
+	assert.False(t, saved.IsApply())
+
+	p := NewApplyPlan(saved)
+	assert.True(t, p.IsApply())
+	assert.Equal(t, saved.Store, p.Store)
+	assert.Equal(t, saved.From, p.From)
+	assert.Equal(t, saved.Resources, p.Resources)
+	assert.Empty(t, p.Entries)
+
+	// Entries that match the plan are accepted.
+	assert.NoError(t, p.Record(PlanEntry{
+		Resource: engine.Product, Key: "shoe", OldID: "gid://shopify/Product/1", UpstreamID: "gid://shopify/Product/10", Action: PlanUpdate,
+		Changes: []diff.Change{{Field: "title", Old: "Shoe", New: "Shoes"}},
+	}, map[string]any{"title": "Shoe"}))
+
+	// Resource changed in the store after the plan was made is rejected.
+	assert.EqualError(t, p.Record(PlanEntry{
+		Resource: engine.Product, Key: "shoe", OldID: "gid://shopify/Product/1", UpstreamID: "gid://shopify/Product/10", Action: PlanUpdate,
+		Changes: []diff.Change{{Field: "title", Old: "Shoe", New: "Shoes"}},
+	}, map[string]any{"title": "Sneaker"}), "product shoe: changed in the store since the plan was made")
+
+	assert.EqualError(t,
+		p.Add(PlanEntry{Resource: engine.ProductVariant, Parent: "gid://shopify/Product/1", Key: "Red", Action: PlanDelete}),
+		"product_variant gid://shopify/Product/1/Red: planned to create, would delete now",
+	)
+	assert.EqualError(t,
+		p.Add(PlanEntry{Resource: engine.ProductVariant, Parent: "gid://shopify/Product/1", Key: "Blue", Action: PlanCreate}),
+		"product_variant gid://shopify/Product/1/Blue: not in the plan, would create",
+	)
+}
@@ -0,0 +1,180 @@
+package collection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/internal/runner/restore/collection/handler"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+)
+
+// Runner is a collection restore runner.
+type Runner struct {
+	path     string
+	eng      *engine.Engine
+	rstEng   *engine.Restore
+	client   *api.GQLClient
+	logger   *tlog.Logger
+	stats    map[engine.ResourceType]*runner.Summary
+	filters  *runner.RestoreFilter
+	idMap    *runner.IDMap
+	isDryRun bool
+}
+
+// NewRunner constructs a new restore runner.
+func NewRunner(path string, eng *engine.Engine, client *api.GQLClient, logger *tlog.Logger, filters *runner.RestoreFilter, idMap *runner.IDMap, isDryRun bool) *Runner {
+	rstEng := eng.Doer().(*engine.Restore)
+
+	stats := make(map[engine.ResourceType]*runner.Summary)
+	for _, rt := range engine.GetCollectionResourceTypes() {
+		stats[rt] = &runner.Summary{}
+	}
+
+	return &Runner{
+		path:     path,
+		eng:      eng,
+		rstEng:   rstEng,
+		client:   client,
+		logger:   logger,
+		stats:    stats,
+		filters:  filters,
+		idMap:    idMap,
+		isDryRun: isDryRun,
+	}
+}
+
+// FilterFields returns the fields collections can be filtered by on restore.
+func FilterFields() []string {
+	return handler.FilterFields.Names()
+}
+
+// Kind returns runner type; implements `runner.Runner` interface.
+func (r *Runner) Kind() engine.ResourceType {
+	return engine.Collection
+}
+
+// Run executes collection restoration process; implements `runner.Runner` interface.
+func (r *Runner) Run(ctx context.Context) error {
+	r.eng.Register(engine.Collection)
+	restoreStart := time.Now()
+
+	go func() {
+		defer r.eng.Done(engine.Collection)
+
+		// TODO: Handle/log error.
+		_ = r.restore()
+	}()
+
+	for res := range r.eng.Run(ctx, engine.Collection) {
+		if res.Err != nil && !errors.Is(res.Err, engine.ErrSkipChildren) {
+			r.stats[res.ResourceType].Failed += 1
+			r.logger.Errorf("Failed to restore resource %s: %v\n", res.ResourceType, res.Err)
+		}
+	}
+
+	// Resources restored in an earlier run are skipped on resume.
+	for rt, st := range r.stats {
+		n := r.rstEng.Resumed(rt)
+		st.Count += n
+		st.Skipped += n
+	}
+
+	r.logger.V(tlog.VL3).Infof(
+		"Collection restore complete in %s",
+		time.Since(restoreStart),
+	)
+	return nil
+}
+
+func (r *Runner) restore() error {
+	foundFiles, err := registry.GetAllInDir(r.path, ".json")
+	if err != nil {
+		return err
+	}
+
+	// This is the max number of resources we're expecting to process.
+	const maxNumResources = 2
+
+	// When adding resource to the resource collection we need to maintain
+	// following order: Collection -> Products
+	const (
+		Collection = iota
+		Products
+	)
+
+	// Initialize resources with fixed slots for ordering.
+	resources := make(map[string][][]engine.Resource)
+
+	for f := range foundFiles {
+		if f.Err != nil {
+			r.logger.Warn("Skipping file due to read err", "file", f.Path, "error", f.Err)
+			continue
+		}
+
+		currentID, err := extractID(f.Path)
+		if err != nil {
+			return err
+		}
+
+		if _, exists := resources[currentID]; !exists {
+			resources[currentID] = make([][]engine.Resource, maxNumResources)
+		}
+
+		switch filepath.Base(f.Path) {
+		case "collection.json":
+			collectionFn := &handler.Collection{Client: r.client, File: f, Filter: r.filters, Logger: r.logger, Summary: r.stats[engine.Collection], IDMap: r.idMap, DryRun: r.isDryRun}
+			resources[currentID][Collection] = append(
+				resources[currentID][Collection],
+				engine.NewResource(engine.Collection, filepath.Dir(f.Path), collectionFn),
+			)
+		case "collection_products.json":
+			productFn := &handler.Product{Client: r.client, File: f, Logger: r.logger, Summary: r.stats[engine.CollectionProduct], IDMap: r.idMap, DryRun: r.isDryRun}
+			resources[currentID][Products] = append(
+				resources[currentID][Products],
+				engine.NewResource(engine.CollectionProduct, filepath.Dir(f.Path), productFn),
+			)
+		}
+	}
+
+	// Flatten resources for each currentID in the defined order.
+	for _, orderedResources := range resources {
+		var flattened engine.ResourceCollection
+
+		if len(orderedResources[Collection]) > 0 {
+			flattened.Parent = &orderedResources[Collection][0]
+		}
+
+		for idx, rc := range orderedResources {
+			if idx == Collection {
+				continue
+			}
+			flattened.Children = append(flattened.Children, rc...)
+		}
+		if flattened.Parent != nil {
+			r.eng.Add(engine.Collection, flattened)
+		}
+	}
+	return nil
+}
+
+// Stats returns runner stats.
+func (r *Runner) Stats() map[engine.ResourceType]*runner.Summary {
+	return r.stats
+}
+
+func extractID(path string) (string, error) {
+	parts := strings.Split(filepath.Clean(path), string(filepath.Separator))
+
+	if len(parts) < 2 {
+		return "", fmt.Errorf("path does not have enough elements")
+	}
+	return parts[len(parts)-2], nil
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ankitpokhrel/shopctl"
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+const (
+	collectionTypeSmart  = "smart"
+	collectionTypeCustom = "custom"
+)
+
+type Collection struct {
+	Client  *api.GQLClient
+	Logger  *tlog.Logger
+	File    registry.File
+	Filter  *runner.RestoreFilter
+	Summary *runner.Summary
+	IDMap   *runner.IDMap
+	DryRun  bool
+}
+
+func (h *Collection) Handle(ctx context.Context, data any) (any, error) {
+	collectionRaw, err := registry.ReadFileContents(h.File.Path)
+	if err != nil {
+		h.Logger.Error("Unable to read contents", "file", h.File.Path, "error", err)
+		return nil, err
+	}
+	h.Summary.Count += 1
+
+	var collection schema.Collection
+	if err = json.Unmarshal(collectionRaw, &collection); err != nil {
+		h.Logger.Error("Unable to marshal contents", "file", h.File.Path, "error", err)
+		h.Summary.Failed += 1
+		return nil, err
+	}
+
+	// Filter collection.
+	if !FilterFields.Match(h.Filter, &collection) {
+		h.Summary.Skipped += 1
+		h.IDMap.Add(runner.IDMapping{Resource: engine.Collection, OldID: collection.ID, Key: collection.Handle, Action: runner.ActionSkipped})
+		return nil, engine.ErrSkipChildren
+	}
+
+	if h.DryRun {
+		h.Logger.V(tlog.VL3).Warn("Skipping collection sync")
+		h.Summary.Passed += 1
+		return collection.ID, nil
+	}
+	res, action, err := createOrUpdateCollection(ctx, &collection, h.Client, h.IDMap, h.Logger)
+	if err != nil {
+		h.Summary.Failed += 1
+		return nil, err
+	}
+	h.Summary.Passed += 1
+	h.IDMap.Add(runner.IDMapping{Resource: engine.Collection, OldID: collection.ID, NewID: res.Collection.ID, Key: collection.Handle, Action: action})
+	return res.Collection.ID, nil
+}
+
+func createOrUpdateCollection(ctx context.Context, collection *schema.Collection, client *api.GQLClient, idMap *runner.IDMap, lgr *tlog.Logger) (*api.CollectionSyncResponse, string, error) {
+	input := schema.CollectionInput{
+		Title:           &collection.Title,
+		Handle:          &collection.Handle,
+		DescriptionHtml: &collection.DescriptionHtml,
+		SortOrder:       &collection.SortOrder,
+		TemplateSuffix:  collection.TemplateSuffix,
+		Seo: &schema.SEOInput{
+			Title:       collection.Seo.Title,
+			Description: collection.Seo.Description,
+		},
+	}
+	if collection.Image != nil && collection.Image.URL != "" {
+		input.Image = &schema.ImageInput{
+			Src:     &collection.Image.URL,
+			AltText: collection.Image.AltText,
+		}
+	}
+	if collection.RuleSet != nil {
+		rules := make([]schema.CollectionRuleInput, 0, len(collection.RuleSet.Rules))
+		for _, r := range collection.RuleSet.Rules {
+			rules = append(rules, schema.CollectionRuleInput{
+				Column:    r.Column,
+				Relation:  r.Relation,
+				Condition: r.Condition,
+			})
+		}
+		input.RuleSet = &schema.CollectionRuleSetInput{
+			AppliedDisjunctively: collection.RuleSet.AppliedDisjunctively,
+			Rules:                rules,
+		}
+	}
+
+	if upstreamID := findUpstreamCollection(ctx, collection, client, idMap); upstreamID != "" {
+		input.ID = &upstreamID
+
+		lgr.Warn("Collection already exists, updating", "oldID", collection.ID, "upstreamID", upstreamID)
+		res, err := client.UpdateCollection(ctx, input)
+		return res, runner.ActionUpdated, err
+	}
+
+	lgr.Info("Creating collection", "id", collection.ID, "handle", collection.Handle)
+	res, err := client.CreateCollection(ctx, input)
+	return res, runner.ActionCreated, err
+}
+
+// findUpstreamCollection looks up the collection by its mapped ID and then by its handle.
+func findUpstreamCollection(ctx context.Context, collection *schema.Collection, client *api.GQLClient, idMap *runner.IDMap) string {
+	if newID, ok := idMap.Resolve(engine.Collection, collection.ID); ok {
+		if c, err := client.CheckCollectionByID(ctx, newID); err == nil && c.ID != "" {
+			return c.ID
+		}
+	}
+	if c, err := client.CheckCollectionByHandle(ctx, collection.Handle); err == nil && c.ID != "" {
+		return c.ID
+	}
+	return ""
+}
+
+// FilterFields are the fields collections can be filtered by on restore.
+var FilterFields = runner.FilterFields[schema.Collection]{
+	"id": func(c *schema.Collection) []string {
+		return []string{shopctl.ExtractNumericID(c.ID), c.ID}
+	},
+	"handle": func(c *schema.Collection) []string { return []string{c.Handle} },
+	"title":  func(c *schema.Collection) []string { return []string{c.Title} },
+	"type": func(c *schema.Collection) []string {
+		if c.RuleSet != nil {
+			return []string{collectionTypeSmart}
+		}
+		return []string{collectionTypeCustom}
+	},
+	"updated_at": func(c *schema.Collection) []string { return []string{c.UpdatedAt} },
+	"default":    func(c *schema.Collection) []string { return []string{c.Title, c.Handle} },
+}
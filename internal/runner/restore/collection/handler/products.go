@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+)
+
+// Shopify accepts at most 250 products per call when adding products to a collection.
+const addProductsBatchSize = 250
+
+type Product struct {
+	Client  *api.GQLClient
+	Logger  *tlog.Logger
+	File    registry.File
+	Summary *runner.Summary
+	IDMap   *runner.IDMap
+	DryRun  bool
+}
+
+func (h Product) Handle(ctx context.Context, data any) (any, error) {
+	var realCollectionID string
+	if id, ok := data.(string); ok {
+		realCollectionID = id
+	} else {
+		return nil, fmt.Errorf("unable to figure out real collection ID")
+	}
+
+	productsRaw, err := registry.ReadFileContents(h.File.Path)
+	if err != nil {
+		h.Logger.Error("Unable to read contents", "file", h.File.Path, "error", err)
+		return nil, err
+	}
+	h.Summary.Count += 1
+
+	var products api.CollectionProductsData
+	if err = json.Unmarshal(productsRaw, &products); err != nil {
+		h.Logger.Error("Unable to marshal contents", "file", h.File.Path, "error", err)
+		h.Summary.Failed += 1
+		return nil, err
+	}
+	if len(products.Products.Nodes) == 0 {
+		return nil, nil
+	}
+
+	if h.DryRun {
+		h.Logger.V(tlog.VL2).Infof("Collection products to sync: %d", len(products.Products.Nodes))
+		h.Logger.V(tlog.VL3).Warn("Skipping collection products sync")
+		h.Summary.Passed += 1
+		return nil, nil
+	}
+
+	existing := make(map[string]bool)
+	if current, err := h.Client.GetCollectionProducts(ctx, realCollectionID); err == nil {
+		for _, p := range current.Products.Nodes {
+			existing[p.ID] = true
+		}
+	}
+
+	// Products are added in the order they were in the backup
+	// so that the manual sort order of the collection is kept.
+	toAdd := make([]string, 0, len(products.Products.Nodes))
+	for _, p := range products.Products.Nodes {
+		pid := h.resolveProduct(ctx, &p)
+		if pid == "" {
+			h.Logger.Warn("Unable to find the product of the collection, skipping", "collection", realCollectionID, "product", p.ID, "handle", p.Handle)
+			continue
+		}
+		if !existing[pid] {
+			existing[pid] = true
+			toAdd = append(toAdd, pid)
+		}
+	}
+
+	for i := 0; i < len(toAdd); i += addProductsBatchSize {
+		batch := toAdd[i:min(i+addProductsBatchSize, len(toAdd))]
+
+		h.Logger.V(tlog.VL2).Info("Attempting to add products to collection", "id", realCollectionID, "count", len(batch))
+		if _, err := h.Client.AddProductsToCollection(ctx, realCollectionID, batch); err != nil {
+			h.Logger.Error("Failed to add products to collection", "oldID", products.CollectionID, "upstreamID", realCollectionID)
+			h.Summary.Failed += 1
+			return nil, err
+		}
+	}
+	h.Summary.Passed += 1
+	return nil, nil
+}
+
+// resolveProduct finds the upstream product by its mapped ID and then by its handle.
+func (h Product) resolveProduct(ctx context.Context, p *api.CollectionProductNode) string {
+	if newID, ok := h.IDMap.Resolve(engine.Product, p.ID); ok {
+		return newID
+	}
+	if product, err := h.Client.CheckProductByHandle(ctx, p.Handle); err == nil && product.ID != "" {
+		return product.ID
+	}
+	return ""
+}
@@ -1,6 +1,7 @@
 package customer
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path/filepath"
@@ -24,11 +25,31 @@ type Runner struct {
 	logger   *tlog.Logger
 	stats    map[engine.ResourceType]*runner.Summary
 	filters  *runner.RestoreFilter
+	idMap    *runner.IDMap
+	plan     *runner.Plan
+	migrate  *runner.Migration
 	isDryRun bool
 }
 
+// Option is a functional opt for Runner.
+type Option func(*Runner)
+
+// WithPlan records the changes the restore would make in the plan.
+func WithPlan(plan *runner.Plan) Option {
+	return func(r *Runner) {
+		r.plan = plan
+	}
+}
+
+// WithMigration resolves the references to store-specific resources on migration to another store.
+func WithMigration(m *runner.Migration) Option {
+	return func(r *Runner) {
+		r.migrate = m
+	}
+}
+
 // NewRunner constructs a new restore runner.
-func NewRunner(path string, eng *engine.Engine, client *api.GQLClient, logger *tlog.Logger, filters *runner.RestoreFilter, isDryRun bool) *Runner {
+func NewRunner(path string, eng *engine.Engine, client *api.GQLClient, logger *tlog.Logger, filters *runner.RestoreFilter, idMap *runner.IDMap, isDryRun bool, opts ...Option) *Runner {
 	rstEng := eng.Doer().(*engine.Restore)
 
 	stats := make(map[engine.ResourceType]*runner.Summary)
@@ -36,7 +57,7 @@ func NewRunner(path string, eng *engine.Engine, client *api.GQLClient, logger *t
 		stats[rt] = &runner.Summary{}
 	}
 
-	return &Runner{
+	rnr := Runner{
 		path:     path,
 		eng:      eng,
 		rstEng:   rstEng,
@@ -44,8 +65,19 @@ func NewRunner(path string, eng *engine.Engine, client *api.GQLClient, logger *t
 		logger:   logger,
 		stats:    stats,
 		filters:  filters,
+		idMap:    idMap,
 		isDryRun: isDryRun,
 	}
+
+	for _, opt := range opts {
+		opt(&rnr)
+	}
+	return &rnr
+}
+
+// FilterFields returns the fields customers can be filtered by on restore.
+func FilterFields() []string {
+	return handler.FilterFields.Names()
 }
 
 // Kind returns runner type; implements `runner.Runner` interface.
@@ -54,7 +86,7 @@ func (r *Runner) Kind() engine.ResourceType {
 }
 
 // Run executes customer restoration process; implements `runner.Runner` interface.
-func (r *Runner) Run() error {
+func (r *Runner) Run(ctx context.Context) error {
 	r.eng.Register(engine.Customer)
 	restoreStart := time.Now()
 
@@ -65,13 +97,20 @@ func (r *Runner) Run() error {
 		_ = r.restore()
 	}()
 
-	for res := range r.eng.Run(engine.Customer) {
+	for res := range r.eng.Run(ctx, engine.Customer) {
 		if res.Err != nil && !errors.Is(res.Err, engine.ErrSkipChildren) {
 			r.stats[res.ResourceType].Failed += 1
 			r.logger.Errorf("Failed to restore resource %s: %v\n", res.ResourceType, res.Err)
 		}
 	}
 
+	// Resources restored in an earlier run are skipped on resume.
+	for rt, st := range r.stats {
+		n := r.rstEng.Resumed(rt)
+		st.Count += n
+		st.Skipped += n
+	}
+
 	r.logger.V(tlog.VL3).Infof(
 		"Customer restore complete in %s",
 		time.Since(restoreStart),
@@ -115,16 +154,16 @@ func (r *Runner) restore() error {
 
 		switch filepath.Base(f.Path) {
 		case "customer.json":
-			customerFn := &handler.Customer{Client: r.client, File: f, Filter: r.filters, Logger: r.logger, Summary: r.stats[engine.Customer], DryRun: r.isDryRun}
+			customerFn := &handler.Customer{Client: r.client, File: f, Filter: r.filters, Logger: r.logger, Summary: r.stats[engine.Customer], IDMap: r.idMap, Plan: r.plan, Migration: r.migrate, DryRun: r.isDryRun}
 			resources[currentID][Customer] = append(
 				resources[currentID][Customer],
-				engine.NewResource(engine.Customer, r.path, customerFn),
+				engine.NewResource(engine.Customer, filepath.Dir(f.Path), customerFn),
 			)
 		case "customer_metafields.json":
-			metafieldFn := &handler.Metafield{Client: r.client, File: f, Logger: r.logger, Summary: r.stats[engine.CustomerMetaField], DryRun: r.isDryRun}
+			metafieldFn := &handler.Metafield{Client: r.client, File: f, Logger: r.logger, Summary: r.stats[engine.CustomerMetaField], Plan: r.plan, Migration: r.migrate, DryRun: r.isDryRun}
 			resources[currentID][Metafields] = append(
 				resources[currentID][Metafields],
-				engine.NewResource(engine.CustomerMetaField, r.path, metafieldFn),
+				engine.NewResource(engine.CustomerMetaField, filepath.Dir(f.Path), metafieldFn),
 			)
 
 		}
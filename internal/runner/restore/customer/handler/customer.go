@@ -1,30 +1,34 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"slices"
-	"strings"
+	"strconv"
 
 	"github.com/ankitpokhrel/shopctl"
 	"github.com/ankitpokhrel/shopctl/internal/api"
 	"github.com/ankitpokhrel/shopctl/internal/engine"
 	"github.com/ankitpokhrel/shopctl/internal/registry"
 	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/pkg/diff"
 	"github.com/ankitpokhrel/shopctl/pkg/tlog"
 	"github.com/ankitpokhrel/shopctl/schema"
 )
 
 type Customer struct {
-	Client  *api.GQLClient
-	Logger  *tlog.Logger
-	File    registry.File
-	Filter  *runner.RestoreFilter
-	Summary *runner.Summary
-	DryRun  bool
+	Client    *api.GQLClient
+	Logger    *tlog.Logger
+	File      registry.File
+	Filter    *runner.RestoreFilter
+	Summary   *runner.Summary
+	IDMap     *runner.IDMap
+	Plan      *runner.Plan
+	Migration *runner.Migration
+	DryRun    bool
 }
 
-func (h *Customer) Handle(data any) (any, error) {
+func (h *Customer) Handle(ctx context.Context, data any) (any, error) {
 	customerRaw, err := registry.ReadFileContents(h.File.Path)
 	if err != nil {
 		h.Logger.Error("Unable to read contents", "file", h.File.Path, "error", err)
@@ -40,35 +44,126 @@ func (h *Customer) Handle(data any) (any, error) {
 	}
 
 	// Filter customer.
-	if len(h.Filter.Filters) > 0 {
-		matched, err := matchesFilters(&customer, h.Filter)
-		if err != nil || !matched {
-			h.Summary.Skipped += 1
-			return nil, engine.ErrSkipChildren
-		}
+	if !FilterFields.Match(h.Filter, &customer) {
+		h.Summary.Skipped += 1
+		h.IDMap.Add(runner.IDMapping{Resource: engine.Customer, OldID: customer.ID, Key: customerKey(&customer), Action: runner.ActionSkipped})
+		return nil, engine.ErrSkipChildren
+	}
+
+	pl, err := planCustomer(ctx, &customer, h.Client, h.IDMap, h.Migration != nil)
+	if err != nil {
+		h.Summary.Failed += 1
+		return nil, err
+	}
+	err = h.Plan.Add(runner.PlanEntry{
+		Resource: engine.Customer, Key: customerKey(&customer), OldID: customer.ID, UpstreamID: pl.upstreamID,
+		Action: pl.action, Changes: pl.changes, Upstream: pl.upstream,
+	})
+	if err != nil {
+		h.Summary.Failed += 1
+		return nil, err
 	}
 
 	if h.DryRun {
+		h.Logger.V(tlog.VL2).Info("Customer sync planned", "id", customer.ID, "action", pl.action, "fields", diff.Fields(pl.changes))
 		h.Logger.V(tlog.VL3).Warn("Skipping customer sync")
 		h.Summary.Passed += 1
+		if pl.upstreamID != "" {
+			return pl.upstreamID, nil
+		}
 		return customer.ID, nil
 	}
-	res, err := createOrUpdateCustomer(&customer, h.Client, h.Logger)
+	id, action, err := createOrUpdateCustomer(ctx, &customer, pl, h.Client, h.Logger)
 	if err != nil {
 		h.Summary.Failed += 1
 		return nil, err
 	}
-	h.Summary.Passed += 1
-	return res.Customer.ID, nil
+	if action == runner.ActionUnchanged {
+		h.Summary.Unchanged += 1
+	} else {
+		h.Summary.Passed += 1
+	}
+	h.IDMap.Add(runner.IDMapping{Resource: engine.Customer, OldID: customer.ID, NewID: id, Key: customerKey(&customer), Action: action})
+	return id, nil
+}
+
+// customerPlan is the change to make to a customer in the store.
+type customerPlan struct {
+	upstreamID string
+	action     string
+	changes    []diff.Change
+	upstream   string
+	input      schema.CustomerInput
 }
 
-func createOrUpdateCustomer(customer *schema.Customer, client *api.GQLClient, lgr *tlog.Logger) (*api.CustomerSyncResponse, error) {
-	cust, _ := client.CheckCustomerByEmailOrPhoneOrID(
-		customer.Email,
-		customer.Phone,
-		shopctl.ExtractNumericID(customer.ID),
-	)
+// planCustomer compares the customer with its upstream state, if any, and plans the change to make.
+// On migration, the ID of the customer in the backup belongs to another store and is not looked up.
+func planCustomer(ctx context.Context, customer *schema.Customer, client *api.GQLClient, idMap *runner.IDMap, migrating bool) (*customerPlan, error) {
+	// Prefer the ID the customer was restored with earlier, if known.
+	id := shopctl.ExtractNumericID(customer.ID)
+	if migrating {
+		id = ""
+	}
+	if newID, ok := idMap.Resolve(engine.Customer, customer.ID); ok {
+		id = shopctl.ExtractNumericID(newID)
+	}
+
+	pl := customerPlan{action: runner.PlanCreate, input: getCustomerInput(customer)}
+	if id == "" && customerKey(customer) == "" {
+		return &pl, nil
+	}
+	cust, _ := client.CheckCustomerByEmailOrPhoneOrID(ctx, customer.Email, customer.Phone, id)
+	if cust == nil || cust.ID == "" {
+		return &pl, nil
+	}
+
+	current, err := client.GetCustomerByID(ctx, cust.ID)
+	if err != nil {
+		return nil, err
+	}
+	currentInput := getCustomerInput(current)
+	if pl.changes, err = diff.Compare(currentInput, pl.input); err != nil {
+		return nil, err
+	}
+	if pl.upstream, err = runner.Checksum(currentInput); err != nil {
+		return nil, err
+	}
 
+	pl.upstreamID = cust.ID
+	pl.action = runner.PlanUpdate
+	if len(pl.changes) == 0 {
+		pl.action = runner.PlanUnchanged
+	}
+	return &pl, nil
+}
+
+func createOrUpdateCustomer(ctx context.Context, customer *schema.Customer, pl *customerPlan, client *api.GQLClient, lgr *tlog.Logger) (string, string, error) {
+	input := pl.input
+
+	switch pl.action {
+	case runner.PlanUnchanged:
+		lgr.V(tlog.VL2).Info("Customer is unchanged, skipping update", "oldID", customer.ID, "upstreamID", pl.upstreamID)
+		return pl.upstreamID, runner.ActionUnchanged, nil
+	case runner.PlanUpdate:
+		input.ID = &pl.upstreamID
+
+		lgr.Warn("Customer already exists, updating", "oldID", customer.ID, "upstreamID", *input.ID, "fields", diff.Fields(pl.changes))
+		res, err := client.UpdateCustomer(ctx, input)
+		if err != nil {
+			return "", runner.ActionUpdated, err
+		}
+		return res.Customer.ID, runner.ActionUpdated, nil
+	}
+
+	lgr.Info("Creating customer", "id", customer.ID)
+	res, err := client.CreateCustomer(ctx, input)
+	if err != nil {
+		return "", runner.ActionCreated, err
+	}
+	return res.Customer.ID, runner.ActionCreated, nil
+}
+
+func getCustomerInput(customer *schema.Customer) schema.CustomerInput {
 	var addresses []any
 	for _, address := range customer.AddressesV2.Nodes {
 		if addressMap, ok := address.(map[string]any); ok {
@@ -97,7 +192,7 @@ func createOrUpdateCustomer(customer *schema.Customer, client *api.GQLClient, lg
 		}
 	}
 
-	input := schema.CustomerInput{
+	return schema.CustomerInput{
 		FirstName:             customer.FirstName,
 		LastName:              customer.LastName,
 		Email:                 customer.Email,
@@ -111,84 +206,57 @@ func createOrUpdateCustomer(customer *schema.Customer, client *api.GQLClient, lg
 		TaxExempt:             &customer.TaxExempt,
 		TaxExemptions:         customer.TaxExemptions,
 	}
-
-	if cust != nil && cust.ID != "" {
-		input.ID = &cust.ID
-
-		lgr.Warn("Customer already exists, updating", "oldID", customer.ID, "upstreamID", *input.ID)
-		return client.UpdateCustomer(input)
-	}
-
-	lgr.Info("Creating customer", "id", customer.ID)
-	return client.CreateCustomer(input)
 }
 
-//nolint:gocyclo
-func matchesFilters(customer *schema.Customer, rf *runner.RestoreFilter) (bool, error) {
-	containsAny := func(s []any, v any) bool { return slices.Contains(s, v) } //nolint:gocritic
-
-	results := []bool{}
-	for key, values := range rf.Filters {
-		switch strings.ToLower(key) {
-		case "id":
-			matched := slices.Contains(values, shopctl.ExtractNumericID(customer.ID))
-			results = append(results, matched)
-		case "email":
-			matched := false
-			if customer.Email != nil {
-				matched = slices.Contains(values, *customer.Email)
-			}
-			results = append(results, matched)
-		case "phone":
-			matched := slices.Contains(values, *customer.Phone)
-			results = append(results, matched)
-		case "firstname":
-			matched := false
-			if customer.FirstName != nil {
-				matched = slices.Contains(values, *customer.FirstName)
-			}
-			results = append(results, matched)
-		case "lastname":
-			matched := false
-			if customer.FirstName != nil {
-				matched = slices.Contains(values, *customer.LastName)
-			}
-			results = append(results, matched)
-		case "tags":
-			matched := false
-			for _, tag := range values {
-				if containsAny(customer.Tags, tag) {
-					matched = true
-					break
-				}
-			}
-			results = append(results, matched)
-		case "state":
-			matched := slices.Contains(values, strings.ToLower(string(customer.State)))
-			results = append(results, matched)
-		case "verifiedemail":
-			matched := slices.Contains(values, "true")
-			results = append(results, matched)
-		default:
-			return false, fmt.Errorf("unsupported filter key: %s", key)
-		}
+// customerKey returns the natural key of a customer.
+func customerKey(customer *schema.Customer) string {
+	if customer.Email != nil && *customer.Email != "" {
+		return *customer.Email
 	}
-
-	if len(results) == 0 {
-		return false, nil
+	if customer.Phone != nil {
+		return *customer.Phone
 	}
+	return ""
+}
 
-	// Combine results using separators
-	finalResult := results[0]
-	for i, sep := range rf.Separators {
-		switch strings.ToLower(sep) {
-		case "and":
-			finalResult = finalResult && results[i+1]
-		case "or":
-			finalResult = finalResult || results[i+1]
-		default:
-			return false, fmt.Errorf("unsupported separator: %s", sep)
+// FilterFields are the fields customers can be filtered by on restore.
+var FilterFields = runner.FilterFields[schema.Customer]{
+	"id": func(c *schema.Customer) []string {
+		return []string{shopctl.ExtractNumericID(c.ID), c.ID}
+	},
+	"email":          func(c *schema.Customer) []string { return runner.OptionalValue(c.Email) },
+	"phone":          func(c *schema.Customer) []string { return runner.OptionalValue(c.Phone) },
+	"first_name":     func(c *schema.Customer) []string { return runner.OptionalValue(c.FirstName) },
+	"firstname":      func(c *schema.Customer) []string { return runner.OptionalValue(c.FirstName) },
+	"last_name":      func(c *schema.Customer) []string { return runner.OptionalValue(c.LastName) },
+	"lastname":       func(c *schema.Customer) []string { return runner.OptionalValue(c.LastName) },
+	"tag":            func(c *schema.Customer) []string { return runner.StringValues(c.Tags) },
+	"tags":           func(c *schema.Customer) []string { return runner.StringValues(c.Tags) },
+	"state":          func(c *schema.Customer) []string { return []string{string(c.State)} },
+	"verified_email": func(c *schema.Customer) []string { return []string{strconv.FormatBool(c.VerifiedEmail)} },
+	"verifiedemail":  func(c *schema.Customer) []string { return []string{strconv.FormatBool(c.VerifiedEmail)} },
+	"accepts_marketing": func(c *schema.Customer) []string {
+		subscribed := c.EmailMarketingConsent != nil && c.EmailMarketingConsent.MarketingState == schema.CustomerEmailMarketingStateSubscribed
+		return []string{strconv.FormatBool(subscribed)}
+	},
+	"country": func(c *schema.Customer) []string {
+		if c.DefaultAddress == nil {
+			return nil
 		}
-	}
-	return finalResult, nil
+		out := runner.OptionalValue(c.DefaultAddress.Country)
+		if c.DefaultAddress.CountryCodeV2 != nil {
+			out = append(out, string(*c.DefaultAddress.CountryCodeV2))
+		}
+		return out
+	},
+	"orders_count": func(c *schema.Customer) []string { return []string{c.NumberOfOrders} },
+	"total_spent": func(c *schema.Customer) []string {
+		return []string{strconv.FormatFloat(c.AmountSpent.Amount, 'f', -1, 64)}
+	},
+	"customer_date": func(c *schema.Customer) []string { return []string{c.CreatedAt} },
+	"created_at":    func(c *schema.Customer) []string { return []string{c.CreatedAt} },
+	"updated_at":    func(c *schema.Customer) []string { return []string{c.UpdatedAt} },
+	"default": func(c *schema.Customer) []string {
+		return slices.Concat([]string{c.DisplayName}, runner.OptionalValue(c.Email), runner.OptionalValue(c.Phone))
+	},
 }
@@ -1,26 +1,31 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
 	"github.com/ankitpokhrel/shopctl"
 	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
 	"github.com/ankitpokhrel/shopctl/internal/registry"
 	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/pkg/diff"
 	"github.com/ankitpokhrel/shopctl/pkg/tlog"
 	"github.com/ankitpokhrel/shopctl/schema"
 )
 
 type Metafield struct {
-	Client  *api.GQLClient
-	Logger  *tlog.Logger
-	File    registry.File
-	Summary *runner.Summary
-	DryRun  bool
+	Client    *api.GQLClient
+	Logger    *tlog.Logger
+	File      registry.File
+	Summary   *runner.Summary
+	Plan      *runner.Plan
+	Migration *runner.Migration
+	DryRun    bool
 }
 
-func (h Metafield) Handle(data any) (any, error) {
+func (h Metafield) Handle(ctx context.Context, data any) (any, error) {
 	var realCustomerID string
 	if id, ok := data.(string); ok {
 		realCustomerID = id
@@ -45,6 +50,14 @@ func (h Metafield) Handle(data any) (any, error) {
 		return nil, nil
 	}
 
+	// Metafields that can't be resolved on migration are left untouched.
+	nodes, skipped, err := h.Migration.ResolveMetafields(ctx, engine.CustomerMetaField, meta.CustomerID, schema.MetafieldOwnerTypeCustomer, meta.Metafields.Nodes)
+	if err != nil {
+		h.Summary.Failed += 1
+		return nil, err
+	}
+	meta.Metafields.Nodes = nodes
+
 	keyme := func(namespace, key string) string {
 		return fmt.Sprintf("%s.%s", namespace, key)
 	}
@@ -54,6 +67,7 @@ func (h Metafield) Handle(data any) (any, error) {
 
 	// Get upstream metafields.
 	currentMetafields, _ := h.Client.GetCustomerMetaFieldsByEmailOrPhoneOrID(
+		ctx,
 		&meta.Email, &meta.Phone,
 		shopctl.ExtractNumericID(realCustomerID),
 	)
@@ -74,31 +88,77 @@ func (h Metafield) Handle(data any) (any, error) {
 
 		for id, cm := range currentMetafieldsMap {
 			if m, ok := backupMetafieldsMap[id]; ok {
+				changes, err := diff.Compare(getMetafieldFields(cm), getMetafieldFields(m))
+				if err != nil {
+					h.Summary.Failed += 1
+					return nil, err
+				}
+				entry := runner.PlanEntry{
+					Resource: engine.CustomerMetaField, Parent: meta.CustomerID, Key: id, OldID: m.ID, UpstreamID: cm.ID,
+					Action: runner.PlanUpdate, Changes: changes,
+				}
+				if len(changes) == 0 {
+					entry.Action = runner.PlanUnchanged
+				}
+				if err := h.Plan.Record(entry, getMetafieldFields(cm)); err != nil {
+					h.Summary.Failed += 1
+					return nil, err
+				}
+				if len(changes) == 0 {
+					h.Logger.V(tlog.VL3).Info("Customer metafield is unchanged, skipping", "namespace", m.Namespace, "key", m.Key)
+					continue
+				}
 				toAdd = append(toAdd, m)
-			} else {
+			} else if !skipped[id] {
+				err := h.Plan.Record(runner.PlanEntry{
+					Resource: engine.CustomerMetaField, Parent: meta.CustomerID, Key: id, UpstreamID: cm.ID, Action: runner.PlanDelete,
+				}, getMetafieldFields(cm))
+				if err != nil {
+					h.Summary.Failed += 1
+					return nil, err
+				}
 				toDelete = append(toDelete, cm)
 			}
 		}
 		for id, m := range backupMetafieldsMap {
 			if _, ok := currentMetafieldsMap[id]; !ok {
+				err := h.Plan.Add(runner.PlanEntry{
+					Resource: engine.CustomerMetaField, Parent: meta.CustomerID, Key: id, OldID: m.ID, Action: runner.PlanCreate,
+				})
+				if err != nil {
+					h.Summary.Failed += 1
+					return nil, err
+				}
 				toAdd = append(toAdd, m)
 			}
 		}
 	} else {
 		for _, m := range meta.Metafields.Nodes {
+			err := h.Plan.Add(runner.PlanEntry{
+				Resource: engine.CustomerMetaField, Parent: meta.CustomerID, Key: keyme(m.Namespace, m.Key), OldID: m.ID, Action: runner.PlanCreate,
+			})
+			if err != nil {
+				h.Summary.Failed += 1
+				return nil, err
+			}
 			toAdd = append(toAdd, &m)
 		}
 	}
 
 	attemptSync := func(cid string) error {
-		if _, err := h.handleCustomerMetaFieldsSet(cid, toAdd); err != nil {
+		if _, err := h.handleCustomerMetaFieldsSet(ctx, cid, toAdd); err != nil {
 			return err
 		}
-		if _, err := h.handleCustomerMetaFieldsDelete(cid, toDelete); err != nil {
+		if _, err := h.handleCustomerMetaFieldsDelete(ctx, cid, toDelete); err != nil {
 			return err
 		}
 		return nil
 	}
+	if len(toAdd) == 0 && len(toDelete) == 0 {
+		h.Logger.V(tlog.VL2).Info("Customer metafields are unchanged, skipping sync", "oldID", meta.CustomerID, "upstreamID", realCustomerID)
+		h.Summary.Unchanged += 1
+		return nil, nil
+	}
 	if h.DryRun {
 		h.Logger.V(tlog.VL2).Infof("Customer metafields to sync - add: %d, remove: %d", len(toAdd), len(toDelete))
 		h.Logger.V(tlog.VL3).Warn("Skipping customer metafields sync")
@@ -115,7 +175,7 @@ func (h Metafield) Handle(data any) (any, error) {
 	return nil, err
 }
 
-func (h Metafield) handleCustomerMetaFieldsSet(customerID string, toAdd []*schema.Metafield) (*api.MetafieldSetResponse, error) {
+func (h Metafield) handleCustomerMetaFieldsSet(ctx context.Context, customerID string, toAdd []*schema.Metafield) (*api.MetafieldSetResponse, error) {
 	if len(toAdd) == 0 {
 		return nil, nil
 	}
@@ -131,10 +191,10 @@ func (h Metafield) handleCustomerMetaFieldsSet(customerID string, toAdd []*schem
 		})
 	}
 	h.Logger.V(tlog.VL2).Info("Attempting to set customer metafields", "id", customerID)
-	return h.Client.SetMetafields(metafields)
+	return h.Client.SetMetafields(ctx, metafields)
 }
 
-func (h Metafield) handleCustomerMetaFieldsDelete(customerID string, toDelete []*schema.Metafield) (*api.MetafieldDeleteResponse, error) {
+func (h Metafield) handleCustomerMetaFieldsDelete(ctx context.Context, customerID string, toDelete []*schema.Metafield) (*api.MetafieldDeleteResponse, error) {
 	if len(toDelete) == 0 {
 		return nil, nil
 	}
@@ -148,5 +208,13 @@ func (h Metafield) handleCustomerMetaFieldsDelete(customerID string, toDelete []
 		})
 	}
 	h.Logger.V(tlog.VL2).Info("Attempting to delete customer metafields", "id", customerID)
-	return h.Client.DeleteMetafields(metafields)
+	return h.Client.DeleteMetafields(ctx, metafields)
+}
+
+// getMetafieldFields returns the fields of the metafield that are synced on update.
+func getMetafieldFields(m *schema.Metafield) map[string]any {
+	return map[string]any{
+		"value": m.Value,
+		"type":  m.Type,
+	}
 }
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+const fulfillmentSuccess = "SUCCESS"
+
+type Fulfillment struct {
+	Client  *api.GQLClient
+	Logger  *tlog.Logger
+	File    registry.File
+	Summary *runner.Summary
+	DryRun  bool
+}
+
+func (h Fulfillment) Handle(ctx context.Context, data any) (any, error) {
+	var realOrderID string
+	if id, ok := data.(string); ok {
+		realOrderID = id
+	} else {
+		return nil, fmt.Errorf("unable to figure out real order ID")
+	}
+
+	fulfillmentRaw, err := registry.ReadFileContents(h.File.Path)
+	if err != nil {
+		h.Logger.Error("Unable to read contents", "file", h.File.Path, "error", err)
+		return nil, err
+	}
+	h.Summary.Count += 1
+
+	var fulfillments api.OrderFulfillmentsData
+	if err = json.Unmarshal(fulfillmentRaw, &fulfillments); err != nil {
+		h.Logger.Error("Unable to marshal contents", "file", h.File.Path, "error", err)
+		h.Summary.Failed += 1
+		return nil, err
+	}
+
+	toCreate := make([]api.OrderFulfillmentNode, 0, len(fulfillments.Fulfillments))
+	for _, f := range fulfillments.Fulfillments {
+		if f.Status == fulfillmentSuccess {
+			toCreate = append(toCreate, f)
+		}
+	}
+	if len(toCreate) == 0 {
+		h.Summary.Passed += 1
+		return nil, nil
+	}
+
+	if h.DryRun {
+		h.Logger.V(tlog.VL2).Infof("Order fulfillments to create: %d", len(toCreate))
+		h.Logger.V(tlog.VL3).Warn("Skipping order fulfillments sync")
+		h.Summary.Passed += 1
+		return nil, nil
+	}
+
+	lineItems, err := h.mapLineItems(ctx, realOrderID)
+	if err != nil {
+		h.Logger.Error("Unable to map order line items", "oldID", fulfillments.OrderID, "upstreamID", realOrderID, "error", err)
+		h.Summary.Failed += 1
+		return nil, err
+	}
+
+	for _, f := range toCreate {
+		if err := h.createFulfillment(ctx, realOrderID, &f, lineItems); err != nil {
+			h.Logger.Error("Failed to create order fulfillment", "oldID", f.ID, "upstreamOrderID", realOrderID, "error", err)
+			h.Summary.Failed += 1
+			return nil, err
+		}
+	}
+	h.Summary.Passed += 1
+	return nil, nil
+}
+
+// mapLineItems maps line items in the backup to the line items of the
+// upstream order. Line items are created in the same order as in the backup.
+func (h Fulfillment) mapLineItems(ctx context.Context, orderID string) (map[string]string, error) {
+	backup, err := readLineItems(filepath.Dir(h.File.Path))
+	if err != nil {
+		return nil, err
+	}
+	upstream, err := h.Client.GetOrderLineItems(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldItems, newItems := backup.LineItems.Nodes, upstream.Data.Order.LineItems.Nodes
+	if len(oldItems) != len(newItems) {
+		return nil, fmt.Errorf("order has %d line items but the backup has %d", len(newItems), len(oldItems))
+	}
+
+	out := make(map[string]string, len(oldItems))
+	for i, li := range oldItems {
+		out[li.ID] = newItems[i].ID
+	}
+	return out, nil
+}
+
+func (h Fulfillment) createFulfillment(ctx context.Context, orderID string, f *api.OrderFulfillmentNode, lineItems map[string]string) error {
+	// Quantity to fulfill per upstream line item.
+	pending := make(map[string]int)
+	for _, fli := range f.FulfillmentLineItems.Nodes {
+		if newID, ok := lineItems[fli.LineItem.ID]; ok {
+			pending[newID] += fli.Quantity
+		}
+	}
+
+	fulfillmentOrders, err := h.Client.GetFulfillmentOrders(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	var byFulfillmentOrder []schema.FulfillmentOrderLineItemsInput
+	for _, fo := range fulfillmentOrders.FulfillmentOrders.Nodes {
+		var items []schema.FulfillmentOrderLineItemInput
+		for _, foli := range fo.LineItems.Nodes {
+			qty := min(pending[foli.LineItem.ID], foli.RemainingQuantity)
+			if qty <= 0 {
+				continue
+			}
+			pending[foli.LineItem.ID] -= qty
+			items = append(items, schema.FulfillmentOrderLineItemInput{ID: foli.ID, Quantity: qty})
+		}
+		if len(items) > 0 {
+			byFulfillmentOrder = append(byFulfillmentOrder, schema.FulfillmentOrderLineItemsInput{
+				FulfillmentOrderID:        fo.ID,
+				FulfillmentOrderLineItems: items,
+			})
+		}
+	}
+	if len(byFulfillmentOrder) == 0 {
+		h.Logger.V(tlog.VL2).Warn("Nothing left to fulfill, skipping", "fulfillment", f.ID, "upstreamOrderID", orderID)
+		return nil
+	}
+
+	notify := false
+	input := schema.FulfillmentInput{
+		LineItemsByFulfillmentOrder: byFulfillmentOrder,
+		NotifyCustomer:              &notify,
+	}
+	if len(f.TrackingInfo) > 0 {
+		input.TrackingInfo = &schema.FulfillmentTrackingInput{
+			Company: f.TrackingInfo[0].Company,
+			Number:  f.TrackingInfo[0].Number,
+			URL:     f.TrackingInfo[0].URL,
+		}
+	}
+
+	h.Logger.V(tlog.VL2).Info("Attempting to create order fulfillment", "id", orderID)
+	_, err = h.Client.CreateFulfillment(ctx, input)
+	return err
+}
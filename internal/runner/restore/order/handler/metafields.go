@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+type Metafield struct {
+	Client  *api.GQLClient
+	Logger  *tlog.Logger
+	File    registry.File
+	Summary *runner.Summary
+	DryRun  bool
+}
+
+func (h Metafield) Handle(ctx context.Context, data any) (any, error) {
+	var realOrderID string
+	if id, ok := data.(string); ok {
+		realOrderID = id
+	} else {
+		return nil, fmt.Errorf("unable to figure out real order ID")
+	}
+
+	metaRaw, err := registry.ReadFileContents(h.File.Path)
+	if err != nil {
+		h.Logger.Error("Unable to read contents", "file", h.File.Path, "error", err)
+		return nil, err
+	}
+	h.Summary.Count += 1
+
+	var meta api.OrderMetafieldsData
+	if err = json.Unmarshal(metaRaw, &meta); err != nil {
+		h.Logger.Error("Unable to marshal contents", "file", h.File.Path, "error", err)
+		h.Summary.Failed += 1
+		return nil, err
+	}
+	if len(meta.Metafields.Nodes) == 0 {
+		return nil, nil
+	}
+
+	if h.DryRun {
+		h.Logger.V(tlog.VL2).Infof("Order metafields to sync - add: %d", len(meta.Metafields.Nodes))
+		h.Logger.V(tlog.VL3).Warn("Skipping order metafields sync")
+		h.Summary.Passed += 1
+		return nil, nil
+	}
+
+	// Restored orders are never updated, so the metafields are only ever set.
+	metafields := make([]schema.MetafieldsSetInput, 0, len(meta.Metafields.Nodes))
+	for _, m := range meta.Metafields.Nodes {
+		metafields = append(metafields, schema.MetafieldsSetInput{
+			Namespace: &m.Namespace,
+			Key:       m.Key,
+			Value:     m.Value,
+			OwnerID:   realOrderID,
+			Type:      &m.Type,
+		})
+	}
+
+	h.Logger.V(tlog.VL2).Info("Attempting to set order metafields", "id", realOrderID)
+	if _, err := h.Client.SetMetafields(ctx, metafields); err != nil {
+		h.Logger.Error("Failed to sync order metafields", "oldID", meta.OrderID, "upstreamID", realOrderID)
+		h.Summary.Failed += 1
+		return nil, err
+	}
+	h.Summary.Passed += 1
+	return nil, nil
+}
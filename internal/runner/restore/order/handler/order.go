@@ -0,0 +1,370 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ankitpokhrel/shopctl"
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+const transactionSuccess = "SUCCESS"
+
+type Order struct {
+	Client  *api.GQLClient
+	Logger  *tlog.Logger
+	File    registry.File
+	Filter  *runner.RestoreFilter
+	Summary *runner.Summary
+	IDMap   *runner.IDMap
+	DryRun  bool
+}
+
+func (h *Order) Handle(ctx context.Context, data any) (any, error) {
+	orderRaw, err := registry.ReadFileContents(h.File.Path)
+	if err != nil {
+		h.Logger.Error("Unable to read contents", "file", h.File.Path, "error", err)
+		return nil, err
+	}
+	h.Summary.Count += 1
+
+	var order schema.Order
+	if err = json.Unmarshal(orderRaw, &order); err != nil {
+		h.Logger.Error("Unable to marshal contents", "file", h.File.Path, "error", err)
+		h.Summary.Failed += 1
+		return nil, err
+	}
+
+	// Filter order.
+	if !FilterFields.Match(h.Filter, &order) {
+		h.Summary.Skipped += 1
+		h.IDMap.Add(runner.IDMapping{Resource: engine.Order, OldID: order.ID, Key: order.Name, Action: runner.ActionSkipped})
+		return nil, engine.ErrSkipChildren
+	}
+
+	// Line items and transactions can only be set when the order is created,
+	// so they are restored along with the order instead of as child resources.
+	lineItems, err := readLineItems(filepath.Dir(h.File.Path))
+	if err != nil {
+		h.Logger.Error("Unable to read order line items", "file", h.File.Path, "error", err)
+		h.Summary.Failed += 1
+		return nil, err
+	}
+	transactions, err := readTransactions(filepath.Dir(h.File.Path))
+	if err != nil {
+		h.Logger.Error("Unable to read order transactions", "file", h.File.Path, "error", err)
+		h.Summary.Failed += 1
+		return nil, err
+	}
+
+	if h.DryRun {
+		h.Logger.V(tlog.VL2).Infof("Order %s: line items to restore: %d, transactions: %d", order.Name, len(lineItems.LineItems.Nodes), len(transactions.Transactions))
+		h.Logger.V(tlog.VL3).Warn("Skipping order sync")
+		h.Summary.Passed += 1
+		return order.ID, nil
+	}
+
+	// Historical orders can't be updated, so an order that was
+	// already restored or that exists upstream is left as is.
+	if upstreamID := h.findUpstreamOrder(ctx, &order); upstreamID != "" {
+		h.Logger.Warn("Order already exists, skipping", "oldID", order.ID, "upstreamID", upstreamID)
+		h.Summary.Skipped += 1
+		h.IDMap.Add(runner.IDMapping{Resource: engine.Order, OldID: order.ID, NewID: upstreamID, Key: order.Name, Action: runner.ActionSkipped})
+		return upstreamID, nil
+	}
+
+	input := h.buildInput(ctx, &order, lineItems, transactions)
+
+	// Restored orders shouldn't touch the inventory or notify the customers.
+	var (
+		inventoryBehaviour = "BYPASS"
+		notify             = false
+	)
+
+	h.Logger.Info("Creating order", "id", order.ID, "name", order.Name)
+	res, err := h.Client.CreateOrder(ctx, input, &schema.OrderCreateOptionsInput{
+		InventoryBehaviour:     &inventoryBehaviour,
+		SendReceipt:            &notify,
+		SendFulfillmentReceipt: &notify,
+	})
+	if err != nil {
+		h.Summary.Failed += 1
+		return nil, err
+	}
+	h.Summary.Passed += 1
+
+	h.IDMap.Add(runner.IDMapping{Resource: engine.Order, OldID: order.ID, NewID: res.Order.ID, Key: order.Name, Action: runner.ActionCreated})
+	// Line items are created in the same order as in the input.
+	if len(res.Order.LineItems.Nodes) == len(lineItems.LineItems.Nodes) {
+		for i, li := range lineItems.LineItems.Nodes {
+			h.IDMap.Add(runner.IDMapping{Resource: engine.OrderLineItem, OldID: li.ID, NewID: res.Order.LineItems.Nodes[i].ID, Key: li.Name, Action: runner.ActionCreated})
+		}
+	}
+	return res.Order.ID, nil
+}
+
+func (h *Order) findUpstreamOrder(ctx context.Context, order *schema.Order) string {
+	if newID, ok := h.IDMap.Resolve(engine.Order, order.ID); ok {
+		return newID
+	}
+	if order.Name == "" {
+		return ""
+	}
+	if o, err := h.Client.CheckOrderByName(ctx, order.Name); err == nil && o.ID != "" {
+		return o.ID
+	}
+	return ""
+}
+
+//nolint:gocyclo
+func (h *Order) buildInput(ctx context.Context, order *schema.Order, lineItems *api.OrderLineItemsData, transactions *api.OrderTransactionsData) schema.OrderCreateOrderInput {
+	input := schema.OrderCreateOrderInput{
+		BillingAddress:   toAddressInput(order.BillingAddress),
+		ClosedAt:         order.ClosedAt,
+		Currency:         &order.CurrencyCode,
+		Customer:         h.resolveCustomer(ctx, order),
+		Email:            order.Email,
+		Name:             &order.Name,
+		Note:             order.Note,
+		Phone:            order.Phone,
+		PoNumber:         order.PoNumber,
+		ShippingAddress:  toAddressInput(order.ShippingAddress),
+		SourceIdentifier: order.SourceIdentifier,
+		SourceName:       order.SourceName,
+		Tags:             order.Tags,
+		TaxesIncluded:    &order.TaxesIncluded,
+		Test:             &order.Test,
+	}
+	if order.ProcessedAt != "" {
+		input.ProcessedAt = &order.ProcessedAt
+	}
+	if order.PresentmentCurrencyCode != "" {
+		input.PresentmentCurrency = &order.PresentmentCurrencyCode
+	}
+
+	var attrs []schema.AttributeInput
+	if err := decode(order.CustomAttributes, &attrs); err == nil {
+		input.CustomAttributes = attrs
+	}
+
+	var taxLines []api.OrderTaxLine
+	if err := decode(order.TaxLines, &taxLines); err == nil {
+		input.TaxLines = toTaxLineInputs(taxLines)
+	}
+
+	var shippingLines []api.OrderShippingLine
+	if err := decode(order.ShippingLines.Nodes, &shippingLines); err == nil {
+		for _, sl := range shippingLines {
+			input.ShippingLines = append(input.ShippingLines, schema.OrderCreateShippingLineInput{
+				Code:     sl.Code,
+				PriceSet: toMoneyBagInput(sl.OriginalPriceSet),
+				Source:   sl.Source,
+				Title:    sl.Title,
+			})
+		}
+	}
+
+	for _, li := range lineItems.LineItems.Nodes {
+		item := schema.OrderCreateLineItemInput{
+			PriceSet:         toMoneyBagInput(li.OriginalUnitPriceSet),
+			Quantity:         li.Quantity,
+			RequiresShipping: &li.RequiresShipping,
+			Sku:              li.Sku,
+			TaxLines:         toTaxLineInputs(li.TaxLines),
+			Taxable:          &li.Taxable,
+			Title:            &li.Title,
+			VariantTitle:     li.VariantTitle,
+			Vendor:           li.Vendor,
+		}
+		if variantID := h.resolveVariant(ctx, &li); variantID != "" {
+			item.VariantID = &variantID
+		} else {
+			h.Logger.V(tlog.VL2).Warn("Unable to find the variant of the line item, restoring it as a custom item", "order", order.Name, "lineItem", li.Name)
+		}
+		input.LineItems = append(input.LineItems, item)
+	}
+
+	// Only the successful transactions affect the financial status of the order.
+	for _, t := range transactions.Transactions {
+		if t.Status != transactionSuccess {
+			continue
+		}
+		input.Transactions = append(input.Transactions, schema.OrderCreateOrderTransactionInput{
+			AmountSet:         *toMoneyBagInput(t.AmountSet),
+			AuthorizationCode: t.AuthorizationCode,
+			Gateway:           t.Gateway,
+			Kind:              &t.Kind,
+			ProcessedAt:       t.ProcessedAt,
+			Status:            &t.Status,
+			Test:              &t.Test,
+		})
+	}
+	return input
+}
+
+// resolveCustomer links the order to the customer it was placed by. The customer
+// is resolved from the ID map first and then by their email or phone.
+func (h *Order) resolveCustomer(ctx context.Context, order *schema.Order) *schema.OrderCreateCustomerInput {
+	if order.Customer == nil || order.Customer.ID == "" {
+		return nil
+	}
+	if newID, ok := h.IDMap.Resolve(engine.Customer, order.Customer.ID); ok {
+		return &schema.OrderCreateCustomerInput{ToAssociate: &schema.OrderCreateAssociateCustomerAttributesInput{ID: &newID}}
+	}
+
+	email, phone := order.Customer.Email, order.Customer.Phone
+	if (email == nil || *email == "") && (phone == nil || *phone == "") {
+		return nil
+	}
+	cust, err := h.Client.CheckCustomerByEmailOrPhoneOrID(ctx, email, phone, "")
+	if err != nil || cust.ID == "" {
+		h.Logger.V(tlog.VL2).Warn("Unable to find the customer of the order", "order", order.Name, "customer", order.Customer.ID)
+		return nil
+	}
+	return &schema.OrderCreateCustomerInput{ToAssociate: &schema.OrderCreateAssociateCustomerAttributesInput{ID: &cust.ID}}
+}
+
+// resolveVariant finds the upstream variant of a line item. The variant is
+// resolved from the ID map first and then by the product handle and the variant title.
+func (h *Order) resolveVariant(ctx context.Context, li *api.OrderLineItemNode) string {
+	if li.Variant == nil {
+		return ""
+	}
+	if newID, ok := h.IDMap.Resolve(engine.ProductVariant, li.Variant.ID); ok {
+		return newID
+	}
+	if li.Product == nil || li.Product.Handle == "" {
+		return ""
+	}
+	product, err := h.Client.CheckProductByHandle(ctx, li.Product.Handle)
+	if err != nil || product.ID == "" {
+		return ""
+	}
+	variant, err := h.Client.GetProductVariantByTitle(ctx, product.ID, li.Variant.Title, false)
+	if err != nil {
+		return ""
+	}
+	return variant.ID
+}
+
+func readLineItems(dir string) (*api.OrderLineItemsData, error) {
+	var lineItems api.OrderLineItemsData
+
+	raw, err := registry.ReadFileContents(filepath.Join(dir, engine.OrderLineItem.File()+".json"))
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &lineItems); err != nil {
+		return nil, err
+	}
+	if len(lineItems.LineItems.Nodes) == 0 {
+		return nil, fmt.Errorf("order doesn't have any line items")
+	}
+	return &lineItems, nil
+}
+
+func readTransactions(dir string) (*api.OrderTransactionsData, error) {
+	var transactions api.OrderTransactionsData
+
+	raw, err := registry.ReadFileContents(filepath.Join(dir, engine.OrderTransaction.File()+".json"))
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &transactions); err != nil {
+		return nil, err
+	}
+	return &transactions, nil
+}
+
+func toAddressInput(a *schema.MailingAddress) *schema.MailingAddressInput {
+	if a == nil {
+		return nil
+	}
+	return &schema.MailingAddressInput{
+		Address1:     a.Address1,
+		Address2:     a.Address2,
+		City:         a.City,
+		Company:      a.Company,
+		CountryCode:  a.CountryCodeV2,
+		FirstName:    a.FirstName,
+		LastName:     a.LastName,
+		Phone:        a.Phone,
+		ProvinceCode: a.ProvinceCode,
+		Zip:          a.Zip,
+	}
+}
+
+func toMoneyBagInput(m schema.MoneyBag) *schema.MoneyBagInput {
+	bag := schema.MoneyBagInput{
+		ShopMoney: schema.MoneyInput{
+			Amount:       strconv.FormatFloat(m.ShopMoney.Amount, 'f', -1, 64),
+			CurrencyCode: m.ShopMoney.CurrencyCode,
+		},
+	}
+	if m.PresentmentMoney.CurrencyCode != "" {
+		bag.PresentmentMoney = &schema.MoneyInput{
+			Amount:       strconv.FormatFloat(m.PresentmentMoney.Amount, 'f', -1, 64),
+			CurrencyCode: m.PresentmentMoney.CurrencyCode,
+		}
+	}
+	return &bag
+}
+
+func toTaxLineInputs(taxLines []api.OrderTaxLine) []schema.OrderCreateTaxLineInput {
+	out := make([]schema.OrderCreateTaxLineInput, 0, len(taxLines))
+	for _, t := range taxLines {
+		var rate float64
+		if t.Rate != nil {
+			rate = *t.Rate
+		}
+		out = append(out, schema.OrderCreateTaxLineInput{
+			PriceSet: toMoneyBagInput(t.PriceSet),
+			Rate:     rate,
+			Title:    t.Title,
+		})
+	}
+	return out
+}
+
+// decode converts loosely typed values of the generated schema to the given type.
+func decode(in any, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// FilterFields are the fields orders can be filtered by on restore.
+var FilterFields = runner.FilterFields[schema.Order]{
+	"id": func(o *schema.Order) []string {
+		return []string{shopctl.ExtractNumericID(o.ID), o.ID}
+	},
+	"name":  func(o *schema.Order) []string { return []string{o.Name, strings.TrimPrefix(o.Name, "#")} },
+	"email": func(o *schema.Order) []string { return runner.OptionalValue(o.Email) },
+	"tag":   func(o *schema.Order) []string { return runner.StringValues(o.Tags) },
+	"tags":  func(o *schema.Order) []string { return runner.StringValues(o.Tags) },
+	"financial_status": func(o *schema.Order) []string {
+		if o.DisplayFinancialStatus == nil {
+			return nil
+		}
+		return []string{string(*o.DisplayFinancialStatus)}
+	},
+	"fulfillment_status": func(o *schema.Order) []string { return []string{string(o.DisplayFulfillmentStatus)} },
+	"test":               func(o *schema.Order) []string { return []string{strconv.FormatBool(o.Test)} },
+	"created_at":         func(o *schema.Order) []string { return []string{o.CreatedAt} },
+	"processed_at":       func(o *schema.Order) []string { return []string{o.ProcessedAt} },
+	"updated_at":         func(o *schema.Order) []string { return []string{o.UpdatedAt} },
+	"default": func(o *schema.Order) []string {
+		return append([]string{o.Name}, runner.OptionalValue(o.Email)...)
+	},
+}
@@ -0,0 +1,408 @@
+package product
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/internal/runner/restore/product/handler"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+
+	bulkVariablesFile = "products.jsonl"
+
+	// The staged variables file of a bulk mutation can be at most 20MB.
+	// See https://shopify.dev/docs/api/usage/bulk-operations/imports#limitations
+	defaultMaxVariablesSize = 20 * 1000 * 1000
+
+	// Lines of a bulk operation result can be larger than the default buffer of the scanner.
+	maxBulkLineSize = 10 * 1024 * 1024
+)
+
+// BulkRunner is a product restore runner that restores products along with their options,
+// variants, metafields and media with productSet bulk mutations. Products are split into
+// chunks that fit in the size limit of the variables file of a bulk mutation.
+type BulkRunner struct {
+	path         string
+	client       *api.GQLClient
+	logger       *tlog.Logger
+	stats        map[engine.ResourceType]*runner.Summary
+	filters      *runner.RestoreFilter
+	idMap        *runner.IDMap
+	isDryRun     bool
+	pollInterval time.Duration
+	maxSize      int
+}
+
+// BulkOption is a functional opt for BulkRunner.
+type BulkOption func(*BulkRunner)
+
+// WithPollInterval sets the interval to poll the status of the bulk operation with.
+func WithPollInterval(d time.Duration) BulkOption {
+	return func(r *BulkRunner) {
+		r.pollInterval = d
+	}
+}
+
+// WithMaxVariablesSize sets the maximum size in bytes of the variables file of a bulk mutation.
+func WithMaxVariablesSize(n int) BulkOption {
+	return func(r *BulkRunner) {
+		r.maxSize = n
+	}
+}
+
+// NewBulkRunner constructs a new bulk restore runner.
+func NewBulkRunner(path string, client *api.GQLClient, logger *tlog.Logger, filters *runner.RestoreFilter, idMap *runner.IDMap, isDryRun bool, opts ...BulkOption) *BulkRunner {
+	stats := make(map[engine.ResourceType]*runner.Summary)
+	for _, rt := range engine.GetProductResourceTypes() {
+		stats[rt] = &runner.Summary{}
+	}
+
+	rnr := BulkRunner{
+		path:         path,
+		client:       client,
+		logger:       logger,
+		stats:        stats,
+		filters:      filters,
+		idMap:        idMap,
+		isDryRun:     isDryRun,
+		pollInterval: defaultPollInterval,
+		maxSize:      defaultMaxVariablesSize,
+	}
+
+	for _, opt := range opts {
+		opt(&rnr)
+	}
+	return &rnr
+}
+
+// Kind returns runner type; implements `runner.Runner` interface.
+func (r *BulkRunner) Kind() engine.ResourceType {
+	return engine.Product
+}
+
+// Stats returns runner stats.
+func (r *BulkRunner) Stats() map[engine.ResourceType]*runner.Summary {
+	return r.stats
+}
+
+// Run executes product restoration with a bulk mutation; implements `runner.Runner` interface.
+func (r *BulkRunner) Run(ctx context.Context) error {
+	restoreStart := time.Now()
+
+	products, err := r.collect()
+	if err != nil {
+		return err
+	}
+	if len(products) == 0 {
+		return nil
+	}
+	if r.stats[engine.ProductInventory].Count > 0 {
+		r.logger.Warn("Inventory quantities are not restored in bulk, run the import without --bulk to restore them")
+	}
+
+	if r.isDryRun {
+		r.logger.V(tlog.VL2).Infof("Products to sync in bulk: %d", len(products))
+		r.logger.V(tlog.VL3).Warn("Skipping bulk product sync")
+		for _, p := range products {
+			r.record(p, nil)
+		}
+		return nil
+	}
+
+	if err := r.restore(ctx, products); err != nil {
+		return err
+	}
+
+	r.logger.V(tlog.VL3).Infof(
+		"Product bulk restore complete in %s",
+		time.Since(restoreStart),
+	)
+	return nil
+}
+
+// collect reads the products to restore from the backup dir.
+func (r *BulkRunner) collect() ([]*handler.BulkProduct, error) {
+	foundFiles, err := registry.GetAllInDir(r.path, ".json")
+	if err != nil {
+		return nil, err
+	}
+
+	byDir := make(map[string]map[string]string)
+	for f := range foundFiles {
+		if f.Err != nil {
+			r.logger.Warn("Skipping file due to read err", "file", f.Path, "error", f.Err)
+			continue
+		}
+		dir := filepath.Dir(f.Path)
+		if _, ok := byDir[dir]; !ok {
+			byDir[dir] = make(map[string]string)
+		}
+		byDir[dir][filepath.Base(f.Path)] = f.Path
+	}
+
+	// Products are restored in a stable order so that lines of
+	// the result can be matched back to the products.
+	dirs := make([]string, 0, len(byDir))
+	for dir, files := range byDir {
+		if _, ok := files["product.json"]; ok {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+
+	products := make([]*handler.BulkProduct, 0, len(dirs))
+	for _, dir := range dirs {
+		r.stats[engine.Product].Count += 1
+
+		p, err := handler.NewBulkProduct(byDir[dir], r.filters, r.idMap)
+		if err != nil {
+			r.logger.Error("Unable to read product", "dir", dir, "error", err)
+			r.stats[engine.Product].Failed += 1
+			continue
+		}
+		if p == nil {
+			r.stats[engine.Product].Skipped += 1
+			continue
+		}
+		for rt := range p.Files {
+			r.stats[rt].Count += 1
+		}
+		products = append(products, p)
+	}
+	return products, nil
+}
+
+// restore streams the variables of the products into chunks that fit in the size limit and restores
+// each chunk with its own bulk operation, in sequence. If a chunk can't be restored, the products of
+// that chunk and the chunks after it are recorded as failed.
+func (r *BulkRunner) restore(ctx context.Context, products []*handler.BulkProduct) error {
+	var (
+		chunk  bytes.Buffer
+		queued = make([]*handler.BulkProduct, 0, len(products))
+		offset int
+	)
+
+	fail := func(products []*handler.BulkProduct, err error) {
+		for _, p := range products {
+			r.record(p, err)
+		}
+	}
+
+	for i, p := range products {
+		line, err := json.Marshal(p.Variables)
+		if err != nil {
+			r.record(p, err)
+			continue
+		}
+		line = append(line, '\n')
+		if len(line) > r.maxSize {
+			r.record(p, fmt.Errorf("product is larger than the bulk mutation limit of %d bytes", r.maxSize))
+			continue
+		}
+
+		if chunk.Len()+len(line) > r.maxSize {
+			if err := r.restoreChunk(ctx, &chunk, queued, offset); err != nil {
+				fail(queued[offset:], err)
+				fail(products[i:], err)
+				return err
+			}
+			chunk.Reset()
+			offset = len(queued)
+		}
+		chunk.Write(line)
+		queued = append(queued, p)
+	}
+
+	if chunk.Len() == 0 {
+		return nil
+	}
+	if err := r.restoreChunk(ctx, &chunk, queued, offset); err != nil {
+		fail(queued[offset:], err)
+		return err
+	}
+	return nil
+}
+
+// restoreChunk uploads the variables of the products from the offset onwards and restores them
+// with a bulk mutation. Line numbers of the result are relative to the start of the chunk.
+func (r *BulkRunner) restoreChunk(ctx context.Context, variables *bytes.Buffer, products []*handler.BulkProduct, offset int) error {
+	count := len(products) - offset
+
+	method := schema.StagedUploadHttpMethodTypePost
+	target, err := r.client.CreateStagedUpload(ctx, schema.StagedUploadInput{
+		Resource:   schema.StagedUploadTargetGenerateUploadResourceBulkMutationVariables,
+		Filename:   bulkVariablesFile,
+		MimeType:   "text/jsonl",
+		HttpMethod: &method,
+	})
+	if err != nil {
+		return err
+	}
+	if err := r.client.Upload(ctx, target, bulkVariablesFile, variables, int64(variables.Len())); err != nil {
+		return err
+	}
+	r.logger.V(tlog.VL2).Infof("Variables of %d products were uploaded to %s", count, target.Path())
+
+	op, err := r.client.RunBulkMutation(ctx, api.ProductSetBulkMutation, target.Path())
+	if err != nil {
+		return err
+	}
+	r.logger.V(tlog.VL1).Infof("Bulk operation %s was submitted for %d products", op.ID, count)
+
+	startedAt := op.CreatedAt
+	if op, err = runner.WaitBulkOperation(ctx, r.client, op.ID, r.pollInterval, r.logger); err != nil {
+		return err
+	}
+	if op.URL == nil {
+		return fmt.Errorf("bulk operation %s didn't return any result", op.ID)
+	}
+
+	res, err := r.client.DownloadBulkResult(ctx, *op.URL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Close() }()
+
+	r.parse(res, products, offset, startedAt)
+	return nil
+}
+
+// parse reads the result of the bulk mutation of the products from the offset onwards. Each line of
+// the result refers to the line of the variables file it was created from with `__lineNumber`.
+// Products without a valid line in the result are recorded as failed.
+func (r *BulkRunner) parse(rd io.Reader, products []*handler.BulkProduct, offset int, startedAt string) {
+	seen := make(map[int]bool, len(products)-offset)
+
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxBulkLineSize)
+
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var out api.ProductSetBulkResult
+		if err := json.Unmarshal(scanner.Bytes(), &out); err != nil {
+			r.logger.Warn("Unable to decode line in bulk operation result", "error", err)
+			continue
+		}
+		if out.LineNumber < 0 || offset+out.LineNumber >= len(products) {
+			r.logger.Warn("Unexpected line in bulk operation result", "line", out.LineNumber)
+			continue
+		}
+		p := products[offset+out.LineNumber]
+		seen[out.LineNumber] = true
+
+		set := out.Data.ProductSet
+		switch {
+		case len(out.Errors) > 0:
+			r.record(p, out.Errors)
+		case len(set.UserErrors) > 0:
+			r.record(p, set.UserErrors)
+		case set.Product == nil:
+			r.record(p, fmt.Errorf("product was not returned"))
+		default:
+			r.record(p, nil)
+
+			// Products created by the operation were created after the operation started.
+			action := runner.ActionUpdated
+			if set.Product.CreatedAt >= startedAt {
+				action = runner.ActionCreated
+			}
+			r.idMap.Add(runner.IDMapping{Resource: engine.Product, OldID: p.Product.ID, NewID: set.Product.ID, Key: p.Product.Handle, Action: action})
+
+			// Variants are matched by their option values as titles are not unique, or by their SKU.
+			var (
+				byOptions = make(map[string]string, len(set.Product.Variants.Nodes))
+				bySku     = make(map[string]string, len(set.Product.Variants.Nodes))
+			)
+			for _, v := range set.Product.Variants.Nodes {
+				byOptions[variantKey(v.SelectedOptions)] = v.ID
+				if v.Sku != nil && *v.Sku != "" {
+					bySku[*v.Sku] = v.ID
+				}
+			}
+			for _, v := range p.Variants {
+				newID, ok := byOptions[variantKey(selectedOptions(v.SelectedOptions))]
+				if !ok && v.Sku != nil && *v.Sku != "" {
+					newID, ok = bySku[*v.Sku]
+				}
+				if !ok {
+					r.logger.Warn("Unable to match restored variant, skipping its ID mapping", "productID", p.Product.ID, "variantID", v.ID, "title", v.Title)
+					continue
+				}
+				r.idMap.Add(runner.IDMapping{Resource: engine.ProductVariant, OldID: v.ID, NewID: newID, Key: v.Title, Action: action})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		r.logger.Error("Unable to read bulk operation result", "error", err)
+	}
+
+	for i, p := range products[offset:] {
+		if !seen[i] {
+			r.record(p, fmt.Errorf("product is missing in the bulk operation result"))
+		}
+	}
+}
+
+// variantKey identifies a variant within its product by its option values, e.g. Color:Red/Size:M.
+func variantKey(options []api.SelectedOption) string {
+	parts := make([]string, 0, len(options))
+	for _, o := range options {
+		parts = append(parts, o.Name+":"+o.Value)
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, "/")
+}
+
+// selectedOptions decodes the option values of a variant in the backup.
+func selectedOptions(options []any) []api.SelectedOption {
+	data, err := json.Marshal(options)
+	if err != nil {
+		return nil
+	}
+	var out []api.SelectedOption
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// record updates the stats of the product and the resources restored along with it.
+func (r *BulkRunner) record(p *handler.BulkProduct, err error) {
+	if err != nil {
+		r.logger.Error("Failed to restore product", "oldID", p.Product.ID, "handle", p.Product.Handle, "error", err)
+	}
+
+	for _, rt := range []engine.ResourceType{engine.Product, engine.ProductOption, engine.ProductVariant, engine.ProductMetaField, engine.ProductMedia, engine.ProductInventory} {
+		if rt != engine.Product && !p.Files[rt] {
+			continue
+		}
+		switch {
+		case rt == engine.ProductInventory:
+			r.stats[rt].Skipped += 1
+		case err != nil:
+			r.stats[rt].Failed += 1
+		default:
+			r.stats[rt].Passed += 1
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package product
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/config"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+func TestBulkRunner_Run(t *testing.T) {
+	t.Setenv("SHOPIFY_ACCESS_TOKEN", "12345")
+
+	path := "./testdata/.tmp"
+	files := map[string]string{
+		"products/1/product.json":            `{"id":"gid://shopify/Product/1","handle":"shirt","title":"Shirt","status":"ACTIVE","tags":["premium"],"options":[{"name":"Size","position":1,"optionValues":[{"name":"S"},{"name":"M"},{"name":"L"}]}]}`,
+		"products/1/product_variants.json":   `{"id":"gid://shopify/Product/1","variants":{"nodes":[{"id":"gid://shopify/ProductVariant/11","title":"Cotton","price":"10.00","selectedOptions":[{"name":"Size","value":"S","optionValue":{"id":"1"}}]},{"id":"gid://shopify/ProductVariant/12","title":"Cotton","price":"10.00","selectedOptions":[{"name":"Size","value":"M","optionValue":{"id":"2"}}]},{"id":"gid://shopify/ProductVariant/13","title":"Cotton","price":"10.00","selectedOptions":[{"name":"Size","value":"L","optionValue":{"id":"3"}}]}]}}`,
+		"products/1/product_metafields.json": `{"id":"gid://shopify/Product/1","metafields":{"nodes":[{"namespace":"custom","key":"material","value":"cotton","type":"single_line_text_field"}]}}`,
+		"products/1/product_media.json":      `{"id":"gid://shopify/Product/1","media":{"nodes":[{"id":"gid://shopify/MediaImage/21","mediaContentType":"IMAGE","preview":{"image":{"url":"https://cdn.shopify.com/front.jpg","altText":"Front"}}},{"id":"gid://shopify/Video/22","mediaContentType":"VIDEO","preview":{"image":{"url":"https://cdn.shopify.com/preview.jpg"}},"originalSource":{"url":"https://cdn.shopify.com/videos/demo.mp4","mimeType":"video/mp4"}},{"id":"gid://shopify/ExternalVideo/23","mediaContentType":"EXTERNAL_VIDEO","preview":{"image":{"url":"https://i.ytimg.com/preview.jpg"}}}]}}`,
+		"products/1/product_inventory.json":  `{"id":"gid://shopify/Product/1","variants":{"nodes":[]}}`,
+		"products/2/product.json":            `{"id":"gid://shopify/Product/2","handle":"mug","title":"Mug","status":"ACTIVE","tags":["premium"]}`,
+		"products/3/product.json":            `{"id":"gid://shopify/Product/3","handle":"cap","title":"Cap","status":"DRAFT","tags":[]}`,
+	}
+	for name, content := range files {
+		loc := filepath.Join(path, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(loc), 0o755))
+		assert.NoError(t, os.WriteFile(loc, []byte(content), 0o644))
+	}
+
+	var (
+		server   *httptest.Server
+		uploaded []api.ProductSetVariables
+	)
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/upload":
+			// The form is streamed with a known length instead of a chunked body.
+			assert.Positive(t, r.ContentLength)
+			assert.Empty(t, r.TransferEncoding)
+			assert.Equal(t, "tmp/bulk/products.jsonl", r.FormValue("key"))
+			file, _, err := r.FormFile("file")
+			assert.NoError(t, err)
+
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				var v api.ProductSetVariables
+				assert.NoError(t, json.Unmarshal(scanner.Bytes(), &v))
+				uploaded = append(uploaded, v)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case "/result.jsonl":
+			_, _ = w.Write([]byte(`{"data":{"productSet":{"product":{"id":"gid://shopify/Product/101","createdAt":"2025-05-01T10:00:01Z","variants":{"nodes":[{"id":"gid://shopify/ProductVariant/112","title":"Cotton","selectedOptions":[{"name":"Size","value":"M"}]},{"id":"gid://shopify/ProductVariant/111","title":"Cotton","selectedOptions":[{"name":"Size","value":"S"}]}]}},"userErrors":[]}},"__lineNumber":0}
+{"data":{"productSet":{"product":null,"userErrors":[{"field":["input","title"],"message":"Title is invalid"}]}},"__lineNumber":1}
+`))
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		assert.NoError(t, json.Unmarshal(body, &req))
+
+		var resp string
+		switch {
+		case strings.Contains(req.Query, "stagedUploadsCreate"):
+			resp = `{"data":{"stagedUploadsCreate":{"stagedTargets":[{"url":"` + server.URL + `/upload","parameters":[{"name":"key","value":"tmp/bulk/products.jsonl"}]}],"userErrors":[]}}}`
+		case strings.Contains(req.Query, "bulkOperationRunMutation"):
+			assert.Equal(t, "tmp/bulk/products.jsonl", req.Variables["stagedUploadPath"])
+			assert.Contains(t, req.Variables["mutation"], "productSet")
+			resp = `{"data":{"bulkOperationRunMutation":{"bulkOperation":{"id":"gid://shopify/BulkOperation/2","status":"CREATED","createdAt":"2025-05-01T10:00:00Z"},"userErrors":[]}}}`
+		case strings.Contains(req.Query, "GetBulkOperation"):
+			resp = `{"data":{"node":{"id":"gid://shopify/BulkOperation/2","status":"COMPLETED","objectCount":"2","url":"` + server.URL + `/result.jsonl"}}}`
+		default:
+			t.Fatalf("unexpected query: %s", req.Query)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(resp))
+	}))
+	defer server.Close()
+
+	client := api.NewGQLClient(&config.StoreContext{Store: "teststore.example.com", Alias: "test"}, api.WithServer(server.URL))
+	idMap := runner.NewIDMap()
+	filters, err := runner.NewRestoreFilter("tag:premium", FilterFields())
+	assert.NoError(t, err)
+
+	rnr := NewBulkRunner(path, client, tlog.New(tlog.VerboseLevel(tlog.VL1), true), filters, idMap, false, WithPollInterval(time.Millisecond))
+	assert.NoError(t, rnr.Run(context.Background()))
+
+	// Products not matching the filters are not uploaded.
+	assert.Len(t, uploaded, 2)
+	assert.Equal(t, "shirt", *uploaded[0].Identifier.Handle)
+	assert.Len(t, uploaded[0].Input.Variants, 3)
+	assert.Len(t, uploaded[0].Input.Metafields, 1)
+	assert.Len(t, uploaded[0].Input.ProductOptions, 1)
+	// Videos are uploaded from their original file and media without a source are skipped.
+	assert.Len(t, uploaded[0].Input.Files, 2)
+	assert.Equal(t, "https://cdn.shopify.com/front.jpg", *uploaded[0].Input.Files[0].OriginalSource)
+	assert.Equal(t, "Front", *uploaded[0].Input.Files[0].Alt)
+	assert.Equal(t, schema.FileContentTypeVideo, *uploaded[0].Input.Files[1].ContentType)
+	assert.Equal(t, "https://cdn.shopify.com/videos/demo.mp4", *uploaded[0].Input.Files[1].OriginalSource)
+	assert.Equal(t, "mug", *uploaded[1].Identifier.Handle)
+
+	stats := rnr.Stats()
+	assert.Equal(t, runner.Summary{Count: 3, Passed: 1, Failed: 1, Skipped: 1}, *stats[engine.Product])
+	assert.Equal(t, runner.Summary{Count: 1, Passed: 1}, *stats[engine.ProductOption])
+	assert.Equal(t, runner.Summary{Count: 1, Passed: 1}, *stats[engine.ProductVariant])
+	assert.Equal(t, runner.Summary{Count: 1, Passed: 1}, *stats[engine.ProductMetaField])
+	assert.Equal(t, runner.Summary{Count: 1, Skipped: 1}, *stats[engine.ProductInventory])
+
+	id, ok := idMap.Resolve(engine.Product, "gid://shopify/Product/1")
+	assert.True(t, ok)
+	assert.Equal(t, "gid://shopify/Product/101", id)
+
+	// Variants with the same title are matched by their option values.
+	id, ok = idMap.Resolve(engine.ProductVariant, "gid://shopify/ProductVariant/11")
+	assert.True(t, ok)
+	assert.Equal(t, "gid://shopify/ProductVariant/111", id)
+
+	id, ok = idMap.Resolve(engine.ProductVariant, "gid://shopify/ProductVariant/12")
+	assert.True(t, ok)
+	assert.Equal(t, "gid://shopify/ProductVariant/112", id)
+
+	// Variants that are missing in the result are not mapped.
+	_, ok = idMap.Resolve(engine.ProductVariant, "gid://shopify/ProductVariant/13")
+	assert.False(t, ok)
+
+	// Clean up.
+	assert.NoError(t, os.RemoveAll(path))
+}
+
+func TestBulkRunner_RunInChunks(t *testing.T) {
+	t.Setenv("SHOPIFY_ACCESS_TOKEN", "12345")
+
+	path := "./testdata/.tmp"
+	files := map[string]string{
+		"products/1/product.json":          `{"id":"gid://shopify/Product/1","handle":"shirt","title":"Shirt","status":"ACTIVE","tags":[]}`,
+		"products/1/product_variants.json": `{"id":"gid://shopify/Product/1","variants":{"nodes":[{"id":"gid://shopify/ProductVariant/11","title":"Default Title","sku":"SH-1","price":"10.00","selectedOptions":[]}]}}`,
+		"products/2/product.json":          `{"id":"gid://shopify/Product/2","handle":"mug","title":"Mug","status":"ACTIVE","tags":[]}`,
+		"products/3/product.json":          `{"id":"gid://shopify/Product/3","handle":"cap","title":"Cap","status":"ACTIVE","tags":[]}`,
+	}
+	for name, content := range files {
+		loc := filepath.Join(path, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(loc), 0o755))
+		assert.NoError(t, os.WriteFile(loc, []byte(content), 0o644))
+	}
+
+	// Each chunk has its own result where line numbers start from 0 again.
+	results := []string{
+		`{"data":{"productSet":{"product":{"id":"gid://shopify/Product/101","createdAt":"2025-05-01T10:00:01Z","variants":{"nodes":[{"id":"gid://shopify/ProductVariant/111","title":"Default Title","sku":"SH-1","selectedOptions":[]}]}},"userErrors":[]}},"__lineNumber":0}`,
+		`{"data":{"productSet":{"product":{"id":"gid://shopify/Product/103","createdAt":"2025-05-01T10:00:01Z","variants":{"nodes":[]}},"userErrors":[]}},"__lineNumber":1}
+{"data":{"productSet":{"product":{"id":"gid://shopify/Product/102","createdAt":"2025-05-01T10:00:01Z","variants":{"nodes":[]}},"userErrors":[]}},"__lineNumber":0}`,
+	}
+
+	var (
+		server  *httptest.Server
+		uploads [][]string
+	)
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/upload":
+			file, _, err := r.FormFile("file")
+			assert.NoError(t, err)
+
+			var handles []string
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				var v api.ProductSetVariables
+				assert.NoError(t, json.Unmarshal(scanner.Bytes(), &v))
+				handles = append(handles, *v.Identifier.Handle)
+			}
+			uploads = append(uploads, handles)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case "/result.jsonl":
+			_, _ = w.Write([]byte(results[len(uploads)-1] + "\n"))
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		var req struct {
+			Query string `json:"query"`
+		}
+		assert.NoError(t, json.Unmarshal(body, &req))
+
+		var resp string
+		switch {
+		case strings.Contains(req.Query, "stagedUploadsCreate"):
+			resp = `{"data":{"stagedUploadsCreate":{"stagedTargets":[{"url":"` + server.URL + `/upload","parameters":[{"name":"key","value":"tmp/bulk/products.jsonl"}]}],"userErrors":[]}}}`
+		case strings.Contains(req.Query, "bulkOperationRunMutation"):
+			resp = `{"data":{"bulkOperationRunMutation":{"bulkOperation":{"id":"gid://shopify/BulkOperation/2","status":"CREATED","createdAt":"2025-05-01T10:00:00Z"},"userErrors":[]}}}`
+		case strings.Contains(req.Query, "GetBulkOperation"):
+			resp = `{"data":{"node":{"id":"gid://shopify/BulkOperation/2","status":"COMPLETED","objectCount":"1","url":"` + server.URL + `/result.jsonl"}}}`
+		default:
+			t.Fatalf("unexpected query: %s", req.Query)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(resp))
+	}))
+	defer server.Close()
+
+	client := api.NewGQLClient(&config.StoreContext{Store: "teststore.example.com", Alias: "test"}, api.WithServer(server.URL))
+	idMap := runner.NewIDMap()
+
+	// The variables of the shirt fill the first chunk, and the mug and the cap fit in the second one.
+	rnr := NewBulkRunner(path, client, tlog.New(tlog.VerboseLevel(tlog.VL1), true), nil, idMap, false, WithPollInterval(time.Millisecond), WithMaxVariablesSize(460))
+	assert.NoError(t, rnr.Run(context.Background()))
+
+	assert.Equal(t, [][]string{{"shirt"}, {"mug", "cap"}}, uploads)
+	assert.Equal(t, runner.Summary{Count: 3, Passed: 3}, *rnr.Stats()[engine.Product])
+
+	for old, upstream := range map[string]string{
+		"gid://shopify/Product/1": "gid://shopify/Product/101",
+		"gid://shopify/Product/2": "gid://shopify/Product/102",
+		"gid://shopify/Product/3": "gid://shopify/Product/103",
+	} {
+		id, ok := idMap.Resolve(engine.Product, old)
+		assert.True(t, ok)
+		assert.Equal(t, upstream, id)
+	}
+	id, ok := idMap.Resolve(engine.ProductVariant, "gid://shopify/ProductVariant/11")
+	assert.True(t, ok)
+	assert.Equal(t, "gid://shopify/ProductVariant/111", id)
+
+	// Clean up.
+	assert.NoError(t, os.RemoveAll(path))
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"encoding/json"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+// BulkProduct is a product to restore with a productSet bulk mutation.
+type BulkProduct struct {
+	Product   schema.Product
+	Variants  []schema.ProductVariant
+	Files     map[engine.ResourceType]bool
+	Variables api.ProductSetVariables
+}
+
+// NewBulkProduct builds the productSet variables from the exported files of a product. Files are
+// keyed by their base name. It returns nil if the product doesn't match the restore filters.
+//
+// The product is identified by its handle unless it can be resolved from the ID map, e.g. if
+// the handle was changed. Inventory quantities are not part of the mutation.
+func NewBulkProduct(files map[string]string, filter *runner.RestoreFilter, idMap *runner.IDMap) (*BulkProduct, error) {
+	p := BulkProduct{
+		Files: make(map[engine.ResourceType]bool),
+	}
+
+	if err := readJSON(files["product.json"], &p.Product); err != nil {
+		return nil, err
+	}
+	if !FilterFields.Match(filter, &p.Product) {
+		return nil, nil
+	}
+
+	input := getProductSetInput(&p.Product)
+	if len(input.ProductOptions) > 0 {
+		p.Files[engine.ProductOption] = true
+	}
+
+	if path, ok := files["product_variants.json"]; ok {
+		var variants api.ProductVariantData
+		if err := readJSON(path, &variants); err != nil {
+			return nil, err
+		}
+		p.Variants = variants.Variants.Nodes
+		p.Files[engine.ProductVariant] = true
+
+		for _, v := range variants.Variants.Nodes {
+			input.Variants = append(input.Variants, schema.ProductVariantSetInput{
+				Barcode:            v.Barcode,
+				CompareAtPrice:     v.CompareAtPrice,
+				InventoryPolicy:    &v.InventoryPolicy,
+				InventoryItem:      getInventoryItem(&v),
+				OptionValues:       getOptions(v.SelectedOptions),
+				Position:           &v.Position,
+				Price:              &v.Price,
+				Taxable:            &v.Taxable,
+				TaxCode:            v.TaxCode,
+				RequiresComponents: &v.RequiresComponents,
+			})
+		}
+	}
+
+	if path, ok := files["product_metafields.json"]; ok {
+		var meta api.ProductMetafieldsData
+		if err := readJSON(path, &meta); err != nil {
+			return nil, err
+		}
+		p.Files[engine.ProductMetaField] = true
+
+		for _, m := range meta.Metafields.Nodes {
+			input.Metafields = append(input.Metafields, schema.MetafieldInput{
+				Namespace: &m.Namespace,
+				Key:       &m.Key,
+				Value:     &m.Value,
+				Type:      &m.Type,
+			})
+		}
+	}
+
+	if path, ok := files["product_media.json"]; ok {
+		var media api.ProductMediaData
+		if err := readJSON(path, &media); err != nil {
+			return nil, err
+		}
+		p.Files[engine.ProductMedia] = true
+
+		for _, m := range media.Media.Nodes {
+			// Videos and 3D models are sent with their original file instead of the preview
+			// image. Media without a source, e.g. external videos, can't be recreated.
+			src := runner.MediaSource(&m)
+			if src == "" {
+				continue
+			}
+			var alt *string
+			if m.Preview.Image != nil {
+				alt = m.Preview.Image.AltText
+			}
+			contentType := schema.FileContentType(m.MediaContentType)
+			input.Files = append(input.Files, schema.FileSetInput{
+				Alt:            alt,
+				ContentType:    &contentType,
+				OriginalSource: &src,
+			})
+		}
+	}
+
+	if _, ok := files["product_inventory.json"]; ok {
+		p.Files[engine.ProductInventory] = true
+	}
+
+	p.Variables.Input = input
+	if id, ok := idMap.Resolve(engine.Product, p.Product.ID); ok {
+		p.Variables.Identifier = &schema.ProductSetIdentifiers{ID: &id}
+	} else {
+		p.Variables.Identifier = &schema.ProductSetIdentifiers{Handle: &p.Product.Handle}
+	}
+	return &p, nil
+}
+
+func getProductSetInput(product *schema.Product) schema.ProductSetInput {
+	var (
+		category *string
+
+		options           = make([]schema.OptionSetInput, 0, len(product.Options))
+		redirectNewHandle = true // Auto create redirect if handle is changed.
+	)
+
+	if product.Category != nil {
+		category = &product.Category.ID
+	}
+
+	for _, opt := range product.Options {
+		values := make([]schema.OptionValueSetInput, 0, len(opt.OptionValues))
+		for _, v := range opt.OptionValues {
+			values = append(values, schema.OptionValueSetInput{
+				Name: &v.Name,
+			})
+		}
+		options = append(options, schema.OptionSetInput{
+			Name:     &opt.Name,
+			Position: &opt.Position,
+			Values:   values,
+		})
+	}
+
+	return schema.ProductSetInput{
+		Handle:                 &product.Handle,
+		Title:                  &product.Title,
+		DescriptionHtml:        &product.DescriptionHtml,
+		ProductType:            &product.ProductType,
+		Category:               category,
+		Tags:                   product.Tags,
+		Vendor:                 &product.Vendor,
+		Seo:                    &schema.SEOInput{Title: product.Seo.Title, Description: product.Seo.Description},
+		Status:                 &product.Status,
+		TemplateSuffix:         product.TemplateSuffix,
+		GiftCardTemplateSuffix: product.GiftCardTemplateSuffix,
+		GiftCard:               &product.IsGiftCard,
+		RedirectNewHandle:      &redirectNewHandle,
+		CombinedListingRole:    product.CombinedListingRole,
+		RequiresSellingPlan:    &product.RequiresSellingPlan,
+		ProductOptions:         options,
+	}
+}
+
+func readJSON(path string, v any) error {
+	content, err := registry.ReadFileContents(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, v)
+}
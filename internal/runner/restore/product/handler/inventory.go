@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+	"github.com/ankitpokhrel/shopctl/schema"
+)
+
+const (
+	inventoryQuantityAvailable = "available"
+	inventoryReasonCorrection  = "correction"
+
+	// Shopify accepts at most 250 quantities per call.
+	inventoryBatchSize = 250
+)
+
+// Locations resolves store locations by their name. Locations
+// are fetched once and shared between the inventory handlers.
+type Locations struct {
+	once   sync.Once
+	byName map[string]string
+	err    error
+}
+
+// Resolve returns the ID of the location with the given name.
+func (l *Locations) Resolve(ctx context.Context, client *api.GQLClient, name string) (string, bool, error) {
+	l.once.Do(func() {
+		locations, err := client.GetLocations(ctx)
+		if err != nil {
+			l.err = err
+			return
+		}
+		l.byName = make(map[string]string, len(locations))
+		for _, loc := range locations {
+			l.byName[loc.Name] = loc.ID
+		}
+	})
+	if l.err != nil {
+		return "", false, l.err
+	}
+	id, ok := l.byName[name]
+	return id, ok, nil
+}
+
+type Inventory struct {
+	Client    *api.GQLClient
+	Logger    *tlog.Logger
+	File      registry.File
+	Summary   *runner.Summary
+	IDMap     *runner.IDMap
+	Locations *Locations
+	Migration *runner.Migration
+	DryRun    bool
+}
+
+func (h *Inventory) Handle(ctx context.Context, data any) (any, error) {
+	var realProductID string
+	if id, ok := data.(string); ok {
+		realProductID = id
+	} else {
+		return nil, fmt.Errorf("unable to figure out real product ID")
+	}
+
+	inventoryRaw, err := registry.ReadFileContents(h.File.Path)
+	if err != nil {
+		h.Logger.Error("Unable to read contents", "file", h.File.Path, "error", err)
+		return nil, err
+	}
+	h.Summary.Count += 1
+
+	var inventory api.ProductInventoryData
+	if err = json.Unmarshal(inventoryRaw, &inventory); err != nil {
+		h.Logger.Error("Unable to marshal contents", "file", h.File.Path, "error", err)
+		h.Summary.Failed += 1
+		return nil, err
+	}
+
+	if h.DryRun {
+		levels := 0
+		for _, v := range inventory.Variants.Nodes {
+			levels += len(v.InventoryItem.InventoryLevels.Nodes)
+		}
+		h.Logger.V(tlog.VL2).Infof("Product inventory levels to sync: %d", levels)
+		h.Logger.V(tlog.VL3).Warn("Skipping product inventory sync")
+		h.Summary.Passed += 1
+		return nil, nil
+	}
+
+	quantities, err := h.getQuantities(ctx, realProductID, &inventory)
+	if err != nil {
+		h.Logger.Error("Failed to prepare product inventory", "oldID", inventory.ProductID, "upstreamID", realProductID, "error", err)
+		h.Summary.Failed += 1
+		return nil, err
+	}
+
+	for i := 0; i < len(quantities); i += inventoryBatchSize {
+		batch := quantities[i:min(i+inventoryBatchSize, len(quantities))]
+
+		h.Logger.V(tlog.VL2).Info("Attempting to set product inventory quantities", "id", realProductID, "count", len(batch))
+		_, err := h.Client.SetInventoryQuantities(ctx, schema.InventorySetQuantitiesInput{
+			Name:                  inventoryQuantityAvailable,
+			Reason:                inventoryReasonCorrection,
+			IgnoreCompareQuantity: true,
+			Quantities:            batch,
+		})
+		if err != nil {
+			h.Logger.Error("Failed to sync product inventory", "oldID", inventory.ProductID, "upstreamID", realProductID)
+			h.Summary.Failed += 1
+			return nil, err
+		}
+	}
+	h.Summary.Passed += 1
+	return nil, nil
+}
+
+// getQuantities maps the available quantities in the backup to the upstream inventory items. Variants are
+// matched by the ID map and then by their title, and locations are matched by their name. Inventory items
+// are stocked at the locations they are not yet stocked at.
+func (h *Inventory) getQuantities(ctx context.Context, productID string, inventory *api.ProductInventoryData) ([]schema.InventoryQuantityInput, error) {
+	upstream, err := h.Client.GetProductInventory(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*api.ProductInventoryNode, len(upstream.Variants.Nodes))
+	byTitle := make(map[string]*api.ProductInventoryNode, len(upstream.Variants.Nodes))
+	for _, v := range upstream.Variants.Nodes {
+		byID[v.ID] = &v
+		byTitle[keyme(v.Title)] = &v
+	}
+
+	quantities := make([]schema.InventoryQuantityInput, 0)
+	for _, v := range inventory.Variants.Nodes {
+		if !v.InventoryItem.Tracked {
+			continue
+		}
+
+		current, ok := byTitle[keyme(v.Title)]
+		if newID, mapped := h.IDMap.Resolve(engine.ProductVariant, v.ID); mapped && byID[newID] != nil {
+			current, ok = byID[newID], true
+		}
+		if !ok {
+			h.Logger.Warn("Unable to find the variant to set inventory for, skipping", "variant", v.ID, "title", v.Title)
+			continue
+		}
+
+		stocked := make(map[string]bool, len(current.InventoryItem.InventoryLevels.Nodes))
+		for _, lvl := range current.InventoryItem.InventoryLevels.Nodes {
+			stocked[lvl.Location.ID] = true
+		}
+
+		for _, lvl := range v.InventoryItem.InventoryLevels.Nodes {
+			available, ok := quantityOf(lvl.Quantities, inventoryQuantityAvailable)
+			if !ok {
+				continue
+			}
+
+			locationID, found, err := h.Locations.Resolve(ctx, h.Client, lvl.Location.Name)
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				h.Logger.Warn("Location not found in the store, skipping", "location", lvl.Location.Name, "variant", v.ID)
+				h.Migration.Unresolve(runner.Unresolved{
+					Resource: engine.ProductInventory, Owner: v.ID, Ref: runner.RefLocation, Value: lvl.Location.Name, Reason: "location not found",
+				})
+				continue
+			}
+
+			if !stocked[locationID] {
+				if _, err := h.Client.ActivateInventory(ctx, current.InventoryItem.ID, locationID); err != nil {
+					return nil, err
+				}
+				stocked[locationID] = true
+			}
+
+			quantities = append(quantities, schema.InventoryQuantityInput{
+				InventoryItemID: current.InventoryItem.ID,
+				LocationID:      locationID,
+				Quantity:        available,
+			})
+		}
+	}
+	return quantities, nil
+}
+
+func quantityOf(quantities []api.InventoryQuantity, name string) (int, bool) {
+	for _, q := range quantities {
+		if q.Name == name {
+			return q.Quantity, true
+		}
+	}
+	return 0, false
+}
@@ -1,10 +1,17 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
 
 	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
 	"github.com/ankitpokhrel/shopctl/internal/registry"
 	"github.com/ankitpokhrel/shopctl/internal/runner"
 	"github.com/ankitpokhrel/shopctl/pkg/tlog"
@@ -12,14 +19,17 @@ import (
 )
 
 type Media struct {
-	Client  *api.GQLClient
-	Logger  *tlog.Logger
-	File    registry.File
-	Summary *runner.Summary
-	DryRun  bool
+	Client    *api.GQLClient
+	Logger    *tlog.Logger
+	File      registry.File
+	Summary   *runner.Summary
+	IDMap     *runner.IDMap
+	Plan      *runner.Plan
+	Migration *runner.Migration
+	DryRun    bool
 }
 
-func (h *Media) Handle(data any) (any, error) {
+func (h *Media) Handle(ctx context.Context, data any) (any, error) {
 	var realProductID string
 	if id, ok := data.(string); ok {
 		realProductID = id
@@ -40,29 +50,46 @@ func (h *Media) Handle(data any) (any, error) {
 		return nil, err
 	}
 
+	// Media IDs differ between stores, so media are matched by their file name on migration.
+	key := func(m *api.ProductMediaNode) string { return m.ID }
+	if h.Migration != nil {
+		key = mediaFileName
+		media.Media.Nodes = h.migrate(media.ProductID, media.Media.Nodes)
+	}
+
 	toAdd := make([]*api.ProductMediaNode, 0)
 	toDelete := make([]string, 0)
 
 	// Get upstream medias.
-	currentMedia, _ := h.Client.GetProductMedias(realProductID)
+	currentMedia, _ := h.Client.GetProductMedias(ctx, realProductID)
 	currentMediaMap := make(map[string]*api.ProductMediaNode, 0)
 	if currentMedia != nil {
 		for _, m := range currentMedia.Data.Product.Media.Nodes {
-			currentMediaMap[m.ID] = &m
+			currentMediaMap[key(&m)] = &m
 		}
 
 		backupMediaMap := make(map[string]*api.ProductMediaNode, len(media.Media.Nodes))
 		for _, m := range media.Media.Nodes {
-			backupMediaMap[m.ID] = &m
+			backupMediaMap[key(&m)] = &m
 		}
 
-		for id := range currentMediaMap {
-			if _, ok := backupMediaMap[id]; !ok {
-				toDelete = append(toDelete, id)
+		for k, cm := range currentMediaMap {
+			entry := runner.PlanEntry{
+				Resource: engine.ProductMedia, Parent: media.ProductID, Key: k, UpstreamID: cm.ID, Action: runner.PlanUnchanged,
+			}
+			if m, ok := backupMediaMap[k]; !ok {
+				entry.Action = runner.PlanDelete
+				toDelete = append(toDelete, cm.ID)
+			} else {
+				entry.OldID = m.ID
+			}
+			if err := h.Plan.Record(entry, getMediaFields(cm)); err != nil {
+				h.Summary.Failed += 1
+				return nil, err
 			}
 		}
-		for id, m := range backupMediaMap {
-			if _, ok := currentMediaMap[id]; !ok {
+		for k, m := range backupMediaMap {
+			if _, ok := currentMediaMap[k]; !ok {
 				toAdd = append(toAdd, m)
 			}
 		}
@@ -71,12 +98,21 @@ func (h *Media) Handle(data any) (any, error) {
 			toAdd = append(toAdd, &m)
 		}
 	}
+	for _, m := range toAdd {
+		err := h.Plan.Add(runner.PlanEntry{
+			Resource: engine.ProductMedia, Parent: media.ProductID, Key: key(m), OldID: m.ID, Action: runner.PlanCreate,
+		})
+		if err != nil {
+			h.Summary.Failed += 1
+			return nil, err
+		}
+	}
 
 	attemptSync := func(pid string) error {
-		if _, err := h.handleProductMediaDelete(pid, toDelete); err != nil {
+		if _, err := h.handleProductMediaDelete(ctx, pid, toDelete); err != nil {
 			return err
 		}
-		if _, err := h.handleProductMediaAdd(pid, toAdd); err != nil {
+		if _, err := h.handleProductMediaAdd(ctx, pid, toAdd); err != nil {
 			return err
 		}
 		return nil
@@ -96,26 +132,178 @@ func (h *Media) Handle(data any) (any, error) {
 		return nil, err
 	}
 	h.Summary.Passed += 1
+	h.mapMedia(ctx, realProductID, currentMediaMap, media.Media.Nodes, toAdd, key)
 	return nil, nil
 }
 
-func (h Media) handleProductMediaAdd(productID string, toAdd []*api.ProductMediaNode) (*api.ProductCreateResponse, error) {
+// mapMedia records old to new ID mapping of the product media. Media are attached in
+// the order they are added so the newly attached media are matched in the same order.
+func (h *Media) mapMedia(
+	ctx context.Context,
+	productID string,
+	current map[string]*api.ProductMediaNode,
+	backup []api.ProductMediaNode,
+	added []*api.ProductMediaNode,
+	key func(*api.ProductMediaNode) string,
+) {
+	if h.IDMap == nil {
+		return
+	}
+
+	existing := make(map[string]bool, len(current))
+	for _, m := range current {
+		existing[m.ID] = true
+	}
+	for _, m := range backup {
+		if cm, ok := current[key(&m)]; ok {
+			h.IDMap.Add(runner.IDMapping{Resource: engine.ProductMedia, OldID: m.ID, NewID: cm.ID, Key: mediaKey(&m), Action: runner.ActionSkipped})
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	res, err := h.Client.GetProductMedias(ctx, productID)
+	if err != nil {
+		h.Logger.Warn("Unable to fetch product medias to map their IDs", "id", productID, "error", err)
+		return
+	}
+
+	newIDs := make([]string, 0, len(added))
+	for _, m := range res.Data.Product.Media.Nodes {
+		if !existing[m.ID] {
+			newIDs = append(newIDs, m.ID)
+		}
+	}
+	for i, m := range added {
+		var newID string
+		if i < len(newIDs) {
+			newID = newIDs[i]
+		}
+		h.IDMap.Add(runner.IDMapping{Resource: engine.ProductMedia, OldID: m.ID, NewID: newID, Key: mediaKey(m), Action: runner.ActionCreated})
+	}
+}
+
+func mediaKey(m *api.ProductMediaNode) string {
+	if m.Preview.Image == nil {
+		return ""
+	}
+	return m.Preview.Image.URL
+}
+
+// mediaFileName returns the file name of the media preview image without the query.
+func mediaFileName(m *api.ProductMediaNode) string {
+	u, err := url.Parse(mediaKey(m))
+	if err != nil || u.Path == "" {
+		return ""
+	}
+	return path.Base(u.Path)
+}
+
+// migrate leaves out the media that can't be matched by their file name on migration.
+func (h *Media) migrate(productID string, nodes []api.ProductMediaNode) []api.ProductMediaNode {
+	out := make([]api.ProductMediaNode, 0, len(nodes))
+	for _, m := range nodes {
+		if mediaFileName(&m) == "" {
+			h.Logger.Warn("Product media doesn't have a preview image to match it by, skipping", "id", m.ID)
+			h.Migration.Unresolve(runner.Unresolved{
+				Resource: engine.ProductMedia, Owner: productID, Ref: runner.RefMedia, Value: m.ID, Reason: "media has no preview image",
+			})
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func (h Media) handleProductMediaAdd(ctx context.Context, productID string, toAdd []*api.ProductMediaNode) (*api.ProductCreateResponse, error) {
 	input := schema.ProductInput{
 		ID: &productID,
 	}
 
 	createMediaInput := make([]schema.CreateMediaInput, 0, len(toAdd))
 	for _, m := range toAdd {
+		src, err := h.mediaSource(ctx, m)
+		if err != nil {
+			return nil, err
+		}
 		createMediaInput = append(createMediaInput, schema.CreateMediaInput{
-			OriginalSource:   m.Preview.Image.URL,
+			OriginalSource:   src,
 			Alt:              m.Preview.Image.AltText,
 			MediaContentType: m.MediaContentType,
 		})
 	}
-	return h.Client.UpdateProduct(input, createMediaInput)
+	return h.Client.UpdateProduct(ctx, input, createMediaInput)
+}
+
+// mediaSource returns the url to attach the media from. The exported file of the media is
+// uploaded to the store if the original url is no longer reachable, e.g. if the product was deleted.
+func (h Media) mediaSource(ctx context.Context, m *api.ProductMediaNode) (string, error) {
+	src := runner.MediaSource(m)
+	if src == "" {
+		src = mediaKey(m)
+	}
+
+	name := runner.MediaFile(m)
+	if name == "" {
+		return src, nil
+	}
+	file := filepath.Join(filepath.Dir(h.File.Path), runner.MediaDir, name)
+	if _, err := os.Stat(file); err != nil {
+		return src, nil
+	}
+	if h.Client.IsMediaReachable(ctx, src) {
+		return src, nil
+	}
+
+	h.Logger.V(tlog.VL2).Info("Media url is not reachable, uploading the exported file", "id", m.ID, "file", file)
+	return h.uploadMediaFile(ctx, m, file)
+}
+
+func (h Media) uploadMediaFile(ctx context.Context, m *api.ProductMediaNode, file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		size     = strconv.FormatInt(info.Size(), 10)
+		method   = schema.StagedUploadHttpMethodTypePost
+		resource = schema.StagedUploadTargetGenerateUploadResourceImage
+	)
+	switch m.MediaContentType {
+	case schema.MediaContentTypeVideo:
+		resource = schema.StagedUploadTargetGenerateUploadResourceVideo
+	case schema.MediaContentTypeModel3d:
+		resource = schema.StagedUploadTargetGenerateUploadResourceModel3d
+	}
+
+	target, err := h.Client.CreateStagedUpload(ctx, schema.StagedUploadInput{
+		Filename:   filepath.Base(file),
+		MimeType:   runner.MediaMimeType(m),
+		Resource:   resource,
+		FileSize:   &size,
+		HttpMethod: &method,
+	})
+	if err != nil {
+		return "", err
+	}
+	if target.ResourceURL == nil {
+		return "", fmt.Errorf("stagedUploadsCreate: no resource url to attach media %s from", m.ID)
+	}
+	if err := h.Client.Upload(ctx, target, filepath.Base(file), f, info.Size()); err != nil {
+		return "", err
+	}
+	return *target.ResourceURL, nil
 }
 
-func (h Media) handleProductMediaDelete(productID string, toDelete []string) (*api.FileUpdateResponse, error) {
+func (h Media) handleProductMediaDelete(ctx context.Context, productID string, toDelete []string) (*api.FileUpdateResponse, error) {
 	if len(toDelete) == 0 {
 		return nil, nil
 	}
@@ -128,5 +316,17 @@ func (h Media) handleProductMediaDelete(productID string, toDelete []string) (*a
 		})
 	}
 	h.Logger.V(tlog.VL2).Info("Attempting to detach product medias", "id", productID)
-	return h.Client.DetachProductMedia(input)
+	return h.Client.DetachProductMedia(ctx, input)
+}
+
+func getMediaFields(m *api.ProductMediaNode) map[string]any {
+	var alt *string
+	if m.Preview.Image != nil {
+		alt = m.Preview.Image.AltText
+	}
+	return map[string]any{
+		"type":   m.MediaContentType,
+		"alt":    alt,
+		"source": runner.MediaSource(m),
+	}
 }
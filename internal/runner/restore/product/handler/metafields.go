@@ -1,25 +1,30 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
 	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
 	"github.com/ankitpokhrel/shopctl/internal/registry"
 	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/pkg/diff"
 	"github.com/ankitpokhrel/shopctl/pkg/tlog"
 	"github.com/ankitpokhrel/shopctl/schema"
 )
 
 type Metafield struct {
-	Client  *api.GQLClient
-	Logger  *tlog.Logger
-	File    registry.File
-	Summary *runner.Summary
-	DryRun  bool
+	Client    *api.GQLClient
+	Logger    *tlog.Logger
+	File      registry.File
+	Summary   *runner.Summary
+	Plan      *runner.Plan
+	Migration *runner.Migration
+	DryRun    bool
 }
 
-func (h Metafield) Handle(data any) (any, error) {
+func (h Metafield) Handle(ctx context.Context, data any) (any, error) {
 	var realProductID string
 	if id, ok := data.(string); ok {
 		realProductID = id
@@ -41,49 +46,104 @@ func (h Metafield) Handle(data any) (any, error) {
 		return nil, err
 	}
 
+	// Metafields that can't be resolved on migration are left untouched.
+	nodes, skipped, err := h.Migration.ResolveMetafields(ctx, engine.ProductMetaField, meta.ProductID, schema.MetafieldOwnerTypeProduct, meta.Metafields.Nodes)
+	if err != nil {
+		h.Summary.Failed += 1
+		return nil, err
+	}
+	meta.Metafields.Nodes = nodes
+
 	toAdd := make([]*schema.Metafield, 0)
 	toDelete := make([]*schema.Metafield, 0)
 
 	// Get upstream metafields.
-	currentMetafields, _ := h.Client.GetProductMetaFields(realProductID)
+	currentMetafields, _ := h.Client.GetProductMetaFields(ctx, realProductID)
 	if currentMetafields != nil {
 		currentMetafieldsMap := make(map[string]*schema.Metafield, len(currentMetafields.Data.Product.Metafields.Nodes))
 		for _, opt := range currentMetafields.Data.Product.Metafields.Nodes {
-			currentMetafieldsMap[opt.ID] = &opt
+			currentMetafieldsMap[metafieldKey(&opt)] = &opt
 		}
 
+		// Metafields are matched by their namespace and key as IDs differ between stores.
 		backupMetafieldsMap := make(map[string]*schema.Metafield, len(meta.Metafields.Nodes))
 		for _, m := range meta.Metafields.Nodes {
-			backupMetafieldsMap[m.ID] = &m
+			backupMetafieldsMap[metafieldKey(&m)] = &m
 		}
 
 		for id, cm := range currentMetafieldsMap {
 			if m, ok := backupMetafieldsMap[id]; ok {
+				changes, err := diff.Compare(getMetafieldFields(cm), getMetafieldFields(m))
+				if err != nil {
+					h.Summary.Failed += 1
+					return nil, err
+				}
+				entry := runner.PlanEntry{
+					Resource: engine.ProductMetaField, Parent: meta.ProductID, Key: id, OldID: m.ID, UpstreamID: cm.ID,
+					Action: runner.PlanUpdate, Changes: changes,
+				}
+				if len(changes) == 0 {
+					entry.Action = runner.PlanUnchanged
+				}
+				if err := h.Plan.Record(entry, getMetafieldFields(cm)); err != nil {
+					h.Summary.Failed += 1
+					return nil, err
+				}
+				if len(changes) == 0 {
+					h.Logger.V(tlog.VL3).Info("Product metafield is unchanged, skipping", "namespace", m.Namespace, "key", m.Key)
+					continue
+				}
 				toAdd = append(toAdd, m)
-			} else {
+			} else if !skipped[id] {
+				err := h.Plan.Record(runner.PlanEntry{
+					Resource: engine.ProductMetaField, Parent: meta.ProductID, Key: id, UpstreamID: cm.ID, Action: runner.PlanDelete,
+				}, getMetafieldFields(cm))
+				if err != nil {
+					h.Summary.Failed += 1
+					return nil, err
+				}
 				toDelete = append(toDelete, cm)
 			}
 		}
 		for id, m := range backupMetafieldsMap {
 			if _, ok := currentMetafieldsMap[id]; !ok {
+				err := h.Plan.Add(runner.PlanEntry{
+					Resource: engine.ProductMetaField, Parent: meta.ProductID, Key: id, OldID: m.ID, Action: runner.PlanCreate,
+				})
+				if err != nil {
+					h.Summary.Failed += 1
+					return nil, err
+				}
 				toAdd = append(toAdd, m)
 			}
 		}
 	} else {
 		for _, m := range meta.Metafields.Nodes {
+			err := h.Plan.Add(runner.PlanEntry{
+				Resource: engine.ProductMetaField, Parent: meta.ProductID, Key: metafieldKey(&m), OldID: m.ID, Action: runner.PlanCreate,
+			})
+			if err != nil {
+				h.Summary.Failed += 1
+				return nil, err
+			}
 			toAdd = append(toAdd, &m)
 		}
 	}
 
 	attemptSync := func(pid string) error {
-		if _, err := h.handleProductMetaFieldsSet(pid, toAdd); err != nil {
+		if _, err := h.handleProductMetaFieldsSet(ctx, pid, toAdd); err != nil {
 			return err
 		}
-		if _, err := h.handleProductMetaFieldsDelete(pid, toDelete); err != nil {
+		if _, err := h.handleProductMetaFieldsDelete(ctx, pid, toDelete); err != nil {
 			return err
 		}
 		return nil
 	}
+	if len(toAdd) == 0 && len(toDelete) == 0 {
+		h.Logger.V(tlog.VL2).Info("Product metafields are unchanged, skipping sync", "oldID", meta.ProductID, "upstreamID", realProductID)
+		h.Summary.Unchanged += 1
+		return nil, nil
+	}
 	h.Logger.V(1).Info("Attempting to sync product metafileds", "oldID", meta.ProductID, "upstreamID", realProductID)
 	if h.DryRun {
 		h.Logger.V(tlog.VL2).Infof("Product metafields to sync - add: %d, remove: %d", len(toAdd), len(toDelete))
@@ -101,7 +161,7 @@ func (h Metafield) Handle(data any) (any, error) {
 	return nil, nil
 }
 
-func (h Metafield) handleProductMetaFieldsSet(productID string, toAdd []*schema.Metafield) (*api.MetafieldSetResponse, error) {
+func (h Metafield) handleProductMetaFieldsSet(ctx context.Context, productID string, toAdd []*schema.Metafield) (*api.MetafieldSetResponse, error) {
 	if len(toAdd) == 0 {
 		return nil, nil
 	}
@@ -117,10 +177,10 @@ func (h Metafield) handleProductMetaFieldsSet(productID string, toAdd []*schema.
 		})
 	}
 	h.Logger.V(tlog.VL2).Info("Attempting to set product metafields", "id", productID)
-	return h.Client.SetMetafields(metafields)
+	return h.Client.SetMetafields(ctx, metafields)
 }
 
-func (h Metafield) handleProductMetaFieldsDelete(productID string, toDelete []*schema.Metafield) (*api.MetafieldDeleteResponse, error) {
+func (h Metafield) handleProductMetaFieldsDelete(ctx context.Context, productID string, toDelete []*schema.Metafield) (*api.MetafieldDeleteResponse, error) {
 	if len(toDelete) == 0 {
 		return nil, nil
 	}
@@ -134,5 +194,17 @@ func (h Metafield) handleProductMetaFieldsDelete(productID string, toDelete []*s
 		})
 	}
 	h.Logger.V(tlog.VL2).Info("Attempting to delete product metafields", "id", productID)
-	return h.Client.DeleteMetafields(metafields)
+	return h.Client.DeleteMetafields(ctx, metafields)
+}
+
+func metafieldKey(m *schema.Metafield) string {
+	return m.Namespace + "." + m.Key
+}
+
+// getMetafieldFields returns the fields of the metafield that are synced on update.
+func getMetafieldFields(m *schema.Metafield) map[string]any {
+	return map[string]any{
+		"value": m.Value,
+		"type":  m.Type,
+	}
 }
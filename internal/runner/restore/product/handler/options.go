@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
 	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/pkg/diff"
 	"github.com/ankitpokhrel/shopctl/pkg/tlog"
 	"github.com/ankitpokhrel/shopctl/schema"
 )
@@ -16,10 +20,12 @@ type Option struct {
 	Client    *api.GQLClient
 	Logger    *tlog.Logger
 	File      registry.File
+	Summary   *runner.Summary
+	Plan      *runner.Plan
 	DryRun    bool
 }
 
-func (h Option) Handle(data any) (any, error) {
+func (h Option) Handle(ctx context.Context, data any) (any, error) {
 	var realProductID string
 	if id, ok := data.(string); ok {
 		realProductID = id
@@ -32,10 +38,12 @@ func (h Option) Handle(data any) (any, error) {
 		h.Logger.Error("Unable to read contents", "file", h.File.Path, "error", err)
 		return nil, err
 	}
+	h.Summary.Count += 1
 
 	var product schema.Product
 	if err = json.Unmarshal(productRaw, &product); err != nil {
 		h.Logger.Error("Unable to marshal contents", "file", h.File.Path, "error", err)
+		h.Summary.Failed += 1
 		return nil, err
 	}
 
@@ -44,7 +52,7 @@ func (h Option) Handle(data any) (any, error) {
 	toDelete := make([]string, 0)
 
 	// Get upstream options.
-	currentOptions, _ := h.Client.GetProductOptions(realProductID)
+	currentOptions, _ := h.Client.GetProductOptions(ctx, realProductID)
 	currentOptionsMap := make(map[string]*schema.ProductOption, 0)
 	if currentOptions != nil {
 		for _, opt := range currentOptions.Data.Product.Options {
@@ -56,12 +64,39 @@ func (h Option) Handle(data any) (any, error) {
 			backupOptionsMap[keyme(opt.Name)] = &opt
 		}
 
-		for k := range currentOptionsMap {
-			id := currentOptionsMap[k].ID
+		for k, co := range currentOptionsMap {
+			id := co.ID
 			if opt, ok := backupOptionsMap[k]; ok {
+				changes, err := diff.Compare(getOptionFields(co), getOptionFields(opt))
+				if err != nil {
+					h.Summary.Failed += 1
+					return nil, err
+				}
+				entry := runner.PlanEntry{
+					Resource: engine.ProductOption, Parent: product.ID, Key: opt.Name, OldID: opt.ID, UpstreamID: id,
+					Action: runner.PlanUpdate, Changes: changes,
+				}
+				if len(changes) == 0 {
+					entry.Action = runner.PlanUnchanged
+				}
+				if err := h.Plan.Record(entry, getOptionFields(co)); err != nil {
+					h.Summary.Failed += 1
+					return nil, err
+				}
+				if len(changes) == 0 {
+					h.Logger.V(tlog.VL2).Info("Product option is unchanged, skipping update", "id", id, "name", opt.Name)
+					continue
+				}
 				opt.ID = id
 				toUpdate = append(toUpdate, opt)
 			} else {
+				err := h.Plan.Record(runner.PlanEntry{
+					Resource: engine.ProductOption, Parent: product.ID, Key: co.Name, UpstreamID: id, Action: runner.PlanDelete,
+				}, getOptionFields(co))
+				if err != nil {
+					h.Summary.Failed += 1
+					return nil, err
+				}
 				toDelete = append(toDelete, id)
 			}
 		}
@@ -75,34 +110,53 @@ func (h Option) Handle(data any) (any, error) {
 			toAdd = append(toAdd, &m)
 		}
 	}
+	for _, opt := range toAdd {
+		err := h.Plan.Add(runner.PlanEntry{
+			Resource: engine.ProductOption, Parent: product.ID, Key: opt.Name, OldID: opt.ID, Action: runner.PlanCreate,
+		})
+		if err != nil {
+			h.Summary.Failed += 1
+			return nil, err
+		}
+	}
 
 	attemptSync := func(pid string) error {
-		if _, err := h.handleProductOptionDelete(pid, toDelete); err != nil {
+		if _, err := h.handleProductOptionDelete(ctx, pid, toDelete); err != nil {
 			return err
 		}
-		if _, err := h.handleProductOptionAdd(pid, toAdd); err != nil {
+		if _, err := h.handleProductOptionAdd(ctx, pid, toAdd); err != nil {
 			return err
 		}
-		if _, err := h.handlProductOptionUpdate(pid, currentOptionsMap, toUpdate); err != nil {
+		if _, err := h.handlProductOptionUpdate(ctx, pid, currentOptionsMap, toUpdate); err != nil {
 			return err
 		}
 		return nil
 	}
 
+	if len(toAdd) == 0 && len(toUpdate) == 0 && len(toDelete) == 0 {
+		h.Logger.V(tlog.VL2).Info("Product options are unchanged, skipping sync", "oldID", product.ID, "upstreamID", realProductID)
+		h.Summary.Unchanged += 1
+		return nil, nil
+	}
+
 	h.Logger.V(1).Info("Attempting to sync product options", "oldID", product.ID, "upstreamID", realProductID)
 	if h.DryRun {
 		h.Logger.V(tlog.VL2).Infof("Product options to sync - add: %d, update: %d, remove: %d", len(toAdd), len(toUpdate), len(toDelete))
 		h.Logger.V(tlog.VL3).Warn("Skipping product options sync")
+		h.Summary.Passed += 1
 		return nil, nil
 	}
 	err = attemptSync(realProductID)
 	if err != nil {
 		h.Logger.Error("Failed to sync product options", "oldID", product.ID, "upstreamID", realProductID)
+		h.Summary.Failed += 1
+		return nil, err
 	}
-	return nil, err
+	h.Summary.Passed += 1
+	return nil, nil
 }
 
-func (h Option) handleProductOptionAdd(productID string, toAdd []*schema.ProductOption) (*api.ProductOptionSyncResponse, error) {
+func (h Option) handleProductOptionAdd(ctx context.Context, productID string, toAdd []*schema.ProductOption) (*api.ProductOptionSyncResponse, error) {
 	if len(toAdd) == 0 {
 		return nil, nil
 	}
@@ -138,10 +192,10 @@ func (h Option) handleProductOptionAdd(productID string, toAdd []*schema.Product
 		}
 	}
 	h.Logger.V(tlog.VL2).Info("Attempting to create product options", "id", productID)
-	return h.Client.CreateProductOptions(productID, options, schema.ProductOptionCreateVariantStrategyCreate)
+	return h.Client.CreateProductOptions(ctx, productID, options, schema.ProductOptionCreateVariantStrategyCreate)
 }
 
-func (h Option) handlProductOptionUpdate(productID string, currentOptionsMap map[string]*schema.ProductOption, toUpdate []*schema.ProductOption) (*api.ProductOptionSyncResponse, error) {
+func (h Option) handlProductOptionUpdate(ctx context.Context, productID string, currentOptionsMap map[string]*schema.ProductOption, toUpdate []*schema.ProductOption) (*api.ProductOptionSyncResponse, error) {
 	if len(toUpdate) == 0 {
 		return nil, nil
 	}
@@ -206,7 +260,7 @@ func (h Option) handlProductOptionUpdate(productID string, currentOptionsMap map
 			}
 		}
 
-		out, err := h.Client.UpdateProductOptions(productID, &option, optionValuesToAdd, optionValuesToUpdate, optionValuesToDelete, schema.ProductOptionUpdateVariantStrategyManage)
+		out, err := h.Client.UpdateProductOptions(ctx, productID, &option, optionValuesToAdd, optionValuesToUpdate, optionValuesToDelete, schema.ProductOptionUpdateVariantStrategyManage)
 		if err != nil {
 			updateErrors = append(updateErrors, err)
 		} else {
@@ -221,10 +275,27 @@ func (h Option) handlProductOptionUpdate(productID string, currentOptionsMap map
 	return updateResponses[0], nil
 }
 
-func (h Option) handleProductOptionDelete(productID string, toDelete []string) (*api.ProductOptionSyncResponse, error) {
+func (h Option) handleProductOptionDelete(ctx context.Context, productID string, toDelete []string) (*api.ProductOptionSyncResponse, error) {
 	if len(toDelete) == 0 {
 		return nil, nil
 	}
 	h.Logger.V(tlog.VL2).Info("Attempting to delete product options", "id", productID)
-	return h.Client.DeleteProductOptions(productID, toDelete)
+	return h.Client.DeleteProductOptions(ctx, productID, toDelete)
+}
+
+// getOptionFields returns the fields of the option that are synced on update.
+func getOptionFields(opt *schema.ProductOption) map[string]any {
+	values := make([]string, 0, len(opt.OptionValues))
+	for _, v := range opt.OptionValues {
+		if v.LinkedMetafieldValue != nil {
+			values = append(values, *v.LinkedMetafieldValue)
+		} else {
+			values = append(values, v.Name)
+		}
+	}
+	return map[string]any{
+		"name":     opt.Name,
+		"position": opt.Position,
+		"values":   values,
+	}
 }
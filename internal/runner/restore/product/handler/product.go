@@ -1,9 +1,9 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
-	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/ankitpokhrel/shopctl"
@@ -11,20 +11,24 @@ import (
 	"github.com/ankitpokhrel/shopctl/internal/engine"
 	"github.com/ankitpokhrel/shopctl/internal/registry"
 	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/pkg/diff"
 	"github.com/ankitpokhrel/shopctl/pkg/tlog"
 	"github.com/ankitpokhrel/shopctl/schema"
 )
 
 type Product struct {
-	Client  *api.GQLClient
-	Logger  *tlog.Logger
-	File    registry.File
-	Filter  *runner.RestoreFilter
-	Summary *runner.Summary
-	DryRun  bool
+	Client    *api.GQLClient
+	Logger    *tlog.Logger
+	File      registry.File
+	Filter    *runner.RestoreFilter
+	Summary   *runner.Summary
+	IDMap     *runner.IDMap
+	Plan      *runner.Plan
+	Migration *runner.Migration
+	DryRun    bool
 }
 
-func (h *Product) Handle(data any) (any, error) {
+func (h *Product) Handle(ctx context.Context, data any) (any, error) {
 	productRaw, err := registry.ReadFileContents(h.File.Path)
 	if err != nil {
 		h.Logger.Error("Unable to read contents", "file", h.File.Path, "error", err)
@@ -40,47 +44,156 @@ func (h *Product) Handle(data any) (any, error) {
 	}
 
 	// Filter product.
-	if len(h.Filter.Filters) > 0 {
-		matched, err := matchesFilters(&product, h.Filter)
-		if err != nil || !matched {
-			h.Summary.Skipped += 1
-			return nil, engine.ErrSkipChildren
-		}
+	if !FilterFields.Match(h.Filter, &product) {
+		h.Summary.Skipped += 1
+		h.IDMap.Add(runner.IDMapping{Resource: engine.Product, OldID: product.ID, Key: product.Handle, Action: runner.ActionSkipped})
+		return nil, engine.ErrSkipChildren
+	}
+
+	if err := h.migrate(ctx, &product); err != nil {
+		h.Summary.Failed += 1
+		return nil, err
+	}
+
+	pl, err := planProduct(ctx, &product, h.Client, h.IDMap)
+	if err != nil {
+		h.Summary.Failed += 1
+		return nil, err
+	}
+	err = h.Plan.Add(runner.PlanEntry{
+		Resource: engine.Product, Key: product.Handle, OldID: product.ID, UpstreamID: pl.upstreamID,
+		Action: pl.action, Changes: pl.changes, Upstream: pl.upstream,
+	})
+	if err != nil {
+		h.Summary.Failed += 1
+		return nil, err
 	}
 
 	if h.DryRun {
+		h.Logger.V(tlog.VL2).Info("Product sync planned", "handle", product.Handle, "action", pl.action, "fields", diff.Fields(pl.changes))
 		h.Logger.V(tlog.VL3).Warn("Skipping product sync")
 		h.Summary.Passed += 1
+		if pl.upstreamID != "" {
+			return pl.upstreamID, nil
+		}
 		return product.ID, nil
 	}
-	res, err := createOrUpdateProduct(&product, h.Client, h.Logger)
+	id, action, err := createOrUpdateProduct(ctx, &product, pl, h.Client, h.Logger)
 	if err != nil {
 		h.Summary.Failed += 1
 		return nil, err
 	}
-	h.Summary.Passed += 1
-	return res.Product.ID, nil
+	if action == runner.ActionUnchanged {
+		h.Summary.Unchanged += 1
+	} else {
+		h.Summary.Passed += 1
+	}
+	h.IDMap.Add(runner.IDMapping{Resource: engine.Product, OldID: product.ID, NewID: id, Key: product.Handle, Action: action})
+	return id, nil
 }
 
-func createOrUpdateProduct(product *schema.Product, client *api.GQLClient, lgr *tlog.Logger) (*api.ProductCreateResponse, error) {
-	res, err := client.CheckProductByHandle(product.Handle)
+// findUpstreamProduct finds the product in the store by its handle. The product
+// is resolved from the ID map if it can't be found by the handle, e.g. if the handle was changed.
+func findUpstreamProduct(ctx context.Context, product *schema.Product, client *api.GQLClient, idMap *runner.IDMap) (*schema.Product, error) {
+	res, err := client.CheckProductByHandle(ctx, product.Handle)
 	if err != nil {
 		return nil, err
 	}
+	if res.ID != "" {
+		return res, nil
+	}
+	if id, ok := idMap.Resolve(engine.Product, product.ID); ok {
+		if p, err := client.CheckProductByID(ctx, id); err == nil && p.ID != "" {
+			return p, nil
+		}
+	}
+	return res, nil
+}
+
+// migrate resolves the category of the product in the target store on migration.
+// The category is left out if it can't be found.
+func (h *Product) migrate(ctx context.Context, product *schema.Product) error {
+	if h.Migration == nil || product.Category == nil {
+		return nil
+	}
 
-	// TODO: Compare and extract fields that are actually updated.
+	id, ok, err := h.Migration.Category(ctx, product.Category)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		h.Logger.Warn("Product category not found in the store, skipping", "handle", product.Handle, "category", product.Category.FullName)
+		h.Migration.Unresolve(runner.Unresolved{
+			Resource: engine.Product, Owner: product.ID, Ref: runner.RefCategory, Value: product.Category.FullName, Reason: "category not found",
+		})
+		product.Category = nil
+		return nil
+	}
+	product.Category.ID = id
+	return nil
+}
 
-	var (
-		category *string
+// productPlan is the change to make to a product in the store.
+type productPlan struct {
+	upstreamID string
+	action     string
+	changes    []diff.Change
+	upstream   string
+	input      schema.ProductInput
+}
 
-		options           = make([]any, 0, len(product.Options))
-		redirectNewHandle = true // Auto create redirect if handle is changed.
-	)
+// planProduct compares the product with its upstream state, if any, and plans the change to make.
+func planProduct(ctx context.Context, product *schema.Product, client *api.GQLClient, idMap *runner.IDMap) (*productPlan, error) {
+	res, err := findUpstreamProduct(ctx, product, client, idMap)
+	if err != nil {
+		return nil, err
+	}
 
-	if product.Category != nil {
-		category = &product.Category.ID
+	pl := productPlan{action: runner.PlanCreate, input: getProductInput(product)}
+	if res.ID == "" {
+		return &pl, nil
+	}
+
+	current, err := client.GetProductFieldsByID(ctx, res.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Only the fields sent in the mutation are compared, so the input is built from both sides.
+	currentInput := getProductInput(current)
+	if pl.changes, err = diff.Compare(currentInput, pl.input); err != nil {
+		return nil, err
+	}
+	if pl.upstream, err = runner.Checksum(currentInput); err != nil {
+		return nil, err
+	}
+
+	pl.upstreamID = res.ID
+	pl.action = runner.PlanUpdate
+	if len(pl.changes) == 0 {
+		pl.action = runner.PlanUnchanged
+	}
+	return &pl, nil
+}
+
+func createOrUpdateProduct(ctx context.Context, product *schema.Product, pl *productPlan, client *api.GQLClient, lgr *tlog.Logger) (string, string, error) {
+	input := pl.input
+
+	switch pl.action {
+	case runner.PlanUnchanged:
+		lgr.V(tlog.VL2).Info("Product is unchanged, skipping update", "id", pl.upstreamID, "handle", product.Handle)
+		return pl.upstreamID, runner.ActionUnchanged, nil
+	case runner.PlanUpdate:
+		input.ID = &pl.upstreamID
+
+		lgr.Warn("Product already exists, updating", "id", pl.upstreamID, "handle", product.Handle, "fields", diff.Fields(pl.changes))
+		out, err := client.UpdateProduct(ctx, input, nil)
+		if err != nil {
+			return "", runner.ActionUpdated, err
+		}
+		return out.Product.ID, runner.ActionUpdated, nil
 	}
 
+	options := make([]any, 0, len(product.Options))
 	for _, opt := range product.Options {
 		values := make([]any, 0, len(opt.Values))
 		for _, v := range opt.OptionValues {
@@ -95,7 +208,29 @@ func createOrUpdateProduct(product *schema.Product, client *api.GQLClient, lgr *
 		})
 	}
 
-	input := schema.ProductInput{
+	// Some fields can only be specified during create.
+	input.ProductOptions = options // Note that UI may not display all options unless there is a variant with that option.
+
+	lgr.Info("Creating product", "oldID", product.ID, "handle", product.Handle)
+	out, err := client.CreateProduct(ctx, input)
+	if err != nil {
+		return "", runner.ActionCreated, err
+	}
+	return out.Product.ID, runner.ActionCreated, nil
+}
+
+func getProductInput(product *schema.Product) schema.ProductInput {
+	var (
+		category *string
+
+		redirectNewHandle = true // Auto create redirect if handle is changed.
+	)
+
+	if product.Category != nil {
+		category = &product.Category.ID
+	}
+
+	return schema.ProductInput{
 		Handle:                 &product.Handle,
 		Title:                  &product.Title,
 		DescriptionHtml:        &product.DescriptionHtml,
@@ -108,86 +243,41 @@ func createOrUpdateProduct(product *schema.Product, client *api.GQLClient, lgr *
 		TemplateSuffix:         product.TemplateSuffix,
 		GiftCardTemplateSuffix: product.GiftCardTemplateSuffix,
 		GiftCard:               &product.IsGiftCard,
-		CollectionsToJoin:      nil, // Not supported yet.
-		CollectionsToLeave:     nil, // Not supported yet.
+		CollectionsToJoin:      nil, // Products are added to the collections when restoring collections.
+		CollectionsToLeave:     nil,
 		RedirectNewHandle:      &redirectNewHandle,
 		CombinedListingRole:    product.CombinedListingRole,
 		RequiresSellingPlan:    &product.RequiresSellingPlan,
 		ClaimOwnership:         nil, // No way to get this value.
 	}
-
-	if res.ID != "" {
-		input.ID = &res.ID
-
-		lgr.Warn("Product already exists, updating", "id", res.ID, "handle", res.Handle)
-		return client.UpdateProduct(input, nil)
-	}
-
-	// Some fields can only be specified during create.
-	input.ProductOptions = options // Note that UI may not display all options unless there is a variant with that option.
-
-	lgr.Info("Creating product", "oldID", product.ID, "handle", product.Handle)
-	return client.CreateProduct(input)
 }
 
-func matchesFilters(product *schema.Product, rf *runner.RestoreFilter) (bool, error) {
-	containsAny := func(s []any, v any) bool { return slices.Contains(s, v) } //nolint:gocritic
-
-	results := []bool{}
-	for key, values := range rf.Filters {
-		switch strings.ToLower(key) {
-		case "id":
-			matched := slices.Contains(values, shopctl.ExtractNumericID(product.ID))
-			results = append(results, matched)
-		case "handle":
-			matched := slices.Contains(values, string(product.Handle))
-			results = append(results, matched)
-		case "title":
-			matched := slices.Contains(values, string(product.Title))
-			results = append(results, matched)
-		case "tags":
-			matched := false
-			for _, tag := range values {
-				if containsAny(product.Tags, tag) {
-					matched = true
-					break
-				}
-			}
-			results = append(results, matched)
-		case "status":
-			matched := slices.Contains(values, strings.ToLower(string(product.Status)))
-			results = append(results, matched)
-		case "producttype":
-			matched := slices.Contains(values, strings.ToLower(string(product.ProductType)))
-			results = append(results, matched)
-		case "category":
-			matched := false
-			if product.Category != nil {
-				matched = slices.Contains(values, strings.ToLower(product.Category.Name))
-			}
-			results = append(results, matched)
-		default:
-			return false, fmt.Errorf("unsupported filter key: %s", key)
+// FilterFields are the fields products can be filtered by on restore.
+var FilterFields = runner.FilterFields[schema.Product]{
+	"id": func(p *schema.Product) []string {
+		return []string{shopctl.ExtractNumericID(p.ID), p.ID}
+	},
+	"handle":       func(p *schema.Product) []string { return []string{p.Handle} },
+	"title":        func(p *schema.Product) []string { return []string{p.Title} },
+	"vendor":       func(p *schema.Product) []string { return []string{p.Vendor} },
+	"status":       func(p *schema.Product) []string { return []string{string(p.Status)} },
+	"product_type": func(p *schema.Product) []string { return []string{p.ProductType} },
+	"producttype":  func(p *schema.Product) []string { return []string{p.ProductType} },
+	"tag":          func(p *schema.Product) []string { return runner.StringValues(p.Tags) },
+	"tags":         func(p *schema.Product) []string { return runner.StringValues(p.Tags) },
+	"category": func(p *schema.Product) []string {
+		if p.Category == nil {
+			return nil
 		}
-	}
-
-	if len(results) == 0 {
-		return false, nil
-	}
-
-	// Combine results using separators
-	finalResult := results[0]
-	for i, sep := range rf.Separators {
-		switch strings.ToLower(sep) {
-		case "and":
-			finalResult = finalResult && results[i+1]
-		case "or":
-			finalResult = finalResult || results[i+1]
-		default:
-			return false, fmt.Errorf("unsupported separator: %s", sep)
-		}
-	}
-	return finalResult, nil
+		return []string{p.Category.Name, p.Category.FullName}
+	},
+	"gift_card":    func(p *schema.Product) []string { return []string{strconv.FormatBool(p.IsGiftCard)} },
+	"created_at":   func(p *schema.Product) []string { return []string{p.CreatedAt} },
+	"updated_at":   func(p *schema.Product) []string { return []string{p.UpdatedAt} },
+	"published_at": func(p *schema.Product) []string { return runner.OptionalValue(p.PublishedAt) },
+	"default": func(p *schema.Product) []string {
+		return []string{p.Title, p.Handle, p.Vendor, p.ProductType}
+	},
 }
 
 func keyme(s string) string {
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/config"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/internal/registry"
+	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
+)
+
+func TestProduct_HandleUnchanged(t *testing.T) {
+	t.Setenv("SHOPIFY_ACCESS_TOKEN", "12345")
+
+	const (
+		product = `{"id":"gid://shopify/Product/1","handle":"shirt","title":"Shirt","descriptionHtml":"<p>Cotton</p>","vendor":"Acme","productType":"Shirts","status":"ACTIVE","tags":["premium"],"seo":{"title":"Shirt"}}`
+		options = `[{"id":"gid://shopify/ProductOption/2","name":"Size","position":1,"optionValues":[{"id":"gid://shopify/ProductOptionValue/3","name":"S"},{"id":"gid://shopify/ProductOptionValue/4","name":"M"}]}]`
+	)
+
+	path := "./testdata/.tmp/products/1/product.json"
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	assert.NoError(t, os.WriteFile(path, []byte(strings.TrimSuffix(product, "}")+`,"options":`+options+"}"), 0o644))
+
+	var mutations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		var req struct {
+			Query string `json:"query"`
+		}
+		assert.NoError(t, json.Unmarshal(body, &req))
+
+		// The product exists in the store with the same state as in the backup.
+		var resp string
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(req.Query), "mutation"):
+			mutations = append(mutations, req.Query)
+			resp = `{"data":{}}`
+		case strings.Contains(req.Query, "GetProductByHandle"):
+			resp = `{"data":{"productByIdentifier":{"id":"gid://shopify/Product/101","handle":"shirt"}}}`
+		case strings.Contains(req.Query, "GetProductFieldsByID"):
+			resp = `{"data":{"product":` + strings.Replace(product, "Product/1", "Product/101", 1) + `}}`
+		case strings.Contains(req.Query, "GetProductOptions"):
+			resp = `{"data":{"product":{"id":"gid://shopify/Product/101","options":` + options + `}}}`
+		default:
+			t.Fatalf("unexpected query: %s", req.Query)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(resp))
+	}))
+	defer server.Close()
+
+	var (
+		client       = api.NewGQLClient(&config.StoreContext{Store: "teststore.example.com", Alias: "test"}, api.WithServer(server.URL))
+		logger       = tlog.New(tlog.VerboseLevel(tlog.VL1), true)
+		idMap        = runner.NewIDMap()
+		file         = registry.File{Path: path}
+		productStats = runner.Summary{}
+		optionStats  = runner.Summary{}
+	)
+
+	productFn := &Product{Client: client, File: file, Logger: logger, Summary: &productStats, IDMap: idMap}
+	id, err := productFn.Handle(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "gid://shopify/Product/101", id)
+
+	optionsFn := &Option{Client: client, File: file, Logger: logger, Summary: &optionStats}
+	_, err = optionsFn.Handle(context.Background(), id)
+	assert.NoError(t, err)
+
+	assert.Empty(t, mutations)
+	assert.Equal(t, runner.Summary{Count: 1, Unchanged: 1}, productStats)
+	assert.Equal(t, runner.Summary{Count: 1, Unchanged: 1}, optionStats)
+
+	newID, ok := idMap.Resolve(engine.Product, "gid://shopify/Product/1")
+	assert.True(t, ok)
+	assert.Equal(t, "gid://shopify/Product/101", newID)
+
+	// Clean up.
+	assert.NoError(t, os.RemoveAll("./testdata/.tmp"))
+}
@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
 	"github.com/ankitpokhrel/shopctl/internal/api"
+	"github.com/ankitpokhrel/shopctl/internal/engine"
 	"github.com/ankitpokhrel/shopctl/internal/registry"
 	"github.com/ankitpokhrel/shopctl/internal/runner"
+	"github.com/ankitpokhrel/shopctl/pkg/diff"
 	"github.com/ankitpokhrel/shopctl/pkg/tlog"
 	"github.com/ankitpokhrel/shopctl/schema"
 )
@@ -16,10 +19,12 @@ type Variant struct {
 	Logger  *tlog.Logger
 	File    registry.File
 	Summary *runner.Summary
+	IDMap   *runner.IDMap
+	Plan    *runner.Plan
 	DryRun  bool
 }
 
-func (h *Variant) Handle(data any) (any, error) {
+func (h *Variant) Handle(ctx context.Context, data any) (any, error) {
 	var realProductID string
 	if id, ok := data.(string); ok {
 		realProductID = id
@@ -43,9 +48,10 @@ func (h *Variant) Handle(data any) (any, error) {
 	toAdd := make([]*schema.ProductVariant, 0)
 	toUpdate := make([]*schema.ProductVariant, 0)
 	toDelete := make([]string, 0)
+	mappings := make([]runner.IDMapping, 0, len(product.Variants.Nodes))
 
 	// Get upstream variants.
-	currentVariants, _ := h.Client.GetProductVariants(realProductID)
+	currentVariants, _ := h.Client.GetProductVariants(ctx, realProductID)
 	if currentVariants != nil {
 		currentVariantsMap := make(map[string]*schema.ProductVariant, len(currentVariants.Variants.Nodes))
 		for _, v := range currentVariants.Variants.Nodes {
@@ -57,12 +63,46 @@ func (h *Variant) Handle(data any) (any, error) {
 			backupVariantsMap[keyme(v.Title)] = &v
 		}
 
-		for k := range currentVariantsMap {
-			id := currentVariantsMap[k].ID
+		for k, cv := range currentVariantsMap {
+			id := cv.ID
 			if v, ok := backupVariantsMap[k]; ok {
+				changes, err := diff.Compare(getVariantInput(cv), getVariantInput(v))
+				if err != nil {
+					h.Summary.Failed += 1
+					return nil, err
+				}
+				entry := runner.PlanEntry{
+					Resource: engine.ProductVariant, Parent: product.ProductID, Key: v.Title, OldID: v.ID, UpstreamID: id,
+					Action: runner.PlanUpdate, Changes: changes,
+				}
+				if len(changes) == 0 {
+					entry.Action = runner.PlanUnchanged
+				}
+				if err := h.Plan.Record(entry, getVariantInput(cv)); err != nil {
+					h.Summary.Failed += 1
+					return nil, err
+				}
+				if len(changes) == 0 {
+					h.Logger.V(tlog.VL2).Info("Product variant is unchanged, skipping update", "id", id, "title", v.Title)
+					mappings = append(mappings, runner.IDMapping{
+						Resource: engine.ProductVariant, OldID: v.ID, NewID: id, Key: v.Title, Action: runner.ActionUnchanged,
+					})
+					continue
+				}
+				h.Logger.V(tlog.VL2).Info("Product variant has changed", "id", id, "title", v.Title, "fields", diff.Fields(changes))
+				mappings = append(mappings, runner.IDMapping{
+					Resource: engine.ProductVariant, OldID: v.ID, NewID: id, Key: v.Title, Action: runner.ActionUpdated,
+				})
 				v.ID = id
 				toUpdate = append(toUpdate, v)
 			} else {
+				err := h.Plan.Record(runner.PlanEntry{
+					Resource: engine.ProductVariant, Parent: product.ProductID, Key: cv.Title, UpstreamID: id, Action: runner.PlanDelete,
+				}, getVariantInput(cv))
+				if err != nil {
+					h.Summary.Failed += 1
+					return nil, err
+				}
 				toDelete = append(toDelete, id)
 			}
 		}
@@ -76,20 +116,51 @@ func (h *Variant) Handle(data any) (any, error) {
 			toAdd = append(toAdd, &v)
 		}
 	}
+	for _, v := range toAdd {
+		err := h.Plan.Add(runner.PlanEntry{
+			Resource: engine.ProductVariant, Parent: product.ProductID, Key: v.Title, OldID: v.ID, Action: runner.PlanCreate,
+		})
+		if err != nil {
+			h.Summary.Failed += 1
+			return nil, err
+		}
+	}
 
 	attemptSync := func(pid string) error {
-		if _, err := h.handleProductVariantDelete(pid, toDelete); err != nil {
+		if _, err := h.handleProductVariantDelete(ctx, pid, toDelete); err != nil {
 			return err
 		}
-		if _, err := h.handleProductVariantAdd(pid, toAdd); err != nil {
+		res, err := h.handleProductVariantAdd(ctx, pid, toAdd)
+		if err != nil {
 			return err
 		}
-		if _, err := h.handleProductVariantUpdate(pid, toUpdate); err != nil {
+		if res != nil {
+			// Created variants are matched by their title.
+			created := make(map[string]string, len(res.Variants))
+			for _, v := range res.Variants {
+				created[keyme(v.Title)] = v.ID
+			}
+			for _, v := range toAdd {
+				mappings = append(mappings, runner.IDMapping{
+					Resource: engine.ProductVariant, OldID: v.ID, NewID: created[keyme(v.Title)], Key: v.Title, Action: runner.ActionCreated,
+				})
+			}
+		}
+		if _, err := h.handleProductVariantUpdate(ctx, pid, toUpdate); err != nil {
 			return err
 		}
 		return nil
 	}
 
+	if len(toAdd) == 0 && len(toUpdate) == 0 && len(toDelete) == 0 {
+		h.Logger.V(tlog.VL2).Info("Product variants are unchanged, skipping sync", "oldID", product.ProductID, "upstreamID", realProductID)
+		h.Summary.Unchanged += 1
+		for _, mp := range mappings {
+			h.IDMap.Add(mp)
+		}
+		return nil, nil
+	}
+
 	h.Logger.V(1).Info("Attempting to sync product variants", "oldID", product.ProductID, "upstreamID", realProductID)
 	if h.DryRun {
 		h.Logger.V(tlog.VL2).Infof("Product variants to sync - add: %d, update: %d", len(toAdd), len(toUpdate))
@@ -104,66 +175,35 @@ func (h *Variant) Handle(data any) (any, error) {
 		return nil, err
 	}
 	h.Summary.Passed += 1
+	for _, mp := range mappings {
+		h.IDMap.Add(mp)
+	}
 	return nil, nil
 }
 
-func (h Variant) handleProductVariantDelete(productID string, toDelete []string) (*api.ProductVariantsSyncResponse, error) {
+func (h Variant) handleProductVariantDelete(ctx context.Context, productID string, toDelete []string) (*api.ProductVariantsSyncResponse, error) {
 	if len(toDelete) == 0 {
 		return nil, nil
 	}
-	return h.Client.DeleteProductVariants(productID, toDelete)
+	return h.Client.DeleteProductVariants(ctx, productID, toDelete)
 }
 
-func (h Variant) handleProductVariantAdd(productID string, toAdd []*schema.ProductVariant) (*api.ProductVariantsSyncResponse, error) {
-	return h.createOrUpdateProductVariants(productID, toAdd, false)
+func (h Variant) handleProductVariantAdd(ctx context.Context, productID string, toAdd []*schema.ProductVariant) (*api.ProductVariantsSyncResponse, error) {
+	return h.createOrUpdateProductVariants(ctx, productID, toAdd, false)
 }
 
-func (h Variant) handleProductVariantUpdate(productID string, toUpdate []*schema.ProductVariant) (*api.ProductVariantsSyncResponse, error) {
-	return h.createOrUpdateProductVariants(productID, toUpdate, true)
+func (h Variant) handleProductVariantUpdate(ctx context.Context, productID string, toUpdate []*schema.ProductVariant) (*api.ProductVariantsSyncResponse, error) {
+	return h.createOrUpdateProductVariants(ctx, productID, toUpdate, true)
 }
 
-func (h Variant) createOrUpdateProductVariants(productID string, variants []*schema.ProductVariant, isUpdate bool) (*api.ProductVariantsSyncResponse, error) {
+func (h Variant) createOrUpdateProductVariants(ctx context.Context, productID string, variants []*schema.ProductVariant, isUpdate bool) (*api.ProductVariantsSyncResponse, error) {
 	if len(variants) == 0 {
 		return nil, nil
 	}
 
 	variantsInput := make([]schema.ProductVariantsBulkInput, 0, len(variants))
 	for _, v := range variants {
-		var inventoryItem *schema.InventoryItemInput
-
-		if v.InventoryItem != nil {
-			var cost float64
-			if v.InventoryItem.UnitCost != nil {
-				cost = v.InventoryItem.UnitCost.Amount
-			}
-			inventoryItem = &schema.InventoryItemInput{
-				Sku:                  v.InventoryItem.Sku,
-				Cost:                 &cost,
-				CountryCodeOfOrigin:  v.InventoryItem.CountryCodeOfOrigin,
-				HarmonizedSystemCode: v.InventoryItem.HarmonizedSystemCode,
-				ProvinceCodeOfOrigin: v.InventoryItem.ProvinceCodeOfOrigin,
-				Tracked:              &v.InventoryItem.Tracked,
-				Measurement: &schema.InventoryItemMeasurementInput{
-					Weight: &schema.WeightInput{
-						Value: v.InventoryItem.Measurement.Weight.Value,
-						Unit:  v.InventoryItem.Measurement.Weight.Unit,
-					},
-				},
-				RequiresShipping: &v.InventoryItem.RequiresShipping,
-			}
-		}
-
-		input := schema.ProductVariantsBulkInput{
-			Barcode:            v.Barcode,
-			CompareAtPrice:     v.CompareAtPrice,
-			InventoryPolicy:    &v.InventoryPolicy,
-			InventoryItem:      inventoryItem,
-			OptionValues:       getOptions(v.SelectedOptions),
-			Price:              &v.Price,
-			Taxable:            &v.Taxable,
-			TaxCode:            v.TaxCode,
-			RequiresComponents: &v.RequiresComponents,
-		}
+		input := getVariantInput(v)
 		if isUpdate {
 			input.ID = &v.ID
 		}
@@ -172,10 +212,50 @@ func (h Variant) createOrUpdateProductVariants(productID string, variants []*sch
 
 	if isUpdate {
 		h.Logger.V(tlog.VL2).Info("Attempting to update product variant", "id", productID)
-		return h.Client.UpdateProductVariants(productID, variantsInput, false)
+		return h.Client.UpdateProductVariants(ctx, productID, variantsInput, false)
 	}
 	h.Logger.V(tlog.VL2).Info("Attempting to create product variant", "id", productID)
-	return h.Client.CreateProductVariants(productID, variantsInput, schema.ProductVariantsBulkCreateStrategyRemoveStandaloneVariant)
+	return h.Client.CreateProductVariants(ctx, productID, variantsInput, schema.ProductVariantsBulkCreateStrategyRemoveStandaloneVariant)
+}
+
+func getVariantInput(v *schema.ProductVariant) schema.ProductVariantsBulkInput {
+	return schema.ProductVariantsBulkInput{
+		Barcode:            v.Barcode,
+		CompareAtPrice:     v.CompareAtPrice,
+		InventoryPolicy:    &v.InventoryPolicy,
+		InventoryItem:      getInventoryItem(v),
+		OptionValues:       getOptions(v.SelectedOptions),
+		Price:              &v.Price,
+		Taxable:            &v.Taxable,
+		TaxCode:            v.TaxCode,
+		RequiresComponents: &v.RequiresComponents,
+	}
+}
+
+func getInventoryItem(v *schema.ProductVariant) *schema.InventoryItemInput {
+	if v.InventoryItem == nil {
+		return nil
+	}
+
+	var cost float64
+	if v.InventoryItem.UnitCost != nil {
+		cost = v.InventoryItem.UnitCost.Amount
+	}
+	return &schema.InventoryItemInput{
+		Sku:                  v.InventoryItem.Sku,
+		Cost:                 &cost,
+		CountryCodeOfOrigin:  v.InventoryItem.CountryCodeOfOrigin,
+		HarmonizedSystemCode: v.InventoryItem.HarmonizedSystemCode,
+		ProvinceCodeOfOrigin: v.InventoryItem.ProvinceCodeOfOrigin,
+		Tracked:              &v.InventoryItem.Tracked,
+		Measurement: &schema.InventoryItemMeasurementInput{
+			Weight: &schema.WeightInput{
+				Value: v.InventoryItem.Measurement.Weight.Value,
+				Unit:  v.InventoryItem.Measurement.Weight.Unit,
+			},
+		},
+		RequiresShipping: &v.InventoryItem.RequiresShipping,
+	}
 }
 
 func getOptions(options []any) []any {
@@ -1,6 +1,7 @@
 package product
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path/filepath"
@@ -24,11 +25,41 @@ type Runner struct {
 	logger   *tlog.Logger
 	stats    map[engine.ResourceType]*runner.Summary
 	filters  *runner.RestoreFilter
+	idMap    *runner.IDMap
+	plan     *runner.Plan
+	migrate  *runner.Migration
 	isDryRun bool
+
+	skipInventory bool
+	locations     *handler.Locations
+}
+
+// Option is a functional opt for Runner.
+type Option func(*Runner)
+
+// SkipInventory skips restoring inventory quantities of the products.
+func SkipInventory() Option {
+	return func(r *Runner) {
+		r.skipInventory = true
+	}
+}
+
+// WithPlan records the changes the restore would make in the plan.
+func WithPlan(plan *runner.Plan) Option {
+	return func(r *Runner) {
+		r.plan = plan
+	}
+}
+
+// WithMigration resolves the references to store-specific resources on migration to another store.
+func WithMigration(m *runner.Migration) Option {
+	return func(r *Runner) {
+		r.migrate = m
+	}
 }
 
 // NewRunner constructs a new restore runner.
-func NewRunner(path string, eng *engine.Engine, client *api.GQLClient, logger *tlog.Logger, filters *runner.RestoreFilter, isDryRun bool) *Runner {
+func NewRunner(path string, eng *engine.Engine, client *api.GQLClient, logger *tlog.Logger, filters *runner.RestoreFilter, idMap *runner.IDMap, isDryRun bool, opts ...Option) *Runner {
 	rstEng := eng.Doer().(*engine.Restore)
 
 	stats := make(map[engine.ResourceType]*runner.Summary)
@@ -36,16 +67,31 @@ func NewRunner(path string, eng *engine.Engine, client *api.GQLClient, logger *t
 		stats[rt] = &runner.Summary{}
 	}
 
-	return &Runner{
-		path:     path,
-		eng:      eng,
-		rstEng:   rstEng,
-		client:   client,
-		logger:   logger,
-		stats:    stats,
-		filters:  filters,
-		isDryRun: isDryRun,
+	rnr := Runner{
+		path:      path,
+		eng:       eng,
+		rstEng:    rstEng,
+		client:    client,
+		logger:    logger,
+		stats:     stats,
+		filters:   filters,
+		idMap:     idMap,
+		isDryRun:  isDryRun,
+		locations: &handler.Locations{},
 	}
+
+	for _, opt := range opts {
+		opt(&rnr)
+	}
+	if rnr.skipInventory {
+		delete(rnr.stats, engine.ProductInventory)
+	}
+	return &rnr
+}
+
+// FilterFields returns the fields products can be filtered by on restore.
+func FilterFields() []string {
+	return handler.FilterFields.Names()
 }
 
 // Kind returns runner type; implements `runner.Runner` interface.
@@ -59,7 +105,7 @@ func (r *Runner) Stats() map[engine.ResourceType]*runner.Summary {
 }
 
 // Run executes product restoration process; implements `runner.Runner` interface.
-func (r *Runner) Run() error {
+func (r *Runner) Run(ctx context.Context) error {
 	r.eng.Register(engine.Product)
 	restoreStart := time.Now()
 
@@ -70,12 +116,19 @@ func (r *Runner) Run() error {
 		_ = r.restore()
 	}()
 
-	for res := range r.eng.Run(engine.Product) {
+	for res := range r.eng.Run(ctx, engine.Product) {
 		if res.Err != nil && !errors.Is(res.Err, engine.ErrSkipChildren) {
 			r.logger.Errorf("Failed to restore resource %s: %v\n", res.ResourceType, res.Err)
 		}
 	}
 
+	// Resources restored in an earlier run are skipped on resume.
+	for rt, st := range r.stats {
+		n := r.rstEng.Resumed(rt)
+		st.Count += n
+		st.Skipped += n
+	}
+
 	r.logger.V(tlog.VL3).Infof(
 		"Product restore complete in %s",
 		time.Since(restoreStart),
@@ -90,16 +143,17 @@ func (r *Runner) restore() error {
 	}
 
 	// This is the max number of resources we're expecting to process.
-	const maxNumResources = 5
+	const maxNumResources = 6
 
 	// When adding resource to the resource collection we need to maintain
-	// following order: Product -> Options -> Metafields -> Variants -> Media
+	// following order: Product -> Options -> Metafields -> Variants -> Media -> Inventory
 	const (
 		Product = iota
 		Options
 		Metafields
 		Variants
 		Media
+		Inventory
 	)
 
 	// Initialize resources with fixed slots for ordering.
@@ -122,30 +176,39 @@ func (r *Runner) restore() error {
 
 		switch filepath.Base(f.Path) {
 		case "product.json":
-			productFn := &handler.Product{Client: r.client, File: f, Filter: r.filters, Logger: r.logger, Summary: r.stats[engine.Product], DryRun: r.isDryRun}
-			optionsFn := &handler.Option{Client: r.client, File: f, Logger: r.logger, DryRun: r.isDryRun}
+			productFn := &handler.Product{Client: r.client, File: f, Filter: r.filters, Logger: r.logger, Summary: r.stats[engine.Product], IDMap: r.idMap, Plan: r.plan, Migration: r.migrate, DryRun: r.isDryRun}
+			optionsFn := &handler.Option{Client: r.client, File: f, Logger: r.logger, Summary: r.stats[engine.ProductOption], Plan: r.plan, DryRun: r.isDryRun}
 			resources[currentID][Product] = append(
 				resources[currentID][Product],
-				engine.NewResource(engine.Product, r.path, productFn),
-				engine.NewResource(engine.ProductOption, r.path, optionsFn),
+				engine.NewResource(engine.Product, filepath.Dir(f.Path), productFn),
+				engine.NewResource(engine.ProductOption, filepath.Dir(f.Path), optionsFn),
 			)
 		case "product_metafields.json":
-			metafieldFn := &handler.Metafield{Client: r.client, File: f, Logger: r.logger, Summary: r.stats[engine.ProductMetaField], DryRun: r.isDryRun}
+			metafieldFn := &handler.Metafield{Client: r.client, File: f, Logger: r.logger, Summary: r.stats[engine.ProductMetaField], Plan: r.plan, Migration: r.migrate, DryRun: r.isDryRun}
 			resources[currentID][Metafields] = append(
 				resources[currentID][Metafields],
-				engine.NewResource(engine.ProductMetaField, r.path, metafieldFn),
+				engine.NewResource(engine.ProductMetaField, filepath.Dir(f.Path), metafieldFn),
 			)
 		case "product_variants.json":
-			variantFn := &handler.Variant{Client: r.client, File: f, Logger: r.logger, Summary: r.stats[engine.ProductVariant], DryRun: r.isDryRun}
+			variantFn := &handler.Variant{Client: r.client, File: f, Logger: r.logger, Summary: r.stats[engine.ProductVariant], IDMap: r.idMap, Plan: r.plan, DryRun: r.isDryRun}
 			resources[currentID][Variants] = append(
 				resources[currentID][Variants],
-				engine.NewResource(engine.ProductVariant, r.path, variantFn),
+				engine.NewResource(engine.ProductVariant, filepath.Dir(f.Path), variantFn),
 			)
 		case "product_media.json":
-			mediaFn := &handler.Media{Client: r.client, File: f, Logger: r.logger, Summary: r.stats[engine.ProductMedia], DryRun: r.isDryRun}
+			mediaFn := &handler.Media{Client: r.client, File: f, Logger: r.logger, Summary: r.stats[engine.ProductMedia], IDMap: r.idMap, Plan: r.plan, Migration: r.migrate, DryRun: r.isDryRun}
 			resources[currentID][Media] = append(
 				resources[currentID][Media],
-				engine.NewResource(engine.ProductMedia, r.path, mediaFn),
+				engine.NewResource(engine.ProductMedia, filepath.Dir(f.Path), mediaFn),
+			)
+		case "product_inventory.json":
+			if r.skipInventory {
+				continue
+			}
+			inventoryFn := &handler.Inventory{Client: r.client, File: f, Logger: r.logger, Summary: r.stats[engine.ProductInventory], IDMap: r.idMap, Locations: r.locations, Migration: r.migrate, DryRun: r.isDryRun}
+			resources[currentID][Inventory] = append(
+				resources[currentID][Inventory],
+				engine.NewResource(engine.ProductInventory, filepath.Dir(f.Path), inventoryFn),
 			)
 		}
 	}
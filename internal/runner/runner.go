@@ -1,22 +1,67 @@
 package runner
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/ankitpokhrel/shopctl/internal/engine"
+	"github.com/ankitpokhrel/shopctl/pkg/search"
+	"github.com/ankitpokhrel/shopctl/pkg/tlog"
 )
 
 // Runner is a runner interface.
 type Runner interface {
-	Run() error
+	Run(ctx context.Context) error
 	Kind() engine.ResourceType
 	Stats() map[engine.ResourceType]*Summary
 }
 
-// RestoreFilter holds filter expressions for restore operation.
-type RestoreFilter struct {
-	Filters    map[string][]string
-	Separators []string
+// RestoreFilter is a search query to filter the resources to restore with.
+// A nil RestoreFilter matches every resource.
+type RestoreFilter = search.Filter
+
+// NewRestoreFilter parses the restore filter query and checks that it only refers to the given
+// fields. The returned error points at the column of the query if it is invalid. It returns a
+// nil filter if the query is empty.
+func NewRestoreFilter(query string, fields []string) (*RestoreFilter, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, nil
+	}
+
+	f, err := search.ParseFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Validate(fields); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// FilterFields maps the fields a resource can be filtered by on restore to their values.
+type FilterFields[T any] map[string]func(*T) []string
+
+// Names returns the names of the fields in alphabetical order.
+func (f FilterFields[T]) Names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Match reports whether the resource matches the restore filter.
+func (f FilterFields[T]) Match(rf *RestoreFilter, res *T) bool {
+	return rf.Match(func(field string) []string {
+		if fn, ok := f[field]; ok {
+			return fn(res)
+		}
+		return nil
+	})
 }
 
 // Summary aggregate runner stats.
@@ -25,16 +70,82 @@ type Summary struct {
 	Passed  int
 	Failed  int
 	Skipped int
+
+	// Unchanged is the number of resources that already matched
+	// the upstream state, so no mutation was sent for them.
+	Unchanged int
 }
 
 // String implements `fmt.Stringer` interface.
 // TODO: Skipped metrics.
 func (s Summary) String() string {
-	return fmt.Sprintf(`Processed: %d
+	out := fmt.Sprintf(`Processed: %d
 Succeeded: %d
 Skipped: %d
 Failed: %d`,
 		s.Count, s.Passed,
 		s.Skipped, s.Failed,
 	)
+	if s.Unchanged > 0 {
+		out += fmt.Sprintf("\nUnchanged: %d", s.Unchanged)
+	}
+	return out
+}
+
+// StringValues returns the values as strings, e.g. the tags of a resource to filter them by.
+func StringValues(values []any) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		out = append(out, fmt.Sprint(v))
+	}
+	return out
+}
+
+// OptionalValue returns the value as a single value to filter by, or no value if it is nil.
+func OptionalValue(v *string) []string {
+	if v == nil {
+		return nil
+	}
+	return []string{*v}
+}
+
+// DeltaQuery narrows down the search query to the records updated at or after the given time.
+//
+// The comparison is inclusive since the timestamps only have a precision of a second and a record
+// updated within the same second as the last record of the previous export would be missed
+// otherwise. Records that are exported again are deduplicated by their ID.
+func DeltaQuery(query *string, since *time.Time) *string {
+	if since == nil {
+		return query
+	}
+
+	delta := fmt.Sprintf("updated_at:>='%s'", since.UTC().Format(time.RFC3339))
+	if query != nil && *query != "" {
+		delta = fmt.Sprintf("(%s) AND %s", *query, delta)
+	}
+	return &delta
+}
+
+// Watermark records the latest update time seen in an export of the resource. The watermark
+// is not moved if any of the resources failed so that they are retried next time.
+func Watermark(bkpEng *engine.Backup, rt engine.ResourceType, latest time.Time, stats map[engine.ResourceType]*Summary, logger *tlog.Logger) {
+	if latest.IsZero() {
+		return
+	}
+	for _, st := range stats {
+		if st.Failed > 0 {
+			logger.Warnf("Some %ss failed to export, the export watermark won't be updated", rt)
+			return
+		}
+	}
+	bkpEng.SetWatermark(rt, latest)
+}
+
+// LatestTime returns the latest of the current time and the given RFC3339 timestamp.
+func LatestTime(curr time.Time, ts string) time.Time {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil || !t.After(curr) {
+		return curr
+	}
+	return t
 }
@@ -0,0 +1,160 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Change is a change of a single field.
+type Change struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// String implements `fmt.Stringer` interface.
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Field, format(c.Old), format(c.New))
+}
+
+// Option is a functional opt for Compare.
+type Option func(*options)
+
+type options struct {
+	ignore map[string]bool
+}
+
+// Ignore skips the fields with the given names at any depth, including
+// the fields of objects in lists, e.g. volatile fields like `updatedAt`.
+func Ignore(fields ...string) Option {
+	return func(o *options) {
+		for _, f := range fields {
+			o.ignore[f] = true
+		}
+	}
+}
+
+// Compare compares the values field by field and returns the changes needed to turn `from`
+// into `to`, sorted by the field. Nested objects are compared recursively and their fields
+// are reported with a dot separated path, e.g. `seo.title`. Lists are compared as a whole.
+// A missing field, a null field and an empty list are considered equal.
+func Compare(from, to any, opts ...Option) ([]Change, error) {
+	a, err := Normalize(from, opts...)
+	if err != nil {
+		return nil, err
+	}
+	b, err := Normalize(to, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]Change, 0)
+	walk("", a, b, &changes)
+
+	return changes, nil
+}
+
+// Fields returns the name of the changed fields.
+func Fields(changes []Change) []string {
+	fields := make([]string, 0, len(changes))
+	for _, c := range changes {
+		fields = append(fields, c.Field)
+	}
+	return fields
+}
+
+// Normalize converts the value to its generic JSON representation, i.e. the
+// value compared by Compare, without the fields that are ignored.
+func Normalize(v any, opts ...Option) (any, error) {
+	o := options{ignore: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compare value: %w", err)
+	}
+
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("unable to compare value: %w", err)
+	}
+	if len(o.ignore) > 0 {
+		strip(out, o.ignore)
+	}
+	return out, nil
+}
+
+func strip(v any, fields map[string]bool) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			if fields[k] {
+				delete(val, k)
+				continue
+			}
+			strip(item, fields)
+		}
+	case []any:
+		for _, item := range val {
+			strip(item, fields)
+		}
+	}
+}
+
+func walk(path string, a, b any, changes *[]Change) {
+	am, aok := a.(map[string]any)
+	bm, bok := b.(map[string]any)
+
+	if (aok || a == nil) && (bok || b == nil) && (aok || bok) {
+		keys := slices.Collect(maps.Keys(am))
+		for k := range bm {
+			if _, ok := am[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			walk(join(path, k), am[k], bm[k], changes)
+		}
+		return
+	}
+
+	if isEmpty(a) && isEmpty(b) {
+		return
+	}
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, Change{Field: path, Old: a, New: b})
+	}
+}
+
+func isEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+	list, ok := v.([]any)
+	return ok && len(list) == 0
+}
+
+func join(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func format(v any) string {
+	if v == nil {
+		return "null"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
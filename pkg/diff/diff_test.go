@@ -0,0 +1,115 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	type seo struct {
+		Title       *string `json:"title,omitempty"`
+		Description *string `json:"description,omitempty"`
+	}
+	type product struct {
+		Title  string   `json:"title"`
+		Tags   []string `json:"tags"`
+		Seo    *seo     `json:"seo,omitempty"`
+		Vendor *string  `json:"vendor"`
+	}
+
+	title, other := "Shirt", "Red shirt"
+
+	tests := []struct {
+		name     string
+		from     any
+		to       any
+		expected []Change
+	}{
+		{
+			name:     "equal values",
+			from:     product{Title: "Shirt", Tags: []string{"a", "b"}, Seo: &seo{Title: &title}},
+			to:       product{Title: "Shirt", Tags: []string{"a", "b"}, Seo: &seo{Title: &title}},
+			expected: []Change{},
+		},
+		{
+			name:     "null, missing and empty fields are equal",
+			from:     product{Title: "Shirt", Tags: nil, Seo: nil},
+			to:       product{Title: "Shirt", Tags: []string{}, Seo: &seo{}},
+			expected: []Change{},
+		},
+		{
+			name: "changed fields",
+			from: product{Title: "Shirt", Tags: []string{"a", "b"}, Seo: &seo{Title: &title}},
+			to:   product{Title: "Shirt", Tags: []string{"b", "a"}, Seo: &seo{Title: &other}, Vendor: &other},
+			expected: []Change{
+				{Field: "seo.title", Old: "Shirt", New: "Red shirt"},
+				{Field: "tags", Old: []any{"a", "b"}, New: []any{"b", "a"}},
+				{Field: "vendor", Old: nil, New: "Red shirt"},
+			},
+		},
+		{
+			name: "nested object added",
+			from: product{Title: "Shirt"},
+			to:   product{Title: "Shirt", Seo: &seo{Title: &title, Description: &other}},
+			expected: []Change{
+				{Field: "seo.description", Old: nil, New: "Red shirt"},
+				{Field: "seo.title", Old: nil, New: "Shirt"},
+			},
+		},
+		{
+			name: "different type",
+			from: map[string]any{"value": map[string]any{"amount": 1}},
+			to:   map[string]any{"value": 1},
+			expected: []Change{
+				{Field: "value", Old: map[string]any{"amount": float64(1)}, New: float64(1)},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			changes, err := Compare(tc.from, tc.to)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, changes)
+		})
+	}
+}
+
+func TestCompare_Ignore(t *testing.T) {
+	from := map[string]any{
+		"id":        "1",
+		"title":     "Shirt",
+		"updatedAt": "2025-01-01",
+		"options":   []any{map[string]any{"id": "1", "name": "Size"}},
+	}
+	to := map[string]any{
+		"id":        "2",
+		"title":     "Red shirt",
+		"updatedAt": "2025-01-02",
+		"options":   []any{map[string]any{"id": "2", "name": "Size"}},
+	}
+
+	changes, err := Compare(from, to, Ignore("id", "updatedAt"))
+	assert.NoError(t, err)
+	assert.Equal(t, []Change{{Field: "title", Old: "Shirt", New: "Red shirt"}}, changes)
+
+	// Ignored fields are not removed from the compared values.
+	assert.Equal(t, "1", from["id"])
+}
+
+func TestCompare_InvalidValue(t *testing.T) {
+	_, err := Compare(map[string]any{"fn": func() {}}, nil)
+	assert.Error(t, err)
+}
+
+func TestChange_String(t *testing.T) {
+	assert.Equal(t, `seo.title: "Shirt" -> "Red shirt"`, Change{Field: "seo.title", Old: "Shirt", New: "Red shirt"}.String())
+	assert.Equal(t, `vendor: null -> "Nike"`, Change{Field: "vendor", New: "Nike"}.String())
+	assert.Equal(t, `tags: ["a"] -> []`, Change{Field: "tags", Old: []any{"a"}, New: []any{}}.String())
+}
+
+func TestFields(t *testing.T) {
+	changes := []Change{{Field: "seo.title"}, {Field: "tags"}}
+	assert.Equal(t, []string{"seo.title", "tags"}, Fields(changes))
+}
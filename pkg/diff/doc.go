@@ -0,0 +1,15 @@
+// Package diff finds field level differences between two values.
+//
+// Values are compared by their JSON representation, so any value that can be
+// marshalled to JSON can be compared, e.g. a resource and the input to update it.
+//
+// Example usage:
+//
+//	changes, err := diff.Compare(
+//	        map[string]any{"title": "Shirt", "seo": map[string]any{"title": "Shirt"}},
+//	        map[string]any{"title": "Shirt", "seo": map[string]any{"title": "Red shirt"}},
+//	)
+//	fmt.Println(changes[0])  // Output: seo.title: "Shirt" -> "Red shirt"
+//
+// Texts can be compared line by line and printed in the unified diff format with Unified.
+package diff
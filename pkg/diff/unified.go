@@ -0,0 +1,123 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// line is a line of the edit script. Kind is one of ' ', '-' or '+'
+// and a, b are the positions in the old and the new text before the line.
+type line struct {
+	kind byte
+	text string
+	a, b int
+}
+
+// Unified returns the line by line difference of the texts in the unified diff format with
+// the given number of context lines around the changes. It returns an empty string if the
+// texts are equal.
+func Unified(fromName, toName, from, to string, context int) string {
+	script := editScript(split(from), split(to))
+
+	var hunks strings.Builder
+	for i := 0; i < len(script); {
+		if script[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// Changes that are close to each other are shown in the same hunk.
+		end := i
+		for j := i; j < len(script); j++ {
+			if script[j].kind != ' ' {
+				end = j
+			} else if j-end > 2*context {
+				break
+			}
+		}
+		start, stop := max(0, i-context), min(len(script), end+context+1)
+
+		writeHunk(&hunks, script[start:stop])
+		i = stop
+	}
+	if hunks.Len() == 0 {
+		return ""
+	}
+	return fmt.Sprintf("--- %s\n+++ %s\n%s", fromName, toName, hunks.String())
+}
+
+func writeHunk(out *strings.Builder, lines []line) {
+	var aCount, bCount int
+	for _, l := range lines {
+		if l.kind != '+' {
+			aCount++
+		}
+		if l.kind != '-' {
+			bCount++
+		}
+	}
+
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(lines[0].a, aCount), hunkRange(lines[0].b, bCount))
+	for _, l := range lines {
+		out.WriteByte(l.kind)
+		out.WriteString(l.text)
+		out.WriteByte('\n')
+	}
+}
+
+// hunkRange formats the range of the hunk. Lines are numbered from 1, and an
+// empty range refers to the line before it.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// editScript returns the shortest edit script to turn a into b based on their longest common subsequence.
+func editScript(a, b []string) []line {
+	n, m := len(a), len(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	script := make([]line, 0, n+m)
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			script = append(script, line{kind: ' ', text: a[i], a: i, b: j})
+			i++
+			j++
+		case j == m || (i < n && lcs[i+1][j] >= lcs[i][j+1]):
+			script = append(script, line{kind: '-', text: a[i], a: i, b: j})
+			i++
+		default:
+			script = append(script, line{kind: '+', text: b[j], a: i, b: j})
+			j++
+		}
+	}
+	return script
+}
+
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,74 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		context  int
+		expected string
+	}{
+		{
+			name:     "equal texts",
+			from:     "a\nb\nc\n",
+			to:       "a\nb\nc\n",
+			context:  3,
+			expected: "",
+		},
+		{
+			name:    "changed line",
+			from:    "a\nb\nc\nd\ne\n",
+			to:      "a\nb\nx\nd\ne\n",
+			context: 1,
+			expected: `--- old
++++ new
+@@ -2,3 +2,3 @@
+ b
+-c
++x
+ d
+`,
+		},
+		{
+			name:    "separate hunks",
+			from:    "a\nb\nc\nd\ne\nf\ng\n",
+			to:      "x\nb\nc\nd\ne\nf\ny\n",
+			context: 1,
+			expected: `--- old
++++ new
+@@ -1,2 +1,2 @@
+-a
++x
+ b
+@@ -6,2 +6,2 @@
+ f
+-g
++y
+`,
+		},
+		{
+			name:    "new text",
+			from:    "",
+			to:      "a\nb\n",
+			context: 3,
+			expected: `--- old
++++ new
+@@ -0,0 +1,2 @@
++a
++b
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Unified("old", "new", tc.from, tc.to, tc.context))
+		})
+	}
+}
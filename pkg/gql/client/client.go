@@ -56,10 +56,12 @@ type GQLRequest struct {
 
 // Client is a GraphQL client.
 type Client struct {
-	server string
-	token  string
-	http   *retryablehttp.Client
-	logger retryablehttp.LeveledLogger
+	server   string
+	token    string
+	http     *retryablehttp.Client
+	download *retryablehttp.Client
+	logger   retryablehttp.LeveledLogger
+	throttle *throttle
 }
 
 // ClientFunc is a functional option for Client.
@@ -68,9 +70,11 @@ type ClientFunc func(*Client)
 // NewClient creates a new GraphQL client.
 func NewClient(server, token string, opts ...ClientFunc) *Client {
 	c := Client{
-		server: server,
-		token:  token,
-		http:   retryablehttp.NewClient(),
+		server:   server,
+		token:    token,
+		http:     retryablehttp.NewClient(),
+		download: retryablehttp.NewClient(),
+		throttle: newThrottle(),
 	}
 
 	for _, opt := range opts {
@@ -95,6 +99,7 @@ func NewClient(server, token string, opts ...ClientFunc) *Client {
 				Errors []struct {
 					Message string `json:"message"`
 				} `json:"errors"`
+				costExtensions
 			}
 
 			b, err := io.ReadAll(resp.Body)
@@ -108,6 +113,11 @@ func NewClient(server, token string, opts ...ClientFunc) *Client {
 			if err := json.NewDecoder(bytes.NewReader(b)).Decode(&body); err == nil {
 				for _, e := range body.Errors {
 					if strings.EqualFold(e.Message, "Throttled") {
+						var status *ThrottleStatus
+						if body.Extensions != nil && body.Extensions.Cost != nil {
+							status = body.Extensions.Cost.ThrottleStatus
+						}
+						c.throttle.throttled(status)
 						return true, nil
 					}
 				}
@@ -136,6 +146,16 @@ func NewClient(server, token string, opts ...ClientFunc) *Client {
 	c.http.RetryMax = retryMax
 	c.http.RetryWaitMin = minWait
 	c.http.RetryWaitMax = maxWait
+
+	// Files are downloaded from signed urls outside of the GQL API, e.g. bulk operation results
+	// and media. Their response body is not inspected so that large files are streamed to the
+	// caller instead of being read in memory on every attempt.
+	c.download.Logger = c.logger
+	c.download.HTTPClient.Transport = DefaultTransport
+	c.download.CheckRetry = retryablehttp.DefaultRetryPolicy
+	c.download.RetryMax = retryMax
+	c.download.RetryWaitMin = minWait
+	c.download.RetryWaitMax = maxWait
 	return &c
 }
 
@@ -168,6 +188,24 @@ func (c *Client) Request(ctx context.Context, body []byte, headers Header) (*htt
 	return c.http.Do(req.WithContext(ctx))
 }
 
+// Get sends GET request to the given url, e.g. to download a file the server prepared.
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.download.Do(req)
+}
+
+// Head sends HEAD request to the given url, e.g. to check if a file can still be downloaded.
+func (c *Client) Head(ctx context.Context, url string) (*http.Response, error) {
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.download.Do(req)
+}
+
 // Execute sends a GraphQL request and decodes the response to the given result.
 func (c Client) Execute(ctx context.Context, payload GQLRequest, headers Header, result any) error {
 	data, err := json.Marshal(payload)
@@ -175,6 +213,12 @@ func (c Client) Execute(ctx context.Context, payload GQLRequest, headers Header,
 		return fmt.Errorf("failed to marshal GraphQL query: %w", err)
 	}
 
+	// Wait for the expected cost of the query to be available
+	// instead of getting throttled by the server.
+	if err := c.throttle.reserve(ctx, payload.Query); err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+
 	res, err := c.Request(ctx, data, headers)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
@@ -188,9 +232,30 @@ func (c Client) Execute(ctx context.Context, payload GQLRequest, headers Header,
 		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
 	}
 
-	err = json.NewDecoder(res.Body).Decode(result)
+	body, err := io.ReadAll(res.Body)
 	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(body, result); err != nil {
 		return fmt.Errorf("failed to decode response: %w", err)
 	}
+
+	var ext costExtensions
+	if err := json.Unmarshal(body, &ext); err == nil && ext.Extensions != nil {
+		c.throttle.observe(payload.Query, ext.Extensions.Cost)
+	} else {
+		c.throttle.observe(payload.Query, nil)
+	}
 	return nil
 }
+
+// ThrottleStatus returns the query cost budget of the store based on the last response.
+// It returns false if no response with a throttle status was received yet.
+func (c *Client) ThrottleStatus() (ThrottleStatus, bool) {
+	return c.throttle.get()
+}
+
+// CostStats returns the cumulative query cost of the requests sent by the client.
+func (c *Client) CostStats() CostStats {
+	return c.throttle.getStats()
+}
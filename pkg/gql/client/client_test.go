@@ -1,11 +1,13 @@
 package client
 
 import (
+	"bufio"
 	"context"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -59,3 +61,84 @@ func TestClient_Request(t *testing.T) {
 	}()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
+
+func TestClient_ExecuteTracksThrottleStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := os.ReadFile("./testdata/shop.json")
+		assert.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		_, _ = w.Write(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "12345")
+
+	_, ok := client.ThrottleStatus()
+	assert.False(t, ok)
+
+	var out struct {
+		Data struct {
+			Shop struct {
+				Name string `json:"name"`
+			} `json:"shop"`
+		} `json:"data"`
+	}
+	err := client.Execute(context.Background(), GQLRequest{Query: "{ shop { name } }"}, nil, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, "shopify", out.Data.Shop.Name)
+
+	status, ok := client.ThrottleStatus()
+	assert.True(t, ok)
+	assert.Equal(t, float64(2000), status.MaximumAvailable)
+	assert.Equal(t, float64(100), status.RestoreRate)
+	assert.GreaterOrEqual(t, status.CurrentlyAvailable, float64(1999))
+	assert.LessOrEqual(t, status.CurrentlyAvailable, float64(2000))
+
+	stats := client.CostStats()
+	assert.Equal(t, 1, stats.Requests)
+	assert.Equal(t, float64(1), stats.RequestedCost)
+	assert.Equal(t, float64(1), stats.ActualCost)
+}
+
+func TestClient_GetStreamsResponseBody(t *testing.T) {
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"errors":[{"message":"Throttled"}]}` + "\n"))
+		w.(http.Flusher).Flush()
+
+		// The rest of the file is only sent once the first line was read by the caller.
+		<-release
+		_, _ = w.Write([]byte(`{"id":"gid://shopify/Product/1"}` + "\n"))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(server.URL, "12345")
+
+	type result struct {
+		line string
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		res, err := client.Get(context.Background(), server.URL)
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		line, err := bufio.NewReader(res.Body).ReadString('\n')
+		done <- result{line: line, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		assert.NoError(t, r.err)
+		assert.Equal(t, `{"errors":[{"message":"Throttled"}]}`+"\n", r.line)
+	case <-time.After(5 * time.Second):
+		t.Fatal("response body was not streamed")
+	}
+}
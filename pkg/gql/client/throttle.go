@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ThrottleStatus is the state of the store's query cost budget as reported
+// in the `extensions.cost.throttleStatus` of a GraphQL response.
+type ThrottleStatus struct {
+	MaximumAvailable   float64 `json:"maximumAvailable"`
+	CurrentlyAvailable float64 `json:"currentlyAvailable"`
+	RestoreRate        float64 `json:"restoreRate"`
+}
+
+// QueryCost is the cost information returned in the `extensions.cost` of a GraphQL response.
+type QueryCost struct {
+	RequestedQueryCost float64         `json:"requestedQueryCost"`
+	ActualQueryCost    *float64        `json:"actualQueryCost"`
+	ThrottleStatus     *ThrottleStatus `json:"throttleStatus"`
+}
+
+// CostStats is the cumulative query cost of the requests sent by the client.
+type CostStats struct {
+	Requests      int
+	RequestedCost float64
+	ActualCost    float64
+	Throttled     int
+	Waited        time.Duration
+}
+
+// String implements `fmt.Stringer` interface.
+func (s CostStats) String() string {
+	return fmt.Sprintf(`Requests: %d
+Query cost: %.0f (requested %.0f)
+Throttled: %d
+Waited: %s`,
+		s.Requests, s.ActualCost, s.RequestedCost,
+		s.Throttled, s.Waited.Round(time.Millisecond),
+	)
+}
+
+// costExtensions is the extensions part of a GraphQL response.
+type costExtensions struct {
+	Extensions *struct {
+		Cost *QueryCost `json:"cost"`
+	} `json:"extensions"`
+}
+
+// throttle models the store's query cost budget as a leaky bucket. The bucket is refilled with
+// the restore rate of the store and each request takes the cost it needed the last time it was
+// sent. Requests wait for the bucket to refill if their expected cost is not available yet.
+type throttle struct {
+	mux    sync.Mutex
+	status *ThrottleStatus
+	seenAt time.Time
+	costs  map[string]float64
+	stats  CostStats
+}
+
+func newThrottle() *throttle {
+	return &throttle{
+		costs: make(map[string]float64),
+	}
+}
+
+// restore refills the bucket for the time passed since it was last updated.
+func (t *throttle) restore() {
+	if t.status == nil {
+		return
+	}
+	now := time.Now()
+	restored := t.status.RestoreRate * now.Sub(t.seenAt).Seconds()
+
+	t.status.CurrentlyAvailable = min(t.status.MaximumAvailable, t.status.CurrentlyAvailable+restored)
+	t.seenAt = now
+}
+
+// reserve takes the expected cost of the query from the bucket. It blocks until
+// the cost is available or the context is cancelled.
+func (t *throttle) reserve(ctx context.Context, query string) error {
+	for {
+		t.mux.Lock()
+		t.restore()
+
+		cost, known := t.costs[query]
+		if t.status == nil || !known {
+			t.mux.Unlock()
+			return nil
+		}
+		cost = min(cost, t.status.MaximumAvailable)
+
+		if cost <= t.status.CurrentlyAvailable || t.status.RestoreRate <= 0 {
+			t.status.CurrentlyAvailable -= cost
+			t.mux.Unlock()
+			return nil
+		}
+		wait := time.Duration((cost - t.status.CurrentlyAvailable) / t.status.RestoreRate * float64(time.Second))
+		t.stats.Waited += wait
+		t.mux.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// observe records the cost of the query and the throttle status returned by the server.
+func (t *throttle) observe(query string, cost *QueryCost) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.stats.Requests++
+	if cost == nil {
+		return
+	}
+
+	t.stats.RequestedCost += cost.RequestedQueryCost
+	if cost.ActualQueryCost != nil {
+		t.stats.ActualCost += *cost.ActualQueryCost
+	}
+	t.costs[query] = cost.RequestedQueryCost
+
+	if cost.ThrottleStatus != nil {
+		s := *cost.ThrottleStatus
+		t.status = &s
+		t.seenAt = time.Now()
+	}
+}
+
+// throttled records a request that was throttled by the server.
+func (t *throttle) throttled(status *ThrottleStatus) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.stats.Throttled++
+	if status != nil {
+		s := *status
+		t.status = &s
+		t.seenAt = time.Now()
+	}
+}
+
+// get returns the current state of the bucket.
+func (t *throttle) get() (ThrottleStatus, bool) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.restore()
+	if t.status == nil {
+		return ThrottleStatus{}, false
+	}
+	return *t.status, true
+}
+
+func (t *throttle) getStats() CostStats {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	return t.stats
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThrottle_Reserve(t *testing.T) {
+	th := newThrottle()
+
+	// Nothing is known about the store or the query yet.
+	assert.NoError(t, th.reserve(context.Background(), "query"))
+
+	actual := float64(90)
+	th.observe("query", &QueryCost{
+		RequestedQueryCost: 100,
+		ActualQueryCost:    &actual,
+		ThrottleStatus:     &ThrottleStatus{MaximumAvailable: 1000, CurrentlyAvailable: 150, RestoreRate: 1000},
+	})
+
+	// Cost is available and is taken from the bucket.
+	assert.NoError(t, th.reserve(context.Background(), "query"))
+	status, ok := th.get()
+	assert.True(t, ok)
+	assert.InDelta(t, 50, status.CurrentlyAvailable, 10)
+
+	// Next request waits for the bucket to refill.
+	start := time.Now()
+	assert.NoError(t, th.reserve(context.Background(), "query"))
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	// Unknown queries are not held back.
+	start = time.Now()
+	assert.NoError(t, th.reserve(context.Background(), "another query"))
+	assert.Less(t, time.Since(start), 30*time.Millisecond)
+
+	stats := th.getStats()
+	assert.Equal(t, 1, stats.Requests)
+	assert.Equal(t, float64(100), stats.RequestedCost)
+	assert.Equal(t, float64(90), stats.ActualCost)
+	assert.Positive(t, stats.Waited)
+}
+
+func TestThrottle_ReserveCancelled(t *testing.T) {
+	th := newThrottle()
+	th.observe("query", &QueryCost{
+		RequestedQueryCost: 500,
+		ThrottleStatus:     &ThrottleStatus{MaximumAvailable: 1000, CurrentlyAvailable: 0, RestoreRate: 1},
+	})
+	th.throttled(nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, th.reserve(ctx, "query"), context.DeadlineExceeded)
+	assert.Equal(t, 1, th.getStats().Throttled)
+}
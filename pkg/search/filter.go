@@ -0,0 +1,489 @@
+package search
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultField is the field the terms without a field, i.e. free text, are matched against.
+const DefaultField = "default"
+
+// Layouts of the dates that can be compared, e.g. `created_at:>=2025-01-01`.
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", time.DateOnly}
+
+// ParseError is an error in a filter query at the given 1-based column.
+type ParseError struct {
+	Query string
+	Col   int
+	Msg   string
+}
+
+// Error implements `error` interface. The message points at the column of the query.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s at column %d\n  %s\n  %s^", e.Msg, e.Col, e.Query, strings.Repeat(" ", e.Col-1))
+}
+
+// Filter is a search query that is evaluated locally, e.g. against exported resources.
+//
+// It accepts the Shopify search syntax: `field:value` terms with the comparison operators
+// `>`, `>=`, `<` and `<=`, `*` wildcards, negation with `-` or NOT, and grouping with
+// parentheses. Terms are joined with AND and OR, and AND binds tighter than OR. Terms
+// without an operator in between are joined with AND. Unquoted values can also list
+// alternatives separated by commas, e.g. `tag:premium,on-sale`.
+type Filter struct {
+	query string
+	root  node
+}
+
+// ParseFilter parses a filter query. It returns a *ParseError if the query is malformed.
+func ParseFilter(query string) (*Filter, error) {
+	toks, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{query: query, toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t.col, "unexpected %s", t.text)
+	}
+	return &Filter{query: query, root: root}, nil
+}
+
+// Validate checks that the filter refers to the given fields only.
+func (f *Filter) Validate(fields []string) error {
+	var err error
+	walk(f.root, func(t *term) {
+		if err != nil || slices.Contains(fields, t.field) {
+			return
+		}
+		if t.field == DefaultField {
+			err = &ParseError{Query: f.query, Col: t.col, Msg: "free text search is not supported, filter by a field instead, e.g. tag:premium"}
+			return
+		}
+		err = &ParseError{Query: f.query, Col: t.col, Msg: fmt.Sprintf("unsupported field %q, supported fields are: %s", t.field, strings.Join(fields, ", "))}
+	})
+	return err
+}
+
+// Match reports whether a resource matches the filter. The values func returns the values
+// of the given field of the resource. A nil filter matches every resource.
+func (f *Filter) Match(values func(field string) []string) bool {
+	if f == nil {
+		return true
+	}
+	return f.root.match(values)
+}
+
+type node interface {
+	match(values func(field string) []string) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n *andNode) match(values func(string) []string) bool {
+	return n.left.match(values) && n.right.match(values)
+}
+
+type orNode struct{ left, right node }
+
+func (n *orNode) match(values func(string) []string) bool {
+	return n.left.match(values) || n.right.match(values)
+}
+
+type notNode struct{ node node }
+
+func (n *notNode) match(values func(string) []string) bool {
+	return !n.node.match(values)
+}
+
+// term is a single condition, e.g. `tag:premium` or `created_at:>=2025-01-01`.
+type term struct {
+	field string
+	op    string
+	// values are the alternatives the field is matched against.
+	values   []string
+	patterns []*regexp.Regexp
+	col      int
+}
+
+func newTerm(field, op string, values []string, col int) *term {
+	t := term{field: field, op: op, values: values, patterns: make([]*regexp.Regexp, len(values)), col: col}
+	for i, v := range values {
+		if op == "" && strings.Contains(v, "*") {
+			parts := strings.Split(v, "*")
+			for j, p := range parts {
+				parts[j] = regexp.QuoteMeta(p)
+			}
+			t.patterns[i] = regexp.MustCompile("(?is)^" + strings.Join(parts, ".*") + "$")
+		}
+	}
+	return &t
+}
+
+func (t *term) match(values func(string) []string) bool {
+	for _, got := range values(t.field) {
+		for i, want := range t.values {
+			var ok bool
+			switch {
+			case t.patterns[i] != nil:
+				ok = t.patterns[i].MatchString(got)
+			case t.op == "":
+				ok = equal(got, want)
+			default:
+				ok = compare(got, t.op, want)
+			}
+			if ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func walk(n node, fn func(*term)) {
+	switch n := n.(type) {
+	case *andNode:
+		walk(n.left, fn)
+		walk(n.right, fn)
+	case *orNode:
+		walk(n.left, fn)
+		walk(n.right, fn)
+	case *notNode:
+		walk(n.node, fn)
+	case *term:
+		fn(n)
+	}
+}
+
+// equal compares the values case-insensitively. A date matches any time on that day.
+func equal(got, want string) bool {
+	if strings.EqualFold(got, want) {
+		return true
+	}
+	if d, err := time.Parse(time.DateOnly, want); err == nil {
+		if t, ok := parseTime(got); ok {
+			return t.UTC().Format(time.DateOnly) == d.Format(time.DateOnly)
+		}
+	}
+	return false
+}
+
+// compare compares the values as numbers or dates if both of them are, and as strings otherwise.
+func compare(got, op, want string) bool {
+	var c int
+
+	gn, gerr := strconv.ParseFloat(got, 64)
+	wn, werr := strconv.ParseFloat(want, 64)
+	gt, gok := parseTime(got)
+	wt, wok := parseTime(want)
+
+	switch {
+	case gerr == nil && werr == nil:
+		c = cmp.Compare(gn, wn)
+	case gok && wok:
+		c = gt.Compare(wt)
+	default:
+		c = strings.Compare(strings.ToLower(got), strings.ToLower(want))
+	}
+
+	switch op {
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	}
+	return false
+}
+
+func parseTime(s string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokNot
+	tokTerm
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	col  int
+	term *term
+}
+
+type lexer struct {
+	query string
+	rs    []rune
+	pos   int
+}
+
+func lex(query string) ([]token, error) {
+	l := lexer{query: query, rs: []rune(query)}
+
+	toks := make([]token, 0)
+	for l.pos < len(l.rs) {
+		r, col := l.rs[l.pos], l.pos+1
+
+		switch {
+		case unicode.IsSpace(r):
+			l.pos++
+		case r == '(':
+			toks = append(toks, token{kind: tokLParen, text: "'('", col: col})
+			l.pos++
+		case r == ')':
+			toks = append(toks, token{kind: tokRParen, text: "')'", col: col})
+			l.pos++
+		case r == '-' && l.pos+1 < len(l.rs) && !unicode.IsSpace(l.rs[l.pos+1]):
+			toks = append(toks, token{kind: tokNot, text: "'-'", col: col})
+			l.pos++
+		default:
+			tok, err := l.lexTerm()
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, tok)
+		}
+	}
+	return append(toks, token{kind: tokEOF, text: "end of filter", col: len(l.rs) + 1}), nil
+}
+
+func (l *lexer) lexTerm() (token, error) {
+	start := l.pos
+
+	if isQuote(l.rs[start]) {
+		phrase, err := l.lexQuoted()
+		if err != nil {
+			return token{}, err
+		}
+		return token{kind: tokTerm, text: "text", col: start + 1, term: newTerm(DefaultField, "", []string{"*" + phrase + "*"}, start+1)}, nil
+	}
+
+	for l.pos < len(l.rs) && isFieldRune(l.rs[l.pos]) {
+		l.pos++
+	}
+	if l.pos < len(l.rs) && l.rs[l.pos] == ':' {
+		if l.pos == start {
+			return token{}, l.errorf(start+1, "expected a field before ':'")
+		}
+		field := strings.ToLower(string(l.rs[start:l.pos]))
+		l.pos++
+		return l.lexValue(field, start+1)
+	}
+
+	// Free text or an operator.
+	for l.pos < len(l.rs) && !isDelimiter(l.rs[l.pos]) {
+		l.pos++
+	}
+	word := string(l.rs[start:l.pos])
+
+	switch strings.ToUpper(word) {
+	case "AND":
+		return token{kind: tokAnd, text: "AND", col: start + 1}, nil
+	case "OR":
+		return token{kind: tokOr, text: "OR", col: start + 1}, nil
+	case "NOT":
+		return token{kind: tokNot, text: "NOT", col: start + 1}, nil
+	}
+	return token{kind: tokTerm, text: "text", col: start + 1, term: newTerm(DefaultField, "", []string{"*" + word + "*"}, start+1)}, nil
+}
+
+func (l *lexer) lexValue(field string, col int) (token, error) {
+	var op string
+	for _, o := range []string{">=", "<=", ">", "<"} {
+		if strings.HasPrefix(string(l.rs[l.pos:]), o) {
+			op = o
+			l.pos += len(o)
+			break
+		}
+	}
+
+	if l.pos >= len(l.rs) || isDelimiter(l.rs[l.pos]) {
+		return token{}, l.errorf(l.pos+1, "expected a value for %q", field)
+	}
+
+	var values []string
+	if isQuote(l.rs[l.pos]) {
+		v, err := l.lexQuoted()
+		if err != nil {
+			return token{}, err
+		}
+		values = []string{v}
+	} else {
+		start := l.pos
+		for l.pos < len(l.rs) && !isDelimiter(l.rs[l.pos]) {
+			l.pos++
+		}
+		raw := string(l.rs[start:l.pos])
+		if op != "" {
+			values = []string{raw}
+		} else {
+			for _, v := range strings.Split(raw, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					values = append(values, v)
+				}
+			}
+		}
+		if len(values) == 0 {
+			return token{}, l.errorf(start+1, "expected a value for %q", field)
+		}
+	}
+	return token{kind: tokTerm, text: field, col: col, term: newTerm(field, op, values, col)}, nil
+}
+
+// lexQuoted reads a value in single or double quotes. Quotes can be escaped with a backslash.
+func (l *lexer) lexQuoted() (string, error) {
+	var (
+		start = l.pos
+		quote = l.rs[start]
+		out   strings.Builder
+	)
+	for l.pos++; l.pos < len(l.rs); l.pos++ {
+		r := l.rs[l.pos]
+		switch {
+		case r == '\\' && l.pos+1 < len(l.rs):
+			l.pos++
+			out.WriteRune(l.rs[l.pos])
+		case r == quote:
+			l.pos++
+			return out.String(), nil
+		default:
+			out.WriteRune(r)
+		}
+	}
+	return "", l.errorf(start+1, "unterminated quoted value")
+}
+
+func (l *lexer) errorf(col int, format string, args ...any) error {
+	return &ParseError{Query: l.query, Col: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+func isQuote(r rune) bool {
+	return r == '"' || r == '\''
+}
+
+func isFieldRune(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isDelimiter(r rune) bool {
+	return r == '(' || r == ')' || unicode.IsSpace(r)
+}
+
+type parser struct {
+	query string
+	toks  []token
+	pos   int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokAnd:
+			p.next()
+		case tokTerm, tokLParen, tokNot:
+			// Terms without an operator in between are joined with AND.
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{node: n}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokTerm:
+		return t.term, nil
+	case tokLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, p.errorf(t.col, "unclosed '('")
+		}
+		p.next()
+		return n, nil
+	case tokEOF:
+		return nil, p.errorf(t.col, "expected a filter")
+	}
+	return nil, p.errorf(t.col, "unexpected %s", t.text)
+}
+
+func (p *parser) errorf(col int, format string, args ...any) error {
+	return &ParseError{Query: p.query, Col: col, Msg: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,119 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterMatch(t *testing.T) {
+	product := map[string][]string{
+		"id":           {"8737843216608"},
+		"title":        {"Red Cotton Shirt"},
+		"status":       {"DRAFT"},
+		"tag":          {"premium", "on-sale"},
+		"product_type": {"Shirts"},
+		"created_at":   {"2025-02-10T08:30:00Z"},
+		"inventory":    {"42"},
+		"default":      {"Red Cotton Shirt"},
+	}
+	values := func(field string) []string { return product[field] }
+
+	tests := []struct {
+		name     string
+		query    string
+		expected bool
+	}{
+		{name: "equality is case-insensitive", query: "status:draft", expected: true},
+		{name: "value in a list", query: "tag:premium", expected: true},
+		{name: "value not in a list", query: "tag:clearance", expected: false},
+		{name: "comma separated alternatives", query: "id:1,8737843216608,3", expected: true},
+		{name: "quoted value", query: `title:"red cotton shirt"`, expected: true},
+		{name: "single quoted value", query: `title:'Red Cotton Shirt'`, expected: true},
+		{name: "wildcard", query: "title:*cotton*", expected: true},
+		{name: "prefix wildcard", query: "title:blue*", expected: false},
+		{name: "negation", query: "-tag:clearance", expected: true},
+		{name: "NOT", query: "NOT tag:premium", expected: false},
+		{name: "date comparison", query: "created_at:>=2025-01-01", expected: true},
+		{name: "date range", query: "created_at:>2025-01-01 AND created_at:<2025-02-01", expected: false},
+		{name: "timestamp comparison", query: "created_at:<'2025-02-10T09:00:00Z'", expected: true},
+		{name: "date equality matches the day", query: "created_at:2025-02-10", expected: true},
+		{name: "numeric comparison", query: "inventory:>=100", expected: false},
+		{name: "numeric comparison is not lexical", query: "inventory:>9", expected: true},
+		{name: "implicit AND", query: "tag:premium status:active", expected: false},
+		{name: "AND binds tighter than OR", query: "tag:clearance AND status:active OR tag:premium", expected: true},
+		{name: "grouping", query: "tag:clearance AND (status:active OR tag:premium)", expected: false},
+		{name: "negated group", query: "-(tag:clearance OR status:active)", expected: true},
+		{name: "lowercase operators", query: "tag:clearance or tag:premium", expected: true},
+		{name: "free text", query: "cotton", expected: true},
+		{name: "quoted free text", query: `"blue cotton"`, expected: false},
+		{name: "unknown field", query: "vendor:acme", expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := ParseFilter(tc.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, f.Match(values))
+		})
+	}
+
+	// Nil filter matches everything.
+	var f *Filter
+	assert.True(t, f.Match(values))
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		col   int
+		msg   string
+	}{
+		{name: "missing value", query: "tag:premium AND status:", col: 24, msg: `expected a value for "status"`},
+		{name: "missing field", query: ":premium", col: 1, msg: "expected a field before ':'"},
+		{name: "unclosed group", query: "tag:a AND (status:active OR tag:b", col: 11, msg: "unclosed '('"},
+		{name: "unexpected closing parenthesis", query: "tag:a)", col: 6, msg: "unexpected ')'"},
+		{name: "leading operator", query: "AND tag:a", col: 1, msg: "unexpected AND"},
+		{name: "trailing operator", query: "tag:a OR", col: 9, msg: "expected a filter"},
+		{name: "empty group", query: "tag:a AND ()", col: 12, msg: "unexpected ')'"},
+		{name: "unterminated quote", query: `title:"red shirt`, col: 7, msg: "unterminated quoted value"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseFilter(tc.query)
+
+			var perr *ParseError
+			assert.ErrorAs(t, err, &perr)
+			assert.Equal(t, tc.col, perr.Col)
+			assert.Equal(t, tc.msg, perr.Msg)
+		})
+	}
+
+	_, err := ParseFilter("tag:a OR")
+	assert.EqualError(t, err, "expected a filter at column 9\n  tag:a OR\n          ^")
+}
+
+func TestFilterValidate(t *testing.T) {
+	fields := []string{"tag", "title"}
+
+	f, err := ParseFilter("tag:premium AND (title:shirt OR -vendor:acme)")
+	assert.NoError(t, err)
+
+	err = f.Validate(fields)
+
+	var perr *ParseError
+	assert.ErrorAs(t, err, &perr)
+	assert.Equal(t, 34, perr.Col)
+	assert.Equal(t, `unsupported field "vendor", supported fields are: tag, title`, perr.Msg)
+
+	f, err = ParseFilter("tag:premium shirt")
+	assert.NoError(t, err)
+	assert.ErrorAs(t, f.Validate(fields), &perr)
+	assert.Equal(t, 13, perr.Col)
+
+	f, err = ParseFilter("TAG:premium OR title:shirt")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Validate(fields))
+}
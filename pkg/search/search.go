@@ -2,9 +2,16 @@ package search
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 )
 
+var (
+	quotedRegex = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'[^']*'`)
+	fieldRegex  = regexp.MustCompile(`(?:^|[\s(])-?([\w.]+):`)
+)
+
 // Query is a Shopify search query builder.
 type Query struct {
 	conditions []string
@@ -134,3 +141,18 @@ func escape(value string) string {
 	}
 	return value
 }
+
+// Fields returns the fields the given search query filters by in the order they first
+// appear. Terms without a field, e.g. a free text search, are not included.
+func Fields(query string) []string {
+	// Values in quotes may contain anything that looks like a field.
+	query = quotedRegex.ReplaceAllString(query, `""`)
+
+	fields := make([]string, 0)
+	for _, m := range fieldRegex.FindAllStringSubmatch(query, -1) {
+		if !slices.Contains(fields, m[1]) {
+			fields = append(fields, m[1])
+		}
+	}
+	return fields
+}
@@ -81,3 +81,38 @@ func TestQueryBuild(t *testing.T) {
 		})
 	}
 }
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{
+			name:     "single field",
+			query:    "customer_date:>=2025-01-01",
+			expected: []string{"customer_date"},
+		},
+		{
+			name:     "fields in groups and negations",
+			query:    `(tag:vip OR -tag:churned) AND NOT country:"United States" AND updated_at:>'2025-01-01T10:00:00Z'`,
+			expected: []string{"tag", "country", "updated_at"},
+		},
+		{
+			name:     "field like text in quotes",
+			query:    `first_name:"john email:x" AND john`,
+			expected: []string{"first_name"},
+		},
+		{
+			name:     "free text",
+			query:    "john doe",
+			expected: []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Fields(tc.query))
+		})
+	}
+}
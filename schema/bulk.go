@@ -0,0 +1,45 @@
+// Code generated by introspect; EDIT WITH CAUTION.
+
+package schema
+
+type BulkOperation struct {
+	CompletedAt     *string                 `json:"completedAt,omitempty"`
+	CreatedAt       string                  `json:"createdAt"`
+	ErrorCode       *BulkOperationErrorCode `json:"errorCode,omitempty"`
+	FileSize        *string                 `json:"fileSize,omitempty"`
+	ID              string                  `json:"id"`
+	ObjectCount     string                  `json:"objectCount"`
+	PartialDataURL  *string                 `json:"partialDataUrl,omitempty"`
+	Query           string                  `json:"query"`
+	RootObjectCount string                  `json:"rootObjectCount"`
+	Status          BulkOperationStatus     `json:"status"`
+	Type            BulkOperationType       `json:"type"`
+	URL             *string                 `json:"url,omitempty"`
+}
+
+type BulkOperationErrorCode string
+
+const (
+	BulkOperationErrorCodeAccessDenied        BulkOperationErrorCode = "ACCESS_DENIED"
+	BulkOperationErrorCodeInternalServerError BulkOperationErrorCode = "INTERNAL_SERVER_ERROR"
+	BulkOperationErrorCodeTimeout             BulkOperationErrorCode = "TIMEOUT"
+)
+
+type BulkOperationStatus string
+
+const (
+	BulkOperationStatusCanceled  BulkOperationStatus = "CANCELED"
+	BulkOperationStatusCanceling BulkOperationStatus = "CANCELING"
+	BulkOperationStatusCompleted BulkOperationStatus = "COMPLETED"
+	BulkOperationStatusCreated   BulkOperationStatus = "CREATED"
+	BulkOperationStatusExpired   BulkOperationStatus = "EXPIRED"
+	BulkOperationStatusFailed    BulkOperationStatus = "FAILED"
+	BulkOperationStatusRunning   BulkOperationStatus = "RUNNING"
+)
+
+type BulkOperationType string
+
+const (
+	BulkOperationTypeMutation BulkOperationType = "MUTATION"
+	BulkOperationTypeQuery    BulkOperationType = "QUERY"
+)
@@ -0,0 +1,77 @@
+// Code generated by introspect; EDIT WITH CAUTION.
+
+package schema
+
+type Collection struct {
+	Description      string              `json:"description"`
+	DescriptionHtml  string              `json:"descriptionHtml"`
+	Handle           string              `json:"handle"`
+	ID               string              `json:"id"`
+	Image            *Image              `json:"image,omitempty"`
+	LegacyResourceID string              `json:"legacyResourceId"`
+	ProductsCount    *Count              `json:"productsCount,omitempty"`
+	RuleSet          *CollectionRuleSet  `json:"ruleSet,omitempty"`
+	Seo              SEO                 `json:"seo"`
+	SortOrder        CollectionSortOrder `json:"sortOrder"`
+	TemplateSuffix   *string             `json:"templateSuffix,omitempty"`
+	Title            string              `json:"title"`
+	UpdatedAt        string              `json:"updatedAt"`
+}
+
+type CollectionRuleSet struct {
+	AppliedDisjunctively bool             `json:"appliedDisjunctively"`
+	Rules                []CollectionRule `json:"rules"`
+}
+
+type CollectionRule struct {
+	Column    CollectionRuleColumn   `json:"column"`
+	Condition string                 `json:"condition"`
+	Relation  CollectionRuleRelation `json:"relation"`
+}
+
+type CollectionRuleColumn string
+
+const (
+	CollectionRuleColumnTag                        CollectionRuleColumn = "TAG"
+	CollectionRuleColumnTitle                      CollectionRuleColumn = "TITLE"
+	CollectionRuleColumnType                       CollectionRuleColumn = "TYPE"
+	CollectionRuleColumnProductCategoryID          CollectionRuleColumn = "PRODUCT_CATEGORY_ID"
+	CollectionRuleColumnVendor                     CollectionRuleColumn = "VENDOR"
+	CollectionRuleColumnVariantPrice               CollectionRuleColumn = "VARIANT_PRICE"
+	CollectionRuleColumnIsPriceReduced             CollectionRuleColumn = "IS_PRICE_REDUCED"
+	CollectionRuleColumnVariantCompareAtPrice      CollectionRuleColumn = "VARIANT_COMPARE_AT_PRICE"
+	CollectionRuleColumnVariantWeight              CollectionRuleColumn = "VARIANT_WEIGHT"
+	CollectionRuleColumnVariantInventory           CollectionRuleColumn = "VARIANT_INVENTORY"
+	CollectionRuleColumnVariantTitle               CollectionRuleColumn = "VARIANT_TITLE"
+	CollectionRuleColumnProductMetafieldDefinition CollectionRuleColumn = "PRODUCT_METAFIELD_DEFINITION"
+	CollectionRuleColumnVariantMetafieldDefinition CollectionRuleColumn = "VARIANT_METAFIELD_DEFINITION"
+	CollectionRuleColumnProductTaxonomyNodeID      CollectionRuleColumn = "PRODUCT_TAXONOMY_NODE_ID"
+)
+
+type CollectionRuleRelation string
+
+const (
+	CollectionRuleRelationContains    CollectionRuleRelation = "CONTAINS"
+	CollectionRuleRelationEndsWith    CollectionRuleRelation = "ENDS_WITH"
+	CollectionRuleRelationEquals      CollectionRuleRelation = "EQUALS"
+	CollectionRuleRelationGreaterThan CollectionRuleRelation = "GREATER_THAN"
+	CollectionRuleRelationIsNotSet    CollectionRuleRelation = "IS_NOT_SET"
+	CollectionRuleRelationIsSet       CollectionRuleRelation = "IS_SET"
+	CollectionRuleRelationLessThan    CollectionRuleRelation = "LESS_THAN"
+	CollectionRuleRelationNotContains CollectionRuleRelation = "NOT_CONTAINS"
+	CollectionRuleRelationNotEquals   CollectionRuleRelation = "NOT_EQUALS"
+	CollectionRuleRelationStartsWith  CollectionRuleRelation = "STARTS_WITH"
+)
+
+type CollectionSortOrder string
+
+const (
+	CollectionSortOrderAlphaAsc    CollectionSortOrder = "ALPHA_ASC"
+	CollectionSortOrderAlphaDesc   CollectionSortOrder = "ALPHA_DESC"
+	CollectionSortOrderBestSelling CollectionSortOrder = "BEST_SELLING"
+	CollectionSortOrderCreated     CollectionSortOrder = "CREATED"
+	CollectionSortOrderCreatedDesc CollectionSortOrder = "CREATED_DESC"
+	CollectionSortOrderManual      CollectionSortOrder = "MANUAL"
+	CollectionSortOrderPriceAsc    CollectionSortOrder = "PRICE_ASC"
+	CollectionSortOrderPriceDesc   CollectionSortOrder = "PRICE_DESC"
+)
@@ -218,3 +218,262 @@ type FileUpdateInput struct {
 	ReferencesToAdd    []any   `json:"referencesToAdd"`
 	ReferencesToRemove []any   `json:"referencesToRemove"`
 }
+
+type MoneyInput struct {
+	Amount       string       `json:"amount"`
+	CurrencyCode CurrencyCode `json:"currencyCode"`
+}
+
+type MoneyBagInput struct {
+	ShopMoney        MoneyInput  `json:"shopMoney"`
+	PresentmentMoney *MoneyInput `json:"presentmentMoney,omitempty"`
+}
+
+type AttributeInput struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type OrderCreateOrderInput struct {
+	BillingAddress      *MailingAddressInput               `json:"billingAddress,omitempty"`
+	ClosedAt            *string                            `json:"closedAt,omitempty"`
+	Currency            *CurrencyCode                      `json:"currency,omitempty"`
+	CustomAttributes    []AttributeInput                   `json:"customAttributes,omitempty"`
+	Customer            *OrderCreateCustomerInput          `json:"customer,omitempty"`
+	Email               *string                            `json:"email,omitempty"`
+	FinancialStatus     *string                            `json:"financialStatus,omitempty"`
+	FulfillmentStatus   *string                            `json:"fulfillmentStatus,omitempty"`
+	LineItems           []OrderCreateLineItemInput         `json:"lineItems"`
+	Metafields          []MetafieldInput                   `json:"metafields,omitempty"`
+	Name                *string                            `json:"name,omitempty"`
+	Note                *string                            `json:"note,omitempty"`
+	Phone               *string                            `json:"phone,omitempty"`
+	PoNumber            *string                            `json:"poNumber,omitempty"`
+	PresentmentCurrency *CurrencyCode                      `json:"presentmentCurrency,omitempty"`
+	ProcessedAt         *string                            `json:"processedAt,omitempty"`
+	ShippingAddress     *MailingAddressInput               `json:"shippingAddress,omitempty"`
+	ShippingLines       []OrderCreateShippingLineInput     `json:"shippingLines,omitempty"`
+	SourceIdentifier    *string                            `json:"sourceIdentifier,omitempty"`
+	SourceName          *string                            `json:"sourceName,omitempty"`
+	Tags                []any                              `json:"tags,omitempty"`
+	TaxLines            []OrderCreateTaxLineInput          `json:"taxLines,omitempty"`
+	TaxesIncluded       *bool                              `json:"taxesIncluded,omitempty"`
+	Test                *bool                              `json:"test,omitempty"`
+	Transactions        []OrderCreateOrderTransactionInput `json:"transactions,omitempty"`
+}
+
+type OrderCreateCustomerInput struct {
+	ToAssociate *OrderCreateAssociateCustomerAttributesInput `json:"toAssociate,omitempty"`
+}
+
+type OrderCreateAssociateCustomerAttributesInput struct {
+	ID    *string `json:"id,omitempty"`
+	Email *string `json:"email,omitempty"`
+}
+
+type OrderCreateLineItemInput struct {
+	PriceSet         *MoneyBagInput            `json:"priceSet,omitempty"`
+	ProductID        *string                   `json:"productId,omitempty"`
+	Quantity         int                       `json:"quantity"`
+	RequiresShipping *bool                     `json:"requiresShipping,omitempty"`
+	Sku              *string                   `json:"sku,omitempty"`
+	TaxLines         []OrderCreateTaxLineInput `json:"taxLines,omitempty"`
+	Taxable          *bool                     `json:"taxable,omitempty"`
+	Title            *string                   `json:"title,omitempty"`
+	VariantID        *string                   `json:"variantId,omitempty"`
+	VariantTitle     *string                   `json:"variantTitle,omitempty"`
+	Vendor           *string                   `json:"vendor,omitempty"`
+}
+
+type OrderCreateShippingLineInput struct {
+	Code     *string        `json:"code,omitempty"`
+	PriceSet *MoneyBagInput `json:"priceSet,omitempty"`
+	Source   *string        `json:"source,omitempty"`
+	Title    string         `json:"title"`
+}
+
+type OrderCreateTaxLineInput struct {
+	PriceSet *MoneyBagInput `json:"priceSet,omitempty"`
+	Rate     float64        `json:"rate"`
+	Title    string         `json:"title"`
+}
+
+type OrderCreateOrderTransactionInput struct {
+	AmountSet         MoneyBagInput `json:"amountSet"`
+	AuthorizationCode *string       `json:"authorizationCode,omitempty"`
+	Gateway           *string       `json:"gateway,omitempty"`
+	Kind              *string       `json:"kind,omitempty"`
+	ProcessedAt       *string       `json:"processedAt,omitempty"`
+	Status            *string       `json:"status,omitempty"`
+	Test              *bool         `json:"test,omitempty"`
+}
+
+type OrderCreateOptionsInput struct {
+	InventoryBehaviour     *string `json:"inventoryBehaviour,omitempty"`
+	SendFulfillmentReceipt *bool   `json:"sendFulfillmentReceipt,omitempty"`
+	SendReceipt            *bool   `json:"sendReceipt,omitempty"`
+}
+
+type FulfillmentInput struct {
+	LineItemsByFulfillmentOrder []FulfillmentOrderLineItemsInput `json:"lineItemsByFulfillmentOrder"`
+	NotifyCustomer              *bool                            `json:"notifyCustomer,omitempty"`
+	TrackingInfo                *FulfillmentTrackingInput        `json:"trackingInfo,omitempty"`
+}
+
+type FulfillmentOrderLineItemsInput struct {
+	FulfillmentOrderID        string                          `json:"fulfillmentOrderId"`
+	FulfillmentOrderLineItems []FulfillmentOrderLineItemInput `json:"fulfillmentOrderLineItems,omitempty"`
+}
+
+type FulfillmentOrderLineItemInput struct {
+	ID       string `json:"id"`
+	Quantity int    `json:"quantity"`
+}
+
+type FulfillmentTrackingInput struct {
+	Company *string `json:"company,omitempty"`
+	Number  *string `json:"number,omitempty"`
+	URL     *string `json:"url,omitempty"`
+}
+
+type CollectionInput struct {
+	DescriptionHtml *string                 `json:"descriptionHtml,omitempty"`
+	Handle          *string                 `json:"handle,omitempty"`
+	ID              *string                 `json:"id,omitempty"`
+	Image           *ImageInput             `json:"image,omitempty"`
+	RuleSet         *CollectionRuleSetInput `json:"ruleSet,omitempty"`
+	Seo             *SEOInput               `json:"seo,omitempty"`
+	SortOrder       *CollectionSortOrder    `json:"sortOrder,omitempty"`
+	TemplateSuffix  *string                 `json:"templateSuffix,omitempty"`
+	Title           *string                 `json:"title,omitempty"`
+}
+
+type CollectionRuleSetInput struct {
+	AppliedDisjunctively bool                  `json:"appliedDisjunctively"`
+	Rules                []CollectionRuleInput `json:"rules"`
+}
+
+type CollectionRuleInput struct {
+	Column    CollectionRuleColumn   `json:"column"`
+	Condition string                 `json:"condition"`
+	Relation  CollectionRuleRelation `json:"relation"`
+}
+
+type ImageInput struct {
+	AltText *string `json:"altText,omitempty"`
+	ID      *string `json:"id,omitempty"`
+	Src     *string `json:"src,omitempty"`
+}
+
+type InventorySetQuantitiesInput struct {
+	Name                  string                   `json:"name"`
+	Reason                string                   `json:"reason"`
+	ReferenceDocumentURI  *string                  `json:"referenceDocumentUri,omitempty"`
+	IgnoreCompareQuantity bool                     `json:"ignoreCompareQuantity"`
+	Quantities            []InventoryQuantityInput `json:"quantities"`
+}
+
+type InventoryQuantityInput struct {
+	InventoryItemID string `json:"inventoryItemId"`
+	LocationID      string `json:"locationId"`
+	Quantity        int    `json:"quantity"`
+	CompareQuantity *int   `json:"compareQuantity,omitempty"`
+}
+
+type ProductSetInput struct {
+	Category               *string                  `json:"category,omitempty"`
+	CombinedListingRole    *CombinedListingsRole    `json:"combinedListingRole,omitempty"`
+	DescriptionHtml        *string                  `json:"descriptionHtml,omitempty"`
+	Files                  []FileSetInput           `json:"files,omitempty"`
+	GiftCard               *bool                    `json:"giftCard,omitempty"`
+	GiftCardTemplateSuffix *string                  `json:"giftCardTemplateSuffix,omitempty"`
+	Handle                 *string                  `json:"handle,omitempty"`
+	ID                     *string                  `json:"id,omitempty"`
+	Metafields             []MetafieldInput         `json:"metafields,omitempty"`
+	ProductOptions         []OptionSetInput         `json:"productOptions,omitempty"`
+	ProductType            *string                  `json:"productType,omitempty"`
+	RedirectNewHandle      *bool                    `json:"redirectNewHandle,omitempty"`
+	RequiresSellingPlan    *bool                    `json:"requiresSellingPlan,omitempty"`
+	Seo                    *SEOInput                `json:"seo,omitempty"`
+	Status                 *ProductStatus           `json:"status,omitempty"`
+	Tags                   []any                    `json:"tags"`
+	TemplateSuffix         *string                  `json:"templateSuffix,omitempty"`
+	Title                  *string                  `json:"title,omitempty"`
+	Variants               []ProductVariantSetInput `json:"variants,omitempty"`
+	Vendor                 *string                  `json:"vendor,omitempty"`
+}
+
+type ProductSetIdentifiers struct {
+	CustomID *string `json:"customId,omitempty"`
+	Handle   *string `json:"handle,omitempty"`
+	ID       *string `json:"id,omitempty"`
+}
+
+type OptionSetInput struct {
+	ID       *string               `json:"id,omitempty"`
+	Name     *string               `json:"name,omitempty"`
+	Position *int                  `json:"position,omitempty"`
+	Values   []OptionValueSetInput `json:"values,omitempty"`
+}
+
+type OptionValueSetInput struct {
+	ID   *string `json:"id,omitempty"`
+	Name *string `json:"name,omitempty"`
+}
+
+type ProductVariantSetInput struct {
+	Barcode            *string                        `json:"barcode,omitempty"`
+	CompareAtPrice     *string                        `json:"compareAtPrice,omitempty"`
+	ID                 *string                        `json:"id,omitempty"`
+	InventoryItem      *InventoryItemInput            `json:"inventoryItem,omitempty"`
+	InventoryPolicy    *ProductVariantInventoryPolicy `json:"inventoryPolicy,omitempty"`
+	Metafields         []MetafieldInput               `json:"metafields,omitempty"`
+	OptionValues       []any                          `json:"optionValues"`
+	Position           *int                           `json:"position,omitempty"`
+	Price              *string                        `json:"price,omitempty"`
+	RequiresComponents *bool                          `json:"requiresComponents,omitempty"`
+	Taxable            *bool                          `json:"taxable,omitempty"`
+	TaxCode            *string                        `json:"taxCode,omitempty"`
+}
+
+type FileSetInput struct {
+	Alt            *string          `json:"alt,omitempty"`
+	ContentType    *FileContentType `json:"contentType,omitempty"`
+	Filename       *string          `json:"filename,omitempty"`
+	ID             *string          `json:"id,omitempty"`
+	OriginalSource *string          `json:"originalSource,omitempty"`
+}
+
+type FileContentType string
+
+const (
+	FileContentTypeExternalVideo FileContentType = "EXTERNAL_VIDEO"
+	FileContentTypeFile          FileContentType = "FILE"
+	FileContentTypeImage         FileContentType = "IMAGE"
+	FileContentTypeModel3d       FileContentType = "MODEL_3D"
+	FileContentTypeVideo         FileContentType = "VIDEO"
+)
+
+type StagedUploadInput struct {
+	FileSize   *string                                  `json:"fileSize,omitempty"`
+	Filename   string                                   `json:"filename"`
+	HttpMethod *StagedUploadHttpMethodType              `json:"httpMethod,omitempty"`
+	MimeType   string                                   `json:"mimeType"`
+	Resource   StagedUploadTargetGenerateUploadResource `json:"resource"`
+}
+
+type StagedUploadHttpMethodType string
+
+const (
+	StagedUploadHttpMethodTypePost StagedUploadHttpMethodType = "POST"
+	StagedUploadHttpMethodTypePut  StagedUploadHttpMethodType = "PUT"
+)
+
+type StagedUploadTargetGenerateUploadResource string
+
+const (
+	StagedUploadTargetGenerateUploadResourceBulkMutationVariables StagedUploadTargetGenerateUploadResource = "BULK_MUTATION_VARIABLES"
+	StagedUploadTargetGenerateUploadResourceImage                 StagedUploadTargetGenerateUploadResource = "IMAGE"
+	StagedUploadTargetGenerateUploadResourceVideo                 StagedUploadTargetGenerateUploadResource = "VIDEO"
+	StagedUploadTargetGenerateUploadResourceModel3d               StagedUploadTargetGenerateUploadResource = "MODEL_3D"
+)